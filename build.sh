@@ -1 +1,3 @@
-cometbft init --home /tmp/cometbft-home
+# Put a genesis.json into genesis/ first to ship it in the executable.
+# The CometBFT home directory is initialised on first run.
+go build -o openmesh-core
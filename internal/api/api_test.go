@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeNode accepts transactions with a valid signature and includes them at height 7
+type fakeNode struct {
+	accounts map[string]*types.Account
+	txs      []*types.Transaction
+}
+
+func (n *fakeNode) check(raw cmttypes.Tx) abcitypes.ResponseCheckTx {
+	tx := &types.Transaction{}
+	if err := proto.Unmarshal(raw, tx); err != nil || types.VerifySignature(tx) != nil {
+		return abcitypes.ResponseCheckTx{Code: 2, Log: "invalid signature"}
+	}
+	n.txs = append(n.txs, tx)
+	return abcitypes.ResponseCheckTx{}
+}
+
+func (n *fakeNode) BroadcastTxSync(_ context.Context, tx cmttypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	res := n.check(tx)
+	return &ctypes.ResultBroadcastTx{Code: res.Code, Log: res.Log, Hash: tx.Hash()}, nil
+}
+
+func (n *fakeNode) BroadcastTxCommit(_ context.Context, tx cmttypes.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	res := &ctypes.ResultBroadcastTxCommit{CheckTx: n.check(tx), Hash: tx.Hash()}
+	if res.CheckTx.Code == 0 {
+		res.Height = 7
+		res.TxResult = abcitypes.ExecTxResult{Events: []abcitypes.Event{{Type: "transfer"}}}
+	}
+	return res, nil
+}
+
+func (n *fakeNode) ABCIQuery(_ context.Context, path string, _ cmtbytes.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	account, ok := n.accounts[strings.TrimPrefix(path, pathAccount)]
+	if !ok {
+		account = &types.Account{}
+	}
+	value, err := proto.Marshal(account)
+	return &ctypes.ResultABCIQuery{Response: abcitypes.ResponseQuery{Value: value}}, err
+}
+
+func TestBroadcast(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	owner := types.Ed25519Owner(key.Public().(ed25519.PublicKey))
+	node := &fakeNode{accounts: map[string]*types.Account{owner: {Balance: 10, Nonce: 3}}}
+	server := httptest.NewServer(NewInstance(node, "").server.Handler)
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	account, err := client.Account(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), account.Nonce)
+	_, err = client.Account("not an owner")
+	assert.ErrorContains(t, err, "400 Bad Request")
+
+	tx := types.NewStakingTransaction(account.Nonce, types.StakingAction_Join, 5)
+	assert.NoError(t, types.SignEd25519(tx, key))
+	res, err := client.Broadcast(tx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), res.CheckTx.Code)
+	assert.Equal(t, int64(7), res.Height)
+	assert.Equal(t, "transfer", res.TxResult.Events[0].Type)
+	assert.True(t, proto.Equal(tx, node.txs[0]))
+
+	// Without waiting there is no block yet, and a tampered transaction is rejected by the mempool
+	tx.GetStakingData().Amount = 6
+	res, err = client.Broadcast(tx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), res.CheckTx.Code)
+	assert.Zero(t, res.Height)
+	assert.Nil(t, res.TxResult)
+
+	resp, err := http.Post(server.URL+pathTx, "application/json", strings.NewReader("{"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, err = http.Get(server.URL + pathTx)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Client submits transactions through the API of a node
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient returns a client of the API at the address, such as 127.0.0.1:26680 or http://127.0.0.1:26680
+func NewClient(addr string) *Client {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	// Waiting for a block takes up to CometBFT's timeout_broadcast_tx_commit
+	return &Client{baseURL: strings.TrimSuffix(addr, "/"), http: &http.Client{Timeout: time.Minute}}
+}
+
+// Account returns the committed account of an owner, its nonce is the one the next transaction must use
+func (c *Client) Account(owner string) (*types.Account, error) {
+	res, err := c.http.Get(c.baseURL + pathAccount + url.PathEscape(owner))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := readBody(res)
+	if err != nil {
+		return nil, err
+	}
+	account := &types.Account{}
+	return account, protojson.Unmarshal(body, account)
+}
+
+// Broadcast submits a signed transaction, and waits for the block including it if wait is set
+func (c *Client) Broadcast(tx *types.Transaction, wait bool) (*TxResult, error) {
+	body, err := protojson.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.http.Post(fmt.Sprintf("%s%s?wait=%t", c.baseURL, pathTx, wait), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err = readBody(res)
+	if err != nil {
+		return nil, err
+	}
+	result := &TxResult{}
+	return result, json.Unmarshal(body, result)
+}
+
+// readBody returns the body of a successful response, or the error the API answered with
+func readBody(res *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		failure := &apiError{}
+		if json.Unmarshal(body, failure) != nil || failure.Error == "" {
+			return nil, fmt.Errorf("API answered %s", res.Status)
+		}
+		return nil, fmt.Errorf("API answered %s: %s", res.Status, failure.Error)
+	}
+	return body, nil
+}
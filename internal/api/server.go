@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pathTx      = "/tx"       // POST a signed Transaction as protobuf JSON, ?wait=true to wait for its block
+	pathAccount = "/account/" // GET /account/{owner} -> Account, to find the next nonce
+
+	// maxTxBody bounds the size of a submitted transaction
+	maxTxBody = 1 << 20
+)
+
+// Node is what the API needs from CometBFT, the local client of the node outside of tests
+type Node interface {
+	BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*ctypes.ResultBroadcastTx, error)
+	BroadcastTxCommit(ctx context.Context, tx cmttypes.Tx) (*ctypes.ResultBroadcastTxCommit, error)
+	ABCIQuery(ctx context.Context, path string, data cmtbytes.HexBytes) (*ctypes.ResultABCIQuery, error)
+}
+
+// TxResult is the answer to a submitted transaction. Height and TxResult are only set when waiting for the block.
+type TxResult struct {
+	Hash     string                    `json:"hash"`
+	CheckTx  abcitypes.ResponseCheckTx `json:"checkTx"`
+	Height   int64                     `json:"height,omitempty"`
+	TxResult *abcitypes.ExecTxResult   `json:"txResult,omitempty"`
+}
+
+// Instance is the node-local API other processes submit transactions through
+type Instance struct {
+	node   Node
+	server *http.Server
+}
+
+// NewInstance initialise an API serving the node on the listen address, such as 127.0.0.1:26680
+func NewInstance(node Node, listenAddr string) *Instance {
+	i := &Instance{node: node}
+	mux := http.NewServeMux()
+	mux.HandleFunc(pathTx, i.handleTx)
+	mux.HandleFunc(pathAccount, i.handleAccount)
+	i.server = &http.Server{Addr: listenAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return i
+}
+
+// Start listening, requests are served in the background
+func (i *Instance) Start() error {
+	listener, err := net.Listen("tcp", i.server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := i.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Transaction API stopped: %s", err.Error())
+		}
+	}()
+	logger.Infof("Transaction API listening on %s", listener.Addr())
+	return nil
+}
+
+// Stop the API, waiting for the requests being served
+func (i *Instance) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return i.server.Shutdown(ctx)
+}
+
+// handleTx broadcasts a signed transaction. It is checked by the mempool like any other, the API does not sign anything.
+func (i *Instance) handleTx(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s is not supported", r.Method, pathTx))
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTxBody+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(body) > maxTxBody {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("transaction is over %d bytes", maxTxBody))
+		return
+	}
+	tx := &types.Transaction{}
+	if err := protojson.Unmarshal(body, tx); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	raw, err := proto.Marshal(tx)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if r.URL.Query().Get("wait") == "true" {
+		res, err := i.node.BroadcastTxCommit(r.Context(), raw)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, &TxResult{Hash: res.Hash.String(), CheckTx: res.CheckTx, Height: res.Height, TxResult: &res.TxResult})
+		return
+	}
+	res, err := i.node.BroadcastTxSync(r.Context(), raw)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &TxResult{
+		Hash:    res.Hash.String(),
+		CheckTx: abcitypes.ResponseCheckTx{Code: res.Code, Data: res.Data, Log: res.Log, Codespace: res.Codespace},
+	})
+}
+
+// handleAccount answers with the committed account of an owner
+func (i *Instance) handleAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s is not supported", r.Method, pathAccount))
+		return
+	}
+	owner := strings.TrimPrefix(r.URL.Path, pathAccount)
+	if _, _, err := types.ParseOwner(owner); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	res, err := i.node.ABCIQuery(r.Context(), pathAccount+owner, nil)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if res.Response.Code != abcitypes.CodeTypeOK {
+		writeError(w, http.StatusBadRequest, errors.New(res.Response.Log))
+		return
+	}
+	account := &types.Account{}
+	if err := proto.Unmarshal(res.Response.Value, account); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(account)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// apiError is the body of every failed request
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &apiError{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Errorf("Failed to write API response: %s", err.Error())
+	}
+}
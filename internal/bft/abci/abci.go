@@ -3,8 +3,9 @@ package verificationApp
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/dgraph-io/badger/v3"
 	"google.golang.org/protobuf/proto"
 	"log"
@@ -15,231 +16,418 @@ import (
 type VerificationApp struct {
 	db           *badger.DB
 	onGoingBlock *badger.Txn
+	height       int64 // Height of the block being finalised
+	chainID      string
+
+	// Where ExtendVote takes the summaries of this validator from, and the extension it made for extensionHeight.
+	// ExtendVote runs again when a height needs several rounds, those votes must carry the same summaries.
+	summarySource   SummarySource
+	extension       []byte
+	extensionHeight int64
+
+	// Next nonce of each owner with transactions in the mempool, and the fees they pay, see checkPendingNonce
+	pendingNonces map[string]uint64
+	pendingFees   map[string]uint64
+
+	// Owners whose stake changed in the block being finalised, see validatorUpdates
+	stakeChanges map[string]bool
+	// Proposals voted on in the block being finalised, see tallyProposals
+	votedProposals map[uint64]bool
+	// Events of the transaction or block being executed, see emit
+	events []abcitypes.Event
+
+	snapshots *snapshotStore // Nil unless EnableSnapshots was called
+	restore   *restoreState  // Snapshot being restored by state sync
+
+	historyBlocks int64  // Past blocks whose state stays queryable, 0 for all of them
+	minGasPrice   uint64 // Fee per gas the mempool requires, see SetMinGasPrice
+
+	// CID of the binary this node runs, and what to call when it must stop for an upgrade, see SetVersion
+	version        string
+	upgradeHandler UpgradeHandler
 }
 
 var _ abcitypes.Application = (*VerificationApp)(nil)
 
 func (app *VerificationApp) FinalizeBlock(_ context.Context, req *abcitypes.RequestFinalizeBlock) (*abcitypes.ResponseFinalizeBlock, error) {
 	var txs = make([]*abcitypes.ExecTxResult, len(req.Txs))
+	if err := app.checkVersion(req.Height); err != nil {
+		return nil, err
+	}
+
+	app.onGoingBlock = app.db.NewTransactionAt(latestVersion, true)
+	app.height = req.Height
+	app.stakeChanges = make(map[string]bool)
+	app.votedProposals = make(map[uint64]bool)
+	if err := applyDueStakingChanges(app.onGoingBlock, req.Height); err != nil {
+		log.Panicf("Error writing to database, unable to apply staking changes: %v", err)
+	}
+	if err := advanceSummaryWindow(app.onGoingBlock, req.Height, req.Time); err != nil {
+		log.Panicf("Error writing to database, unable to advance the summary window: %v", err)
+	}
+	if err := applyPassedProposals(app.onGoingBlock); err != nil {
+		log.Panicf("Error writing to database, unable to apply passed proposals: %v", err)
+	}
+	app.events = nil
+	if err := app.handleCommitInfo(app.onGoingBlock, req.DecidedLastCommit, req.Misbehavior); err != nil {
+		log.Panicf("Error writing to database, unable to punish misbehaviour: %v", err)
+	}
+	blockEvents := app.takeEvents()
 
-	app.onGoingBlock = app.db.NewTransaction(true)
 	for i, tx := range req.Txs {
-		if code := app.isValid(tx); code != 0 {
+		result := app.ExecuteTransaction(tx)
+		// A rejected transaction only paid its fee, the events of what it did not do are already dropped
+		result.Events = app.takeEvents()
+		if result.Code != CodeTypeOK {
 			log.Printf("Error: invalid transaction index %v", i)
-			txs[i] = &abcitypes.ExecTxResult{Code: code}
-		} else {
-			parts := bytes.SplitN(tx, []byte("="), 2)
-			key, value := parts[0], parts[1]
-			log.Printf("Adding key %s with value %s", key, value)
-
-			if err := app.onGoingBlock.Set(key, value); err != nil {
-				log.Panicf("Error writing to database, unable to execute tx: %v", err)
-			}
-
-			log.Printf("Successfully added key %s with value %s", key, value)
-
-			txs[i] = &abcitypes.ExecTxResult{}
 		}
+		txs[i] = result
+	}
+
+	if err := app.tallyProposals(app.onGoingBlock); err != nil {
+		log.Panicf("Error writing to database, unable to tally proposals: %v", err)
+	}
+	if err := app.distributeRewards(app.onGoingBlock); err != nil {
+		log.Panicf("Error writing to database, unable to distribute rewards: %v", err)
+	}
+	blockEvents = append(blockEvents, app.takeEvents()...)
+	validatorUpdates, err := app.validatorUpdates(app.onGoingBlock)
+	if err != nil {
+		log.Panicf("Error writing to database, unable to update validators: %v", err)
 	}
 
-	// Run callback that stops collecting the code, and sends result as a transaction.
-	{
-		//
+	// The hash of this block seeds the collector assignment of the next one
+	if err := setKey(app.onGoingBlock, lastBlockHashKey, req.Hash); err != nil {
+		log.Panicf("Error writing to database, unable to save block hash: %v", err)
 	}
 
-	// Determine new order in block deterministically.
-	{
-		//
+	appHash, err := computeAppHash(app.onGoingBlock)
+	if err != nil {
+		log.Panicf("Error computing app hash: %v", err)
+	}
+	if err := saveCommitInfo(app.onGoingBlock, req.Height, appHash); err != nil {
+		log.Panicf("Error writing to database, unable to save commit info: %v", err)
 	}
 
 	return &abcitypes.ResponseFinalizeBlock{
-		TxResults: txs,
+		Events:           blockEvents,
+		TxResults:        txs,
+		ValidatorUpdates: validatorUpdates,
+		AppHash:          appHash,
 	}, nil
 }
 
-func (app *VerificationApp) Query(_ context.Context, req *abcitypes.RequestQuery) (*abcitypes.ResponseQuery, error) {
-	resp := abcitypes.ResponseQuery{Key: req.Data}
+// ExecuteTransaction applies a transaction to the block being finalised and returns its result: its code, why it was
+// rejected, and its gas. A transaction is executed once its nonce is the next one and its owner can pay the fee.
+// It then pays the fee and uses up its nonce even if it fails, the failure leaving the rest of the state untouched.
+// A transaction that can't be executed leaves the state untouched, its nonce included.
+func (app *VerificationApp) ExecuteTransaction(tx []byte) *abcitypes.ExecTxResult {
+	transaction, code, reason := decodeTransaction(tx)
+	if code != CodeTypeOK {
+		return &abcitypes.ExecTxResult{Code: code, Log: reason}
+	}
+	gas := types.Gas(transaction)
+	result := func(code uint32, reason string) *abcitypes.ExecTxResult {
+		return &abcitypes.ExecTxResult{Code: code, Log: reason, GasWanted: gas}
+	}
 
-	dbErr := app.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(req.Data)
-		if err != nil {
-			if err != badger.ErrKeyNotFound {
-				return err
-			}
-			resp.Log = "key does not exist"
-			return nil
-		}
-
-		return item.Value(func(val []byte) error {
-			resp.Log = "exists"
-			resp.Value = val
-			return nil
-		})
-	})
-	if dbErr != nil {
-		log.Panicf("Error reading database, unable to execute query: %v", dbErr)
+	// Proposer transactions are not sent by an account, they are checked against the state instead
+	switch transaction.Type {
+	case types.TransactionType_AttestationTransaction:
+		return result(app.handleAttestationTransaction(app.onGoingBlock, transaction.GetAttestationData()))
+	case types.TransactionType_AssignmentTransaction:
+		return result(app.handleAssignmentTransaction(app.onGoingBlock, tx))
 	}
-	return &resp, nil
-}
 
-func (app *VerificationApp) ExecuteTransaction(tx []byte) uint32 {
-	var transaction types.Transaction
-	err := proto.Unmarshal(tx, &transaction)
+	account, err := getAccount(app.onGoingBlock, transaction.Owner)
 	if err != nil {
-		fmt.Println("Error unmarshaling transaction data:", err)
-		return 1
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if code := checkNonce(account.Nonce, transaction.Nonce); code != CodeTypeOK {
+		return result(reject(code, "Wrong nonce for %s: expected %d, got %d", transaction.Owner, account.Nonce, transaction.Nonce))
+	}
+
+	// The fee comes first, the transaction can only spend what is left
+	if code, reason := chargeFee(app.onGoingBlock, transaction.Owner, transaction.Fee); code != CodeTypeOK {
+		return result(code, reason)
 	}
 
 	switch transaction.Type {
 	case types.TransactionType_NormalTransaction:
-		normalData := &types.NormalTransactionData{}
-		normalData = transaction.GetNormalData()
-		if err != nil {
-			fmt.Println("Error unmarshaling normal transaction data:", err)
-			return 1
-		}
-		res := app.handleNormalTransaction(*normalData)
-		if res != 0 {
-			fmt.Println("Error occured in executing transaction")
-			return 1
-		}
-		return 0
-		fmt.Println("Normal Transaction Data:", normalData)
+		code, reason = app.handleNormalTransaction(app.onGoingBlock, transaction.Owner, transaction.GetNormalData())
 	case types.TransactionType_VerificationTransaction:
-		verificationData := &types.VerificationTransactionData{}
-		verificationData = transaction.GetVerificationData()
-		res := app.handleVerificationTransaction(*verificationData)
-		if res != 0 {
-			fmt.Println("Error occured in executing transaction")
-			return 1
-		}
-		return 0
-		fmt.Println("Verification Transaction Data:", verificationData)
+		code, reason = app.handleVerificationTransaction(app.onGoingBlock, transaction.Owner, transaction.GetVerificationData())
 	case types.TransactionType_ResourceTransaction:
-		resourceData := &types.ResourceTransactionData{}
-		err := transaction.GetResourceData()
-		if err != nil {
-			fmt.Println("Error unmarshaling resource transaction data:", err)
-			return 1
-		}
-		res := app.handleResourceTransaction(*resourceData)
-		if res != 0 {
-			fmt.Println("Error occured in executing transaction")
-			return 1
-		}
-		return 0
-		fmt.Println("Resource Transaction Data:", resourceData)
+		code, reason = app.handleResourceTransaction(app.onGoingBlock, transaction.Owner, transaction.GetResourceData())
+	case types.TransactionType_StakingTransaction:
+		code, reason = app.handleStakingTransaction(app.onGoingBlock, transaction.Owner, transaction.GetStakingData())
+	case types.TransactionType_GovernanceTransaction:
+		code, reason = app.handleGovernanceTransaction(app.onGoingBlock, transaction.Owner, transaction.GetGovernanceData())
+	case types.TransactionType_UpgradeTransaction:
+		code, reason = app.handleUpgradeTransaction(app.onGoingBlock, transaction.Owner, transaction.GetUpgradeData())
 	default:
-		fmt.Println("Unknown transaction type")
-		return 1
+		// decodeTransaction already rejects unknown types
+		code, reason = CodeTypeInvalidTxType, "Unknown transaction type"
 	}
-	return 0
+	if code != CodeTypeOK {
+		// Nothing a failed transaction emitted happened
+		app.takeEvents()
+	}
+	app.payFee(app.onGoingBlock, transaction.Owner, transaction.Fee)
+
+	// The handler may have changed the balance, so read the account again before bumping its nonce
+	account, err = getAccount(app.onGoingBlock, transaction.Owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	account.Nonce++
+	if err := setAccount(app.onGoingBlock, transaction.Owner, account); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	res := result(code, reason)
+	res.GasUsed = gas
+	return res
 }
 
-func (app *VerificationApp) isValid(tx []byte) uint32 {
+// decodeTransaction parses a raw transaction and checks everything that does not depend on the state:
+// its encoding, that the payload matches the type, and that it is signed by its owner.
+func decodeTransaction(tx []byte) (*types.Transaction, uint32, string) {
 	// check format
 	var transaction types.Transaction
 	err := proto.Unmarshal(tx, &transaction)
 	if err != nil {
-		fmt.Println("Error unmarshaling transaction data:", err)
-		return 1
+		code, reason := reject(CodeTypeEncodingError, "Error unmarshaling transaction data: %v", err)
+		return nil, code, reason
 	}
 
-	// Check the transaction type and handle accordingly
+	// Reordered or duplicated fields decode to the same transaction and keep the signature valid,
+	// only accept the canonical encoding so a transaction cannot be resubmitted under a different hash.
+	canonical, err := proto.MarshalOptions{Deterministic: true}.Marshal(&transaction)
+	if err != nil || !bytes.Equal(canonical, tx) {
+		code, reason := reject(CodeTypeNonCanonicalTx, "Transaction is not canonically encoded")
+		return nil, code, reason
+	}
+
+	// Check the transaction type matches the payload it carries
 	switch transaction.Type {
 	case types.TransactionType_NormalTransaction:
-		normalData := &types.NormalTransactionData{}
-		normalData = transaction.GetNormalData()
-		if err != nil {
-			fmt.Println("Error unmarshaling normal transaction data:", err)
-			return 1
+		if transaction.GetNormalData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Normal transaction without normal transaction data")
+			return nil, code, reason
 		}
-		return 0
-		fmt.Println("Normal Transaction Data:", normalData)
 	case types.TransactionType_VerificationTransaction:
-		verificationData := &types.VerificationTransactionData{}
-		verificationData = transaction.GetVerificationData()
-		if err != nil {
-			fmt.Println("Error unmarshaling verification transaction data:", err)
-			return 1
+		if transaction.GetVerificationData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Verification transaction without verification transaction data")
+			return nil, code, reason
 		}
-		return 0
-		fmt.Println("Verification Transaction Data:", verificationData)
 	case types.TransactionType_ResourceTransaction:
-		resourceData := &types.ResourceTransactionData{}
-		resourceData = transaction.GetResourceData()
-		if err != nil {
-			fmt.Println("Error unmarshaling resource transaction data:", err)
-			return 1
+		if transaction.GetResourceData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Resource transaction without resource transaction data")
+			return nil, code, reason
+		}
+	case types.TransactionType_StakingTransaction:
+		if transaction.GetStakingData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Staking transaction without staking data")
+			return nil, code, reason
+		}
+	case types.TransactionType_GovernanceTransaction:
+		if transaction.GetGovernanceData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Governance transaction without governance data")
+			return nil, code, reason
+		}
+	case types.TransactionType_UpgradeTransaction:
+		if transaction.GetUpgradeData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Upgrade transaction without upgrade data")
+			return nil, code, reason
+		}
+	case types.TransactionType_AttestationTransaction:
+		if transaction.GetAttestationData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Attestation transaction without attestation transaction data")
+			return nil, code, reason
+		}
+	case types.TransactionType_AssignmentTransaction:
+		if transaction.GetAssignmentData() == nil {
+			code, reason := reject(CodeTypeInvalidTxType, "Assignment transaction without assignment data")
+			return nil, code, reason
 		}
-		return 0
-		fmt.Println("Resource Transaction Data:", resourceData)
 	default:
-		fmt.Println("Unknown transaction type")
-		return 1
+		code, reason := reject(CodeTypeInvalidTxType, "Unknown transaction type")
+		return nil, code, reason
 	}
-	return 1
+
+	if isProposerTransaction(transaction.Type) {
+		// Keep a single encoding for the same content, proposer transactions are not signed by an account
+		if transaction.Owner != "" || transaction.Signature != "" || transaction.Nonce != 0 {
+			code, reason := reject(CodeTypeInvalidOwner, "Proposer transaction with an owner")
+			return nil, code, reason
+		}
+		if transaction.Version != types.SchemaVersion {
+			code, reason := reject(CodeTypeUnsupportedVersion, "Proposer transaction with an old schema version")
+			return nil, code, reason
+		}
+		return &transaction, CodeTypeOK, ""
+	}
+
+	if err := types.VerifySignature(&transaction); err != nil {
+		code := CodeTypeInvalidSignature
+		switch {
+		case errors.Is(err, types.ErrInvalidOwner):
+			code = CodeTypeInvalidOwner
+		case errors.Is(err, types.ErrInvalidSignatureEncoding):
+			code = CodeTypeBadSignatureFormat
+		case errors.Is(err, types.ErrMalleableSignature):
+			code = CodeTypeMalleableSignature
+		}
+		code, reason := reject(code, "Rejected transaction signature: %v", err)
+		return nil, code, reason
+	}
+
+	// Everything past this point only deals with the current schema
+	if err := types.Migrate(&transaction); err != nil {
+		code := CodeTypeInvalidAmount
+		if errors.Is(err, types.ErrUnsupportedVersion) {
+			code = CodeTypeUnsupportedVersion
+		}
+		code, reason := reject(code, "Rejected transaction payload: %v", err)
+		return nil, code, reason
+	}
+
+	return &transaction, CodeTypeOK, ""
 }
 
 func (app *VerificationApp) CheckTx(_ context.Context, check *abcitypes.RequestCheckTx) (*abcitypes.ResponseCheckTx, error) {
-	code := app.isValid(check.Tx)
-	return &abcitypes.ResponseCheckTx{Code: code}, nil
+	if len(check.Tx) > maxTxBytes {
+		code, reason := reject(CodeTypeTxTooLarge, "Rejected transaction of %d bytes", len(check.Tx))
+		return &abcitypes.ResponseCheckTx{Code: code, Log: reason}, nil
+	}
+	transaction, code, reason := decodeTransaction(check.Tx)
+	if code != CodeTypeOK {
+		return &abcitypes.ResponseCheckTx{Code: code, Log: reason}, nil
+	}
+	if isProposerTransaction(transaction.Type) {
+		code, reason := reject(CodeTypeProposerOnly, "Rejected proposer transaction sent to the mempool")
+		return &abcitypes.ResponseCheckTx{Code: code, Log: reason}, nil
+	}
+
+	gas := types.Gas(transaction)
+	if code, reason := app.checkFee(transaction); code != CodeTypeOK {
+		return &abcitypes.ResponseCheckTx{Code: code, Log: reason, GasWanted: gas}, nil
+	}
+	code, reason = app.checkPendingNonce(transaction)
+	return &abcitypes.ResponseCheckTx{Code: code, Log: reason, GasWanted: gas}, nil
 }
 
 func NewVerificationApp(db *badger.DB) *VerificationApp {
-	return &VerificationApp{db: db}
+	return &VerificationApp{
+		db:            db,
+		pendingNonces: make(map[string]uint64),
+		pendingFees:   make(map[string]uint64),
+	}
 }
 
+// InitChain stores the genesis validator set, bonded with the stake its power is worth, and allocates the genesis balances from the app_state of genesis.json.
+// Vote extensions carry the collector summaries, so they are enabled from the first block unless genesis.json says otherwise.
 func (app *VerificationApp) InitChain(_ context.Context, chain *abcitypes.RequestInitChain) (*abcitypes.ResponseInitChain, error) {
-	return &abcitypes.ResponseInitChain{}, nil
-}
-
-func (app *VerificationApp) PrepareProposal(_ context.Context, proposal *abcitypes.RequestPrepareProposal) (*abcitypes.ResponsePrepareProposal, error) {
-	return &abcitypes.ResponsePrepareProposal{Txs: proposal.Txs}, nil
-}
-func (app *VerificationApp) ProcessProposal(_ context.Context, proposal *abcitypes.RequestProcessProposal) (*abcitypes.ResponseProcessProposal, error) {
-	return &abcitypes.ResponseProcessProposal{Status: abcitypes.ResponseProcessProposal_ACCEPT}, nil
-}
+	genesis, err := parseGenesisState(chain.AppStateBytes)
+	if err != nil {
+		return nil, err
+	}
 
-func (app VerificationApp) Commit(_ context.Context, commit *abcitypes.RequestCommit) (*abcitypes.ResponseCommit, error) {
-	return &abcitypes.ResponseCommit{}, app.onGoingBlock.Commit()
-}
+	// Nothing is committed until the first block, so InitChain runs again if the node stops before that.
+	// Genesis only sets keys, so applying it twice is harmless.
+	// The genesis state is the state after the block before the initial height
+	var appHash []byte
+	err = update(app.db, max(chain.InitialHeight, 1)-1, func(txn *badger.Txn) error {
+		if err := setKey(txn, chainIDKey, []byte(chain.ChainId)); err != nil {
+			return err
+		}
+		if err := applyValidatorUpdates(txn, chain.Validators); err != nil {
+			return err
+		}
+		if err := genesisStake(txn, chain.Validators, genesis.supply()); err != nil {
+			return err
+		}
+		if err := genesis.apply(txn); err != nil {
+			return err
+		}
+		appHash, err = computeAppHash(txn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	app.chainID = chain.ChainId
 
-func (app *VerificationApp) ListSnapshots(_ context.Context, snapshots *abcitypes.RequestListSnapshots) (*abcitypes.ResponseListSnapshots, error) {
-	return &abcitypes.ResponseListSnapshots{}, nil
+	// Later changes come from staking transactions, through the validator updates of FinalizeBlock
+	res := &abcitypes.ResponseInitChain{AppHash: appHash, Validators: chain.Validators}
+	if chain.ConsensusParams.GetAbci().GetVoteExtensionsEnableHeight() == 0 {
+		res.ConsensusParams = &cmtproto.ConsensusParams{
+			Abci: &cmtproto.ABCIParams{VoteExtensionsEnableHeight: max(chain.InitialHeight, 1)},
+		}
+	}
+	return res, nil
 }
 
-func (app *VerificationApp) OfferSnapshot(_ context.Context, snapshot *abcitypes.RequestOfferSnapshot) (*abcitypes.ResponseOfferSnapshot, error) {
-	return &abcitypes.ResponseOfferSnapshot{}, nil
-}
+func (app *VerificationApp) Commit(_ context.Context, commit *abcitypes.RequestCommit) (*abcitypes.ResponseCommit, error) {
+	// The block's writes, including its height and app hash, land on disk atomically.
+	// If the node dies before this point, CometBFT replays the block on restart.
+	err := app.onGoingBlock.CommitAt(stateVersion(app.height), nil)
+	app.onGoingBlock = nil
 
-func (app *VerificationApp) LoadSnapshotChunk(_ context.Context, chunk *abcitypes.RequestLoadSnapshotChunk) (*abcitypes.ResponseLoadSnapshotChunk, error) {
-	return &abcitypes.ResponseLoadSnapshotChunk{}, nil
-}
+	// Rechecking the mempool after this rebuilds the pending nonces on top of the new state
+	app.pendingNonces = make(map[string]uint64)
+	app.pendingFees = make(map[string]uint64)
+	if err != nil {
+		return nil, err
+	}
 
-func (app *VerificationApp) ApplySnapshotChunk(_ context.Context, chunk *abcitypes.RequestApplySnapshotChunk) (*abcitypes.ResponseApplySnapshotChunk, error) {
-	return &abcitypes.ResponseApplySnapshotChunk{Result: abcitypes.ResponseApplySnapshotChunk_ACCEPT}, nil
+	app.snapshotAfterCommit(app.height)
+	app.discardHistory()
+	if err := app.checkUpgrade(); err != nil {
+		return nil, err
+	}
+	return &abcitypes.ResponseCommit{}, nil
 }
 
-func (app VerificationApp) ExtendVote(_ context.Context, extend *abcitypes.RequestExtendVote) (*abcitypes.ResponseExtendVote, error) {
-	return &abcitypes.ResponseExtendVote{}, nil
+// ExtendVote attaches the summaries this validator collected during the interval closed by the height being voted on
+func (app *VerificationApp) ExtendVote(_ context.Context, extend *abcitypes.RequestExtendVote) (*abcitypes.ResponseExtendVote, error) {
+	if app.extensionHeight != extend.Height {
+		extension, err := app.buildVoteExtension(extend.Height)
+		if err != nil {
+			return nil, err
+		}
+		app.extension, app.extensionHeight = extension, extend.Height
+	}
+	return &abcitypes.ResponseExtendVote{VoteExtension: app.extension}, nil
 }
 
+// VerifyVoteExtension checks the content of another validator's extension, CometBFT already checked its signature
 func (app *VerificationApp) VerifyVoteExtension(_ context.Context, verify *abcitypes.RequestVerifyVoteExtension) (*abcitypes.ResponseVerifyVoteExtension, error) {
-	return &abcitypes.ResponseVerifyVoteExtension{}, nil
-}
-
-func (app *VerificationApp) handleNormalTransaction(tx types.NormalTransactionData) uint32 {
-	return 0
-}
-
-func (app *VerificationApp) handleVerificationTransaction(tx types.VerificationTransactionData) uint32 {
-	return 0
+	if _, code, _ := decodeVoteExtension(verify.VoteExtension, verify.Height); code != CodeTypeOK {
+		return &abcitypes.ResponseVerifyVoteExtension{Status: abcitypes.ResponseVerifyVoteExtension_REJECT}, nil
+	}
+	return &abcitypes.ResponseVerifyVoteExtension{Status: abcitypes.ResponseVerifyVoteExtension_ACCEPT}, nil
 }
 
-func (app *VerificationApp) handleResourceTransaction(tx types.ResourceTransactionData) uint32 {
-	return 0
-}
+// Info tells CometBFT where the application state is at, so the handshake only replays the missing blocks
 func (app *VerificationApp) Info(_ context.Context, info *abcitypes.RequestInfo) (*abcitypes.ResponseInfo, error) {
-	return &abcitypes.ResponseInfo{}, nil
+	height, appHash, err := loadCommitInfo(app.db)
+	if err != nil {
+		return nil, err
+	}
+	// Info is the first call after a restart, attestations can't be verified without the chain ID
+	if app.chainID, err = loadChainID(app.db); err != nil {
+		return nil, err
+	}
+	// A node restarted with the binary it should have upgraded from stops again
+	if err := app.checkUpgrade(); err != nil {
+		return nil, err
+	}
+
+	return &abcitypes.ResponseInfo{
+		Data:             "openmesh-core",
+		AppVersion:       AppVersion,
+		LastBlockHeight:  height,
+		LastBlockAppHash: appHash,
+	}, nil
 }
@@ -0,0 +1,1806 @@
+package verificationApp
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmted25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/dgraph-io/badger/v3"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestDB opens an in-memory database that is closed once the test finishes
+func newTestDB(t *testing.T) *badger.DB {
+	db, err := badger.OpenManaged(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// runBlock finalises and commits a block, returning the results of FinalizeBlock
+func runBlock(t *testing.T, app *VerificationApp, height int64, txs ...[]byte) *abcitypes.ResponseFinalizeBlock {
+	ctx := context.Background()
+	res, err := app.FinalizeBlock(ctx, &abcitypes.RequestFinalizeBlock{Height: height, Txs: txs})
+	assert.NoError(t, err)
+	_, err = app.Commit(ctx, &abcitypes.RequestCommit{})
+	assert.NoError(t, err)
+	// A write that skipped setKey or deleteKey would leave the tree behind the state
+	assert.Equal(t, rebuiltAppHash(t, app.db), res.AppHash)
+	return res
+}
+
+// rebuiltAppHash returns the app hash of the latest state, computed from the whole state rather than the tree
+func rebuiltAppHash(t *testing.T, db *badger.DB) (root []byte) {
+	view(db, func(txn *badger.Txn) error {
+		leaves, err := treeLeaves(txn)
+		assert.NoError(t, err)
+		root, err = buildTree(leaves, 0, nil)
+		assert.NoError(t, err)
+		return nil
+	})
+	return root
+}
+
+func TestInfoAfterRestart(t *testing.T) {
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+
+	info, err := app.Info(context.Background(), &abcitypes.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), info.LastBlockHeight)
+	assert.Empty(t, info.LastBlockAppHash)
+
+	runBlock(t, app, 1)
+	res := runBlock(t, app, 2)
+	assert.NotEmpty(t, res.AppHash)
+
+	// A new application over the same database must resume where the old one stopped
+	info, err = NewVerificationApp(db).Info(context.Background(), &abcitypes.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), info.LastBlockHeight)
+	assert.Equal(t, res.AppHash, info.LastBlockAppHash)
+	assert.Equal(t, AppVersion, info.AppVersion)
+}
+
+func TestAppHashDeterministic(t *testing.T) {
+	hash := func(pairs ...string) []byte {
+		db := newTestDB(t)
+		txn := db.NewTransactionAt(latestVersion, true)
+		defer txn.Discard()
+		for i := 0; i < len(pairs); i += 2 {
+			assert.NoError(t, setKey(txn, []byte(pairs[i]), []byte(pairs[i+1])))
+		}
+		h, err := computeAppHash(txn)
+		assert.NoError(t, err)
+		return h
+	}
+
+	// Insertion order and meta keys must not matter, contents must
+	assert.Equal(t, hash("a", "1", "b", "2"), hash("b", "2", "a", "1"))
+	assert.Equal(t, hash("a", "1"), hash("a", "1", "meta/lastHeight", "x"))
+	assert.NotEqual(t, hash("a", "1"), hash("a", "2"))
+	assert.NotEqual(t, hash("ab", "c"), hash("a", "bc"))
+}
+
+func TestAppHashIncremental(t *testing.T) {
+	db := newTestDB(t)
+	rng := rand.New(rand.NewSource(1))
+
+	// Blocks of writes and deletes over keys that share their first bytes, the tree must end up as if built at once
+	for height := int64(1); height <= 20; height++ {
+		var appHash []byte
+		err := update(db, height, func(txn *badger.Txn) (err error) {
+			for i := 0; i < 50; i++ {
+				key := []byte("key/" + strconv.Itoa(rng.Intn(200)))
+				if rng.Intn(3) == 0 {
+					err = deleteKey(txn, key)
+				} else {
+					err = setKey(txn, key, []byte(strconv.Itoa(rng.Int())))
+				}
+				if err != nil {
+					return err
+				}
+			}
+			appHash, err = computeAppHash(txn)
+			return err
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, rebuiltAppHash(t, db), appHash)
+
+		// Proofs come from the nodes of the tree, every key present has one and the others don't
+		view(db, func(txn *badger.Txn) error {
+			for i := 0; i < 200; i += 7 {
+				key := []byte("key/" + strconv.Itoa(i))
+				proof, err := stateProof(txn, key)
+				assert.NoError(t, err)
+				item, err := txn.Get(key)
+				if err == badger.ErrKeyNotFound {
+					assert.Nil(t, proof)
+					continue
+				}
+				value, err := item.ValueCopy(nil)
+				assert.NoError(t, err)
+				keyPath := merkle.KeyPath{}.AppendKey(key, merkle.KeyEncodingURL).String()
+				assert.NoError(t, ProofRuntime().VerifyValue(proof, appHash, keyPath, value))
+			}
+			return nil
+		})
+	}
+
+	// Emptying the state empties the tree
+	err := update(db, 21, func(txn *badger.Txn) error {
+		for i := 0; i < 200; i++ {
+			if err := deleteKey(txn, []byte("key/"+strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		appHash, err := computeAppHash(txn)
+		assert.Equal(t, emptyHash, appHash)
+		return err
+	})
+	assert.NoError(t, err)
+	view(db, func(txn *badger.Txn) error {
+		return iteratePrefix(txn, []byte("meta/"), func(item *badger.Item) error {
+			assert.False(t, isTreeKey(item.Key()), "%s is left", item.Key())
+			return nil
+		})
+	})
+}
+
+// signedTx returns the encoding of a normal transaction signed with the given ed25519 key
+func signedTx(t *testing.T, key ed25519.PrivateKey, nonce uint64, data *types.NormalTransactionData) []byte {
+	tx := &types.Transaction{
+		Type:    types.TransactionType_NormalTransaction,
+		Nonce:   nonce,
+		Version: types.SchemaVersion,
+		Data:    &types.Transaction_NormalData{NormalData: data},
+	}
+	assert.NoError(t, types.SignEd25519(tx, key))
+	raw, err := proto.Marshal(tx)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestCheckTxSignatures(t *testing.T) {
+	app := NewVerificationApp(newTestDB(t))
+	checkTx := func(tx []byte) uint32 {
+		res, err := app.CheckTx(context.Background(), &abcitypes.RequestCheckTx{Tx: tx})
+		assert.NoError(t, err)
+		return res.Code
+	}
+
+	_, key, _ := ed25519.GenerateKey(nil)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	data := &types.NormalTransactionData{Amount: 1, SentTo: types.Ed25519Owner(otherKey.Public().(ed25519.PublicKey))}
+	valid := signedTx(t, key, 0, data)
+	assert.Equal(t, CodeTypeOK, checkTx(valid))
+	assert.Equal(t, CodeTypeEncodingError, checkTx([]byte("key=value")))
+
+	// Someone else's signature over the same transaction is a forgery
+	var tx types.Transaction
+	assert.NoError(t, proto.Unmarshal(valid, &tx))
+	forged := proto.Clone(&tx).(*types.Transaction)
+	assert.NoError(t, types.SignEd25519(forged, otherKey))
+	forged.Owner = tx.Owner
+	raw, _ := proto.Marshal(forged)
+	assert.Equal(t, CodeTypeInvalidSignature, checkTx(raw))
+
+	// Tampering with the body invalidates the signature
+	tampered := proto.Clone(&tx).(*types.Transaction)
+	tampered.GetNormalData().Amount = 1000
+	raw, _ = proto.Marshal(tampered)
+	assert.Equal(t, CodeTypeInvalidSignature, checkTx(raw))
+
+	// Same transaction with its owner field moved to the end on the wire
+	ownerField := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), tx.Owner)
+	assert.Equal(t, CodeTypeNonCanonicalTx, checkTx(append(valid[len(ownerField):], ownerField...)))
+
+	broken := proto.Clone(&tx).(*types.Transaction)
+	broken.Owner = "not-a-key"
+	raw, _ = proto.Marshal(broken)
+	assert.Equal(t, CodeTypeInvalidOwner, checkTx(raw))
+
+	broken = proto.Clone(&tx).(*types.Transaction)
+	broken.Signature = "c2ln"
+	raw, _ = proto.Marshal(broken)
+	assert.Equal(t, CodeTypeBadSignatureFormat, checkTx(raw))
+}
+
+func TestCheckTxSecp256k1(t *testing.T) {
+	app := NewVerificationApp(newTestDB(t))
+	key, err := ethcrypto.GenerateKey()
+	assert.NoError(t, err)
+
+	tx := &types.Transaction{
+		Type:    types.TransactionType_NormalTransaction,
+		Version: types.SchemaVersion,
+		Data:    &types.Transaction_NormalData{NormalData: &types.NormalTransactionData{Amount: 1, SentTo: "someone"}},
+	}
+	assert.NoError(t, types.SignSecp256k1(tx, key))
+	raw, _ := proto.Marshal(tx)
+	res, err := app.CheckTx(context.Background(), &abcitypes.RequestCheckTx{Tx: raw})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeOK, res.Code)
+
+	// (R, N-S, 1-V) is the same signature in its high S form
+	sig, _ := base64.RawStdEncoding.DecodeString(tx.Signature)
+	s := new(big.Int).SetBytes(sig[32:64])
+	s.Sub(ethcrypto.S256().Params().N, s)
+	s.FillBytes(sig[32:64])
+	sig[64] ^= 1
+	tx.Signature = base64.RawStdEncoding.EncodeToString(sig)
+	raw, _ = proto.Marshal(tx)
+	res, err = app.CheckTx(context.Background(), &abcitypes.RequestCheckTx{Tx: raw})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeMalleableSignature, res.Code)
+}
+
+// ownerOf returns the owner string of an ed25519 key
+func ownerOf(key ed25519.PrivateKey) string {
+	return types.Ed25519Owner(key.Public().(ed25519.PublicKey))
+}
+
+// queryAccount reads an account through the ABCI query interface
+func queryAccount(t *testing.T, app *VerificationApp, owner string) *types.Account {
+	res, err := app.Query(context.Background(), &abcitypes.RequestQuery{Path: "/account", Data: []byte(owner)})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeOK, res.Code)
+	account := &types.Account{}
+	assert.NoError(t, proto.Unmarshal(res.Value, account))
+	return account
+}
+
+func TestTransfers(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	app := NewVerificationApp(newTestDB(t))
+	appState := []byte(`{"accounts": [{"owner": "` + ownerOf(alice) + `", "balance": "100"}]}`)
+	_, err := app.InitChain(context.Background(), &abcitypes.RequestInitChain{AppStateBytes: appState})
+	assert.NoError(t, err)
+
+	send := func(nonce uint64, amount uint64) []byte {
+		return signedTx(t, alice, nonce, &types.NormalTransactionData{Amount: amount, SentTo: ownerOf(bob)})
+	}
+	res := runBlock(t, app, 1,
+		send(0, 30),
+		send(0, 30),  // Replayed
+		send(2, 10),  // Skips nonce 1
+		send(1, 500), // More than what's left, still uses its nonce
+		send(2, 0),   // Nothing to send
+		send(3, 70),
+	)
+	codes := make([]uint32, 0)
+	for _, r := range res.TxResults {
+		codes = append(codes, r.Code)
+	}
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeNonceTooLow, CodeTypeNonceGap, CodeTypeInsufficientFunds, CodeTypeInvalidAmount, CodeTypeOK}, codes)
+
+	// The results say why a transaction was rejected, and only what was executed used gas
+	assert.Empty(t, res.TxResults[0].Log)
+	assert.Equal(t, types.GasNormal, res.TxResults[0].GasWanted)
+	assert.Equal(t, types.GasNormal, res.TxResults[0].GasUsed)
+	assert.Equal(t, "Wrong nonce for "+ownerOf(alice)+": expected 1, got 0", res.TxResults[1].Log)
+	assert.Equal(t, types.GasNormal, res.TxResults[1].GasWanted)
+	assert.Zero(t, res.TxResults[1].GasUsed)
+	assert.Equal(t, "Rejected transfer: "+ownerOf(alice)+" has 70, needs 500", res.TxResults[3].Log)
+	assert.Equal(t, types.GasNormal, res.TxResults[3].GasUsed)
+
+	account := queryAccount(t, app, ownerOf(alice))
+	assert.Equal(t, uint64(0), account.Balance)
+	assert.Equal(t, uint64(4), account.Nonce)
+	assert.Equal(t, uint64(100), queryAccount(t, app, ownerOf(bob)).Balance)
+}
+
+func TestInvalidGenesis(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	owner := ownerOf(key)
+
+	for _, appState := range []string{
+		`not json`,
+		`{"accounts": [{"owner": "nobody", "balance": "1"}]}`,
+		`{"accounts": [{"owner": "` + owner + `", "balance": "1"}, {"owner": "` + owner + `", "balance": "1"}]}`,
+		`{"accounts": [{"owner": "` + owner + `", "balance": "18446744073709551615"}, {"owner": "` + types.Ed25519Owner(make([]byte, 32)) + `", "balance": "1"}]}`,
+		`{"datasources": [""]}`,
+		`{"datasources": ["binance/btc.eth", "binance/btc.eth"]}`,
+		`{"slashing": {"doubleSignSlash": 10001, "maxMissedBlocks": 1}}`,
+		`{"slashing": {"doubleSignSlash": 500}}`,
+		`{"slashing": {"maxMissedBlocks": 1}}`,
+		`{"params": {"collectorSlots": "0"}}`,
+		`{"params": {"updateThreshold": "1/2"}}`,
+		`{"params": {"unknown": "1"}}`,
+		`{"updateKeys": ["nobody"]}`,
+		`{"updateKeys": ["` + owner + `", "` + owner + `"]}`,
+	} {
+		_, err := parseGenesisState([]byte(appState))
+		assert.Error(t, err, appState)
+	}
+}
+
+func TestMempoolNonces(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	app := NewVerificationApp(newTestDB(t))
+	appState := []byte(`{"accounts": [{"owner": "` + ownerOf(alice) + `", "balance": "100"}]}`)
+	_, err := app.InitChain(context.Background(), &abcitypes.RequestInitChain{AppStateBytes: appState})
+	assert.NoError(t, err)
+
+	txs := make([][]byte, 3)
+	for i := range txs {
+		txs[i] = signedTx(t, alice, uint64(i), &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(bob)})
+	}
+	checkTx := func(tx []byte, checkType abcitypes.CheckTxType) uint32 {
+		res, err := app.CheckTx(context.Background(), &abcitypes.RequestCheckTx{Tx: tx, Type: checkType})
+		assert.NoError(t, err)
+		return res.Code
+	}
+
+	assert.Equal(t, CodeTypeOK, checkTx(txs[0], abcitypes.CheckTxType_New))
+	assert.Equal(t, CodeTypeNonceTooLow, checkTx(txs[0], abcitypes.CheckTxType_New))
+	assert.Equal(t, CodeTypeNonceGap, checkTx(txs[2], abcitypes.CheckTxType_New))
+	assert.Equal(t, CodeTypeOK, checkTx(txs[1], abcitypes.CheckTxType_New))
+	assert.Equal(t, CodeTypeOK, checkTx(txs[2], abcitypes.CheckTxType_New))
+
+	// Only the first transaction makes it into the block, the recheck evicts it and keeps the others
+	runBlock(t, app, 1, txs[0])
+	assert.Equal(t, CodeTypeNonceTooLow, checkTx(txs[0], abcitypes.CheckTxType_Recheck))
+	assert.Equal(t, CodeTypeOK, checkTx(txs[1], abcitypes.CheckTxType_Recheck))
+	assert.Equal(t, CodeTypeOK, checkTx(txs[2], abcitypes.CheckTxType_Recheck))
+}
+
+func TestLegacySchemaMigration(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	app := NewVerificationApp(newTestDB(t))
+	appState := []byte(`{"accounts": [{"owner": "` + ownerOf(alice) + `", "balance": "100"}]}`)
+	_, err := app.InitChain(context.Background(), &abcitypes.RequestInitChain{AppStateBytes: appState})
+	assert.NoError(t, err)
+
+	send := func(version uint32, nonce uint64, data *types.NormalTransactionData) []byte {
+		tx := &types.Transaction{
+			Type:    types.TransactionType_NormalTransaction,
+			Nonce:   nonce,
+			Version: version,
+			Data:    &types.Transaction_NormalData{NormalData: data},
+		}
+		assert.NoError(t, types.SignEd25519(tx, alice))
+		raw, err := proto.Marshal(tx)
+		assert.NoError(t, err)
+		return raw
+	}
+	res := runBlock(t, app, 1,
+		send(0, 0, &types.NormalTransactionData{TransactionAmount: 40, SentTo: ownerOf(bob)}),
+		send(0, 1, &types.NormalTransactionData{TransactionAmount: 0.5, SentTo: ownerOf(bob)}),
+		send(0, 1, &types.NormalTransactionData{TransactionAmount: -1, SentTo: ownerOf(bob)}),
+		send(1, 1, &types.NormalTransactionData{TransactionAmount: 1, Amount: 1, SentTo: ownerOf(bob)}),
+		send(2, 1, &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(bob)}),
+	)
+	codes := make([]uint32, 0)
+	for _, r := range res.TxResults {
+		codes = append(codes, r.Code)
+	}
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeInvalidAmount, CodeTypeInvalidAmount, CodeTypeInvalidAmount, CodeTypeUnsupportedVersion}, codes)
+	assert.Equal(t, uint64(40), queryAccount(t, app, ownerOf(bob)).Balance)
+}
+
+func TestAmountFormatting(t *testing.T) {
+	units, err := types.ParseAmount("1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1500000), units)
+	assert.Equal(t, "1.5", types.FormatAmount(units))
+	assert.Equal(t, "0.000001", types.FormatAmount(1))
+	assert.Equal(t, "18446744073709.551615", types.FormatAmount(^uint64(0)))
+
+	for _, invalid := range []string{"", "-1", "1.0000001", "18446744073709.551616", ".5", "1e3"} {
+		_, err := types.ParseAmount(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+const testChainID = "openmesh-test"
+
+// testValidators starts a chain with the given number of ed25519 validators of power 10 and returns their keys
+func testValidators(t *testing.T, app *VerificationApp, n int, appState string) []ed25519.PrivateKey {
+	keys := make([]ed25519.PrivateKey, n)
+	updates := make([]abcitypes.ValidatorUpdate, n)
+	for i := range keys {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		assert.NoError(t, err)
+		keys[i] = priv
+		updates[i] = abcitypes.Ed25519ValidatorUpdate(pub, 10)
+	}
+	res, err := app.InitChain(context.Background(), &abcitypes.RequestInitChain{ChainId: testChainID, Validators: updates, AppStateBytes: []byte(appState)})
+	assert.NoError(t, err)
+	// Vote extensions are on from the first block
+	assert.Equal(t, int64(1), res.ConsensusParams.Abci.VoteExtensionsEnableHeight)
+	return keys
+}
+
+// verificationTx returns the encoding of a verification transaction signed with the given ed25519 key
+func verificationTx(t *testing.T, key ed25519.PrivateKey, nonce uint64, datasource string, timestamp int64, cids ...string) []byte {
+	tx := types.NewVerificationTransaction(nonce, datasource, timestamp, cids)
+	assert.NoError(t, types.SignEd25519(tx, key))
+	raw, err := proto.Marshal(tx)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestVerificationQuorum(t *testing.T) {
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 4, "")
+
+	const datasource = "ethereum/blocks"
+	honest := []string{
+		"bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy",
+		"bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+	}
+	other := []string{"bafkreidvbhs33ighmljlvr7zbv2ywwzcmp5adtf4kqvlly67cy56bdtmve"}
+
+	// Two out of four validators is not more than 2/3 of the power
+	res := runBlock(t, app, 2,
+		verificationTx(t, keys[0], 0, datasource, 1, honest...),
+		verificationTx(t, keys[3], 0, datasource, 1, other...),
+		verificationTx(t, keys[1], 0, datasource, 1, honest...),
+	)
+	for _, result := range res.TxResults {
+		assert.Equal(t, CodeTypeOK, result.Code)
+	}
+	view(db, func(txn *badger.Txn) error {
+		round, err := getRound(txn, datasource, 1)
+		assert.NoError(t, err)
+		assert.Empty(t, round.Attestation)
+		verified, err := getVerifiedCid(txn, honest[0])
+		assert.NoError(t, err)
+		assert.Nil(t, verified)
+		return nil
+	})
+
+	// The third honest validator makes 30 out of 40
+	res = runBlock(t, app, 3, verificationTx(t, keys[2], 0, datasource, 1, honest...))
+	assert.Equal(t, CodeTypeOK, res.TxResults[0].Code)
+	view(db, func(txn *badger.Txn) error {
+		round, err := getRound(txn, datasource, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, types.AttestationDigest(honest), round.Attestation)
+		assert.Equal(t, honest, round.Cids)
+		assert.Equal(t, int64(3), round.VerifiedHeight)
+		assert.Len(t, round.Agreeing, 3)
+		assert.Equal(t, []string{ownerOf(keys[3])}, round.Disagreeing)
+
+		for _, c := range honest {
+			verified, err := getVerifiedCid(txn, c)
+			assert.NoError(t, err)
+			assert.Equal(t, datasource, verified.Datasource)
+			assert.Equal(t, int64(1), verified.Timestamp)
+			assert.Equal(t, int64(3), verified.Height)
+		}
+		verified, err := getVerifiedCid(txn, other[0])
+		assert.NoError(t, err)
+		assert.Nil(t, verified)
+		return nil
+	})
+}
+
+func TestVerificationRejections(t *testing.T) {
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 1, "")
+	_, outsider, _ := ed25519.GenerateKey(nil)
+
+	const datasource = "ethereum/blocks"
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+
+	badAttestation := types.NewVerificationTransaction(2, datasource, 1, []string{c})
+	badAttestation.GetVerificationData().Attestation = types.AttestationDigest(nil)
+	assert.NoError(t, types.SignEd25519(badAttestation, keys[0]))
+	rawBadAttestation, err := proto.Marshal(badAttestation)
+	assert.NoError(t, err)
+
+	res := runBlock(t, app, 2,
+		verificationTx(t, keys[0], 0, datasource, 1, c),
+		verificationTx(t, keys[0], 1, datasource, 1, c),
+		verificationTx(t, outsider, 0, datasource, 1, c),
+		rawBadAttestation,
+		verificationTx(t, keys[0], 3, datasource, 2, c),
+		verificationTx(t, keys[0], 4, "", 1, c),
+		verificationTx(t, keys[0], 5, datasource, 1, "not-a-cid"),
+		verificationTx(t, keys[0], 6, datasource, 1),
+	)
+	assert.Equal(t, []uint32{
+		CodeTypeOK,
+		CodeTypeDuplicateSubmission,
+		CodeTypeNotValidator,
+		CodeTypeInvalidAttestation,
+		CodeTypeInvalidTimestamp,
+		CodeTypeInvalidDatasource,
+		CodeTypeInvalidCid,
+		CodeTypeInvalidCid,
+	}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code, res.TxResults[3].Code,
+		res.TxResults[4].Code, res.TxResults[5].Code, res.TxResults[6].Code, res.TxResults[7].Code,
+	})
+}
+
+// extendedVote signs a vote extension for the given height the way CometBFT does
+func extendedVote(key ed25519.PrivateKey, height int64, extension []byte) abcitypes.ExtendedVoteInfo {
+	signBytes := cmttypes.VoteExtensionSignBytes(testChainID, &cmtproto.Vote{
+		Type:      cmtproto.PrecommitType,
+		Height:    height,
+		Extension: extension,
+	})
+	return abcitypes.ExtendedVoteInfo{
+		Validator:          abcitypes.Validator{Address: cmted25519.PubKey(key.Public().(ed25519.PublicKey)).Address(), Power: 10},
+		VoteExtension:      extension,
+		ExtensionSignature: ed25519.Sign(key, signBytes),
+		BlockIdFlag:        cmtproto.BlockIDFlagCommit,
+	}
+}
+
+// proposedLastCommit returns the last commit CometBFT puts in a proposal made with the given extended commit
+func proposedLastCommit(commit abcitypes.ExtendedCommitInfo) abcitypes.CommitInfo {
+	votes := make([]abcitypes.VoteInfo, len(commit.Votes))
+	for i, vote := range commit.Votes {
+		votes[i] = abcitypes.VoteInfo{Validator: vote.Validator, BlockIdFlag: vote.BlockIdFlag}
+	}
+	return abcitypes.CommitInfo{Round: commit.Round, Votes: votes}
+}
+
+func TestVoteExtensions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 4, "")
+
+	honest := []string{"bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"}
+	other := []string{"bafkreidvbhs33ighmljlvr7zbv2ywwzcmp5adtf4kqvlly67cy56bdtmve"}
+	extend := func(cids []string) []byte {
+		app.SetSummarySource(func(start, end time.Time) []*types.VerificationTransactionData {
+			return []*types.VerificationTransactionData{
+				{Datasource: "ethereum/blocks", Cids: cids},
+				{Datasource: "binance/btc.eth", Cids: nil}, // Nothing collected, dropped
+			}
+		})
+		app.extensionHeight = 0
+		res, err := app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: 1})
+		assert.NoError(t, err)
+		return res.VoteExtension
+	}
+	honestExtension, otherExtension := extend(honest), extend(other)
+
+	// A vote in a later round of the same height carries the same extension
+	res, err := app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, otherExtension, res.VoteExtension)
+
+	verify := func(height int64, extension []byte) abcitypes.ResponseVerifyVoteExtension_VerifyStatus {
+		res, err := app.VerifyVoteExtension(ctx, &abcitypes.RequestVerifyVoteExtension{Height: height, VoteExtension: extension})
+		assert.NoError(t, err)
+		return res.Status
+	}
+	assert.Equal(t, abcitypes.ResponseVerifyVoteExtension_ACCEPT, verify(1, honestExtension))
+	assert.Equal(t, abcitypes.ResponseVerifyVoteExtension_ACCEPT, verify(1, nil))
+	assert.Equal(t, abcitypes.ResponseVerifyVoteExtension_REJECT, verify(2, honestExtension))
+	assert.Equal(t, abcitypes.ResponseVerifyVoteExtension_REJECT, verify(1, []byte("garbage")))
+
+	runBlock(t, app, 1)
+
+	commit := abcitypes.ExtendedCommitInfo{Votes: []abcitypes.ExtendedVoteInfo{
+		extendedVote(keys[0], 1, honestExtension),
+		extendedVote(keys[1], 1, honestExtension),
+		extendedVote(keys[2], 1, honestExtension),
+		extendedVote(keys[3], 1, otherExtension),
+	}}
+	transfer := signedTx(t, keys[0], 0, &types.NormalTransactionData{SentTo: ownerOf(keys[1]), Amount: 1})
+	prepared, err := app.PrepareProposal(ctx, &abcitypes.RequestPrepareProposal{
+		Height:          2,
+		MaxTxBytes:      1 << 20,
+		Txs:             [][]byte{transfer},
+		LocalLastCommit: commit,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, prepared.Txs, 2)
+	txType, _ := proposalTxType(prepared.Txs[0])
+	assert.Equal(t, types.TransactionType_AttestationTransaction, txType)
+	assert.Equal(t, transfer, prepared.Txs[1])
+
+	process := func(height int64, txs ...[]byte) abcitypes.ResponseProcessProposal_ProposalStatus {
+		res, err := app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: height, Txs: txs, ProposedLastCommit: proposedLastCommit(commit)})
+		assert.NoError(t, err)
+		return res.Status
+	}
+	assert.Equal(t, abcitypes.ResponseProcessProposal_ACCEPT, process(2, prepared.Txs...))
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(3, prepared.Txs...))
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(2, transfer, prepared.Txs[0]))
+
+	// The attestation is required and carries every precommit of the last commit, empty extensions included
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(2, transfer))
+	partial := commit
+	partial.Votes = commit.Votes[:3]
+	partialTx, err := newAttestationTransaction(partial, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(2, partialTx, transfer))
+	otherRound := commit
+	otherRound.Round = 1
+	otherRoundTx, err := newAttestationTransaction(otherRound, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(2, otherRoundTx, transfer))
+	silent := commit
+	silent.Votes = append([]abcitypes.ExtendedVoteInfo{}, commit.Votes...)
+	silent.Votes[3] = extendedVote(keys[3], 1, nil)
+	silentTx, err := newAttestationTransaction(silent, 1)
+	assert.NoError(t, err)
+	attestation := &types.Transaction{}
+	assert.NoError(t, proto.Unmarshal(silentTx, attestation))
+	assert.Len(t, attestation.GetAttestationData().Votes, 4)
+	processed, err := app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: 2, Txs: [][]byte{silentTx}, ProposedLastCommit: proposedLastCommit(silent)})
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_ACCEPT, processed.Status)
+
+	// An extension signed by a validator for a different content does not verify
+	forged := commit
+	forged.Votes = append([]abcitypes.ExtendedVoteInfo{}, commit.Votes...)
+	forged.Votes[3].VoteExtension = honestExtension
+	forgedTx, err := newAttestationTransaction(forged, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process(2, forgedTx))
+
+	// Attestations only come from proposers
+	check, err := app.CheckTx(ctx, &abcitypes.RequestCheckTx{Tx: prepared.Txs[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeProposerOnly, check.Code)
+
+	finalized := runBlock(t, app, 2, prepared.Txs...)
+	assert.Equal(t, CodeTypeOK, finalized.TxResults[0].Code)
+	view(db, func(txn *badger.Txn) error {
+		round, err := getRound(txn, "ethereum/blocks", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, types.AttestationDigest(honest), round.Attestation)
+		assert.Len(t, round.Agreeing, 3)
+		assert.Equal(t, []string{ownerOf(keys[3])}, round.Disagreeing)
+		return nil
+	})
+}
+
+func TestCollectorAssignment(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 4, `{"datasources": ["coinbase/BTC-USD", "binance/btc.eth"]}`)
+
+	prepared, err := app.PrepareProposal(ctx, &abcitypes.RequestPrepareProposal{Height: 1, MaxTxBytes: 1 << 20})
+	assert.NoError(t, err)
+	assert.Len(t, prepared.Txs, 1)
+	assignment := &types.Transaction{}
+	assert.NoError(t, proto.Unmarshal(prepared.Txs[0], assignment))
+	datasources := assignment.GetAssignmentData().Datasources
+	assert.Len(t, datasources, 2)
+	assert.Equal(t, "binance/btc.eth", datasources[0].Datasource)
+	for _, entry := range datasources {
+		assert.Len(t, entry.Validators, assignmentReplication)
+	}
+
+	// Every node computes the same assignment
+	process, err := app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: 1, Txs: prepared.Txs})
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_ACCEPT, process.Status)
+	process, err = app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, process.Status)
+
+	res := runBlock(t, app, 1, prepared.Txs...)
+	assert.Equal(t, CodeTypeOK, res.TxResults[0].Code)
+	for _, datasource := range datasources {
+		for _, owner := range datasource.Validators {
+			assigned, err := app.Assignment(owner)
+			assert.NoError(t, err)
+			assert.Contains(t, assigned, datasource.Datasource)
+		}
+	}
+
+	// The block hash seeding the next assignment is part of the app hash
+	appHash := func(blockHash string) []byte {
+		res, err := app.FinalizeBlock(ctx, &abcitypes.RequestFinalizeBlock{Height: 2, Hash: []byte(blockHash)})
+		assert.NoError(t, err)
+		app.onGoingBlock.Discard()
+		return res.AppHash
+	}
+	assert.NotEqual(t, appHash("block 2"), appHash("another block 2"))
+
+	// The interval closed at height 2 is collected under the assignment of block 1
+	entry := datasources[0]
+	var outsider ed25519.PrivateKey
+	submit := make([][]byte, 0)
+	for _, key := range keys {
+		if slices.Contains(entry.Validators, ownerOf(key)) {
+			submit = append(submit, verificationTx(t, key, 0, entry.Datasource, 2, "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"))
+		} else {
+			outsider = key
+		}
+	}
+	submit = append(submit, verificationTx(t, outsider, 0, entry.Datasource, 2, "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"))
+
+	runBlock(t, app, 2)
+	res = runBlock(t, app, 3, submit...)
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeOK, CodeTypeOK, CodeTypeNotAssigned}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code, res.TxResults[3].Code,
+	})
+	view(db, func(txn *badger.Txn) error {
+		// All three assigned validators agreed, 30 out of their 30
+		round, err := getRound(txn, entry.Datasource, 2)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, round.Attestation)
+		assert.Len(t, round.Agreeing, assignmentReplication)
+		return nil
+	})
+}
+
+func TestProposalLayout(t *testing.T) {
+	ctx := context.Background()
+	_, alice, _ := ed25519.GenerateKey(nil)
+	app := NewVerificationApp(newTestDB(t))
+	_, err := app.InitChain(ctx, &abcitypes.RequestInitChain{
+		AppStateBytes: []byte(`{"accounts": [{"owner": "` + ownerOf(alice) + `", "balance": "100"}]}`),
+	})
+	assert.NoError(t, err)
+
+	send := func(nonce uint64) []byte {
+		return signedTx(t, alice, nonce, &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(alice)})
+	}
+	oversized := make([]byte, maxTxBytes+1)
+
+	// Out of order nonces, repeated and oversized transactions are left out
+	prepared, err := app.PrepareProposal(ctx, &abcitypes.RequestPrepareProposal{
+		Height:     1,
+		MaxTxBytes: 1 << 20,
+		Txs:        [][]byte{send(0), send(0), send(2), oversized, send(1)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{send(0), send(1)}, prepared.Txs)
+
+	// Transactions past MaxTxBytes don't make it either
+	prepared, err = app.PrepareProposal(ctx, &abcitypes.RequestPrepareProposal{
+		Height:     1,
+		MaxTxBytes: int64(len(send(0))),
+		Txs:        [][]byte{send(0), send(1)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{send(0)}, prepared.Txs)
+
+	for _, txs := range [][][]byte{
+		{send(1), send(0)},
+		{send(0), send(0)},
+		{oversized},
+		{[]byte("garbage")},
+	} {
+		res, err := app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: 1, Txs: txs})
+		assert.NoError(t, err)
+		assert.Equal(t, abcitypes.ResponseProcessProposal_REJECT, res.Status)
+	}
+
+	check, err := app.CheckTx(ctx, &abcitypes.RequestCheckTx{Tx: oversized})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeTxTooLarge, check.Code)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	source := NewVerificationApp(newTestDB(t))
+	// Tiny chunks so the snapshot spans several of them
+	assert.NoError(t, source.EnableSnapshots(SnapshotConfig{Dir: t.TempDir(), Interval: 2, KeepRecent: 1, ChunkSize: 64}))
+	_, err := source.InitChain(ctx, &abcitypes.RequestInitChain{
+		ChainId:       testChainID,
+		AppStateBytes: []byte(`{"accounts": [{"owner": "` + ownerOf(alice) + `", "balance": "100"}]}`),
+	})
+	assert.NoError(t, err)
+	for height := int64(1); height <= 4; height++ {
+		runBlock(t, source, height, signedTx(t, alice, uint64(height-1), &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(bob)}))
+	}
+	source.WaitSnapshots()
+
+	// Only the snapshot at height 4 is retained
+	list, err := source.ListSnapshots(ctx, &abcitypes.RequestListSnapshots{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+	assert.Equal(t, uint64(4), snapshot.Height)
+	assert.Greater(t, snapshot.Chunks, uint32(1))
+
+	info, err := source.Info(ctx, &abcitypes.RequestInfo{})
+	assert.NoError(t, err)
+	chunks := make([][]byte, snapshot.Chunks)
+	for i := range chunks {
+		res, err := source.LoadSnapshotChunk(ctx, &abcitypes.RequestLoadSnapshotChunk{Height: 4, Format: snapshot.Format, Chunk: uint32(i)})
+		assert.NoError(t, err)
+		chunks[i] = res.Chunk
+	}
+
+	restoreFrom := func(snapshot *abcitypes.Snapshot, chunks [][]byte, appHash []byte, tamper bool) (*VerificationApp, abcitypes.ResponseApplySnapshotChunk_Result) {
+		app := NewVerificationApp(newTestDB(t))
+		offer, err := app.OfferSnapshot(ctx, &abcitypes.RequestOfferSnapshot{Snapshot: snapshot, AppHash: appHash})
+		assert.NoError(t, err)
+		assert.Equal(t, abcitypes.ResponseOfferSnapshot_ACCEPT, offer.Result)
+
+		var result abcitypes.ResponseApplySnapshotChunk_Result
+		for i, chunk := range chunks {
+			if tamper && i == 0 {
+				res, err := app.ApplySnapshotChunk(ctx, &abcitypes.RequestApplySnapshotChunk{Index: 0, Chunk: []byte("tampered"), Sender: "evil"})
+				assert.NoError(t, err)
+				assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_RETRY, res.Result)
+				assert.Equal(t, []string{"evil"}, res.RejectSenders)
+			}
+			res, err := app.ApplySnapshotChunk(ctx, &abcitypes.RequestApplySnapshotChunk{Index: uint32(i), Chunk: chunk})
+			assert.NoError(t, err)
+			result = res.Result
+		}
+		return app, result
+	}
+	restore := func(appHash []byte, tamper bool) (*VerificationApp, abcitypes.ResponseApplySnapshotChunk_Result) {
+		return restoreFrom(snapshot, chunks, appHash, tamper)
+	}
+
+	restored, result := restore(info.LastBlockAppHash, true)
+	assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_ACCEPT, result)
+	restoredInfo, err := restored.Info(ctx, &abcitypes.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, info.LastBlockHeight, restoredInfo.LastBlockHeight)
+	assert.Equal(t, info.LastBlockAppHash, restoredInfo.LastBlockAppHash)
+	assert.Equal(t, uint64(4), queryAccount(t, restored, ownerOf(bob)).Balance)
+	assert.Equal(t, testChainID, restored.chainID)
+
+	// The restored node carries on from there
+	res := runBlock(t, restored, 5, signedTx(t, alice, 4, &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(bob)}))
+	assert.Equal(t, CodeTypeOK, res.TxResults[0].Code)
+
+	// A snapshot that does not match the app hash from the light client is thrown away
+	_, result = restore([]byte("some other app hash"), false)
+	assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_REJECT_SNAPSHOT, result)
+
+	// So is one whose chunks match its metadata but which changes the chain ID or adds keys outside the app hash
+	forge := func(change func(key, value []byte) []byte) (*abcitypes.Snapshot, [][]byte) {
+		var chunk []byte
+		for _, original := range chunks {
+			assert.NoError(t, readPairs(original, func(key, value []byte) error {
+				chunk = change(key, value)
+				return nil
+			}))
+		}
+		hash := sha256.Sum256(chunk)
+		metadata, err := proto.Marshal(&types.SnapshotMetadata{ChunkHashes: [][]byte{hash[:]}})
+		assert.NoError(t, err)
+		forged := &abcitypes.Snapshot{Height: 4, Format: snapshotFormat, Chunks: 1, Hash: snapshotHash([][]byte{hash[:]}), Metadata: metadata}
+		return forged, [][]byte{chunk}
+	}
+	var pairs []byte
+	forged, forgedChunks := forge(func(key, value []byte) []byte {
+		pairs = appendPair(pairs, key, value)
+		return pairs
+	})
+	_, result = restoreFrom(forged, forgedChunks, info.LastBlockAppHash, false)
+	assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_ACCEPT, result)
+	pairs = nil
+	forged, forgedChunks = forge(func(key, value []byte) []byte {
+		if bytes.Equal(key, chainIDKey) {
+			value = []byte("another-chain")
+		}
+		pairs = appendPair(pairs, key, value)
+		return pairs
+	})
+	_, result = restoreFrom(forged, forgedChunks, info.LastBlockAppHash, false)
+	assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_REJECT_SNAPSHOT, result)
+	pairs = appendPair(nil, lastHeightKey, []byte{0, 0, 0, 0, 0, 0, 0, 9})
+	forged, forgedChunks = forge(func(key, value []byte) []byte {
+		pairs = appendPair(pairs, key, value)
+		return pairs
+	})
+	_, result = restoreFrom(forged, forgedChunks, info.LastBlockAppHash, false)
+	assert.Equal(t, abcitypes.ResponseApplySnapshotChunk_REJECT_SNAPSHOT, result)
+
+	offer, err := NewVerificationApp(newTestDB(t)).OfferSnapshot(ctx, &abcitypes.RequestOfferSnapshot{
+		Snapshot: &abcitypes.Snapshot{Height: 4, Format: snapshotFormat + 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseOfferSnapshot_REJECT_FORMAT, offer.Result)
+}
+
+func TestQueryRouter(t *testing.T) {
+	ctx := context.Background()
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	app := NewVerificationApp(newTestDB(t))
+	keys := testValidators(t, app, 1, `{"accounts": [{"owner": "`+ownerOf(alice)+`", "balance": "100"}]}`)
+
+	const datasource = "ethereum/blocks"
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	send := func(nonce uint64) []byte {
+		return signedTx(t, alice, nonce, &types.NormalTransactionData{Amount: 10, SentTo: ownerOf(bob)})
+	}
+	first := runBlock(t, app, 1, send(0))
+	second := runBlock(t, app, 2, send(1), verificationTx(t, keys[0], 0, datasource, 1, c))
+
+	query := func(req *abcitypes.RequestQuery) *abcitypes.ResponseQuery {
+		res, err := app.Query(ctx, req)
+		assert.NoError(t, err)
+		return res
+	}
+	balance := func(height int64) uint64 {
+		res := query(&abcitypes.RequestQuery{Path: "/account/" + ownerOf(bob), Height: height, Prove: true})
+		assert.Equal(t, CodeTypeOK, res.Code)
+		assert.Equal(t, height, res.Height)
+		account := &types.Account{}
+		assert.NoError(t, proto.Unmarshal(res.Value, account))
+
+		// The proof ties the answer to the app hash of that height
+		appHash := first.AppHash
+		if height == 2 {
+			appHash = second.AppHash
+		}
+		keyPath := merkle.KeyPath{}.AppendKey(res.Key, merkle.KeyEncodingURL).String()
+		assert.NoError(t, ProofRuntime().VerifyValue(res.ProofOps, appHash, keyPath, res.Value))
+		assert.Error(t, ProofRuntime().VerifyValue(res.ProofOps, appHash, keyPath, []byte("forged")))
+		return account.Balance
+	}
+	assert.Equal(t, uint64(10), balance(1))
+	assert.Equal(t, uint64(20), balance(2))
+	assert.Equal(t, uint64(20), queryAccount(t, app, ownerOf(bob)).Balance)
+
+	res := query(&abcitypes.RequestQuery{Path: "/verification/" + c})
+	assert.Equal(t, CodeTypeOK, res.Code)
+	verified := &types.VerifiedCid{}
+	assert.NoError(t, proto.Unmarshal(res.Value, verified))
+	assert.Equal(t, datasource, verified.Datasource)
+	assert.Equal(t, CodeTypeNotFound, query(&abcitypes.RequestQuery{Path: "/verification/" + c, Height: 1}).Code)
+
+	res = query(&abcitypes.RequestQuery{Path: "/datasource/" + datasource + "/latest"})
+	assert.Equal(t, CodeTypeOK, res.Code)
+	round := &types.VerificationRound{}
+	assert.NoError(t, proto.Unmarshal(res.Value, round))
+	assert.Equal(t, []string{c}, round.Cids)
+	assert.Equal(t, CodeTypeNotFound, query(&abcitypes.RequestQuery{Path: "/datasource/binance/latest"}).Code)
+
+	res = query(&abcitypes.RequestQuery{Path: "/validators"})
+	validators := &types.ValidatorSet{}
+	assert.NoError(t, proto.Unmarshal(res.Value, validators))
+	assert.Len(t, validators.Validators, 1)
+	assert.Equal(t, ownerOf(keys[0]), validators.Validators[0].Owner)
+
+	res = query(&abcitypes.RequestQuery{Path: "/params"})
+	params := &types.Params{}
+	assert.NoError(t, proto.Unmarshal(res.Value, params))
+	assert.Equal(t, uint32(assignmentReplication), params.AssignmentReplication)
+
+	assert.Equal(t, CodeTypeInvalidOwner, query(&abcitypes.RequestQuery{Path: "/account/nobody"}).Code)
+	assert.Equal(t, CodeTypeUnknownQuery, query(&abcitypes.RequestQuery{Path: "/unknown"}).Code)
+	assert.Equal(t, CodeTypeInvalidHeight, query(&abcitypes.RequestQuery{Path: "/params", Height: 3}).Code)
+}
+
+// stakingTx returns the encoding of a staking transaction signed with the given ed25519 key
+func stakingTx(t *testing.T, key ed25519.PrivateKey, nonce uint64, action types.StakingAction, amount uint64) []byte {
+	tx := types.NewStakingTransaction(nonce, action, amount)
+	assert.NoError(t, types.SignEd25519(tx, key))
+	raw, err := proto.Marshal(tx)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestStaking(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 1, `{"accounts": [{"owner": "`+ownerOf(alice)+`", "balance": "10000000"}]}`)
+	validator := func(key ed25519.PrivateKey) *types.Validator {
+		var validator *types.Validator
+		view(db, func(txn *badger.Txn) (err error) {
+			validator, err = getValidator(txn, ownerOf(key))
+			return err
+		})
+		return validator
+	}
+
+	res := runBlock(t, app, 1,
+		stakingTx(t, alice, 0, types.StakingAction_Join, 3_000_000),
+		stakingTx(t, alice, 1, types.StakingAction_Join, 1_000_000),
+		stakingTx(t, bob, 0, types.StakingAction_IncreaseStake, 1_000_000),
+		stakingTx(t, alice, 2, types.StakingAction_IncreaseStake, 500_000),
+	)
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeAlreadyStaked, CodeTypeNotStaked, CodeTypeOK}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code, res.TxResults[3].Code,
+	})
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(alice.Public().(ed25519.PublicKey), 3)}, res.ValidatorUpdates)
+	assert.Equal(t, uint64(6_500_000), queryAccount(t, app, ownerOf(alice)).Balance)
+
+	// The validator set of the application follows the votes CometBFT collects
+	runBlock(t, app, 2)
+	runBlock(t, app, 3)
+	assert.Nil(t, validator(alice))
+	runBlock(t, app, 4)
+	assert.Equal(t, int64(3), validator(alice).Power)
+
+	res = runBlock(t, app, 5,
+		stakingTx(t, alice, 3, types.StakingAction_Unbond, 3_000_000),
+		stakingTx(t, alice, 4, types.StakingAction_Unbond, 3_500_000),
+		stakingTx(t, keys[0], 0, types.StakingAction_Unbond, 10_000_000),
+	)
+	assert.Equal(t, []uint32{CodeTypeInvalidAmount, CodeTypeOK, CodeTypeLastValidator}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code,
+	})
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(alice.Public().(ed25519.PublicKey), 0)}, res.ValidatorUpdates)
+	runBlock(t, app, 8)
+	assert.Nil(t, validator(alice))
+	assert.Equal(t, int64(10), validator(keys[0]).Power)
+
+	// Unbonded stake is locked for the unbonding period
+	runBlock(t, app, 5+unbondingBlocks-1)
+	assert.Equal(t, uint64(6_500_000), queryAccount(t, app, ownerOf(alice)).Balance)
+	runBlock(t, app, 5+unbondingBlocks)
+	assert.Equal(t, uint64(10_000_000), queryAccount(t, app, ownerOf(alice)).Balance)
+}
+
+func TestJoinThenPropose(t *testing.T) {
+	ctx := context.Background()
+	_, alice, _ := ed25519.GenerateKey(nil)
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 1, `{"accounts": [{"owner": "`+ownerOf(alice)+`", "balance": "10000000"}], "datasources": ["binance/btc.eth"]}`)
+
+	// Alice's join reaches the validator set of the application at height 4, CometBFT has her vote for height 3
+	res := runBlock(t, app, 1, stakingTx(t, alice, 0, types.StakingAction_Join, 3_000_000))
+	assert.Equal(t, CodeTypeOK, res.TxResults[0].Code)
+	runBlock(t, app, 2)
+	runBlock(t, app, 3)
+
+	app.SetSummarySource(func(start, end time.Time) []*types.VerificationTransactionData {
+		return []*types.VerificationTransactionData{{Datasource: "binance/btc.eth", Cids: []string{"bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"}}}
+	})
+	extension, err := app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: 3})
+	assert.NoError(t, err)
+	commit := abcitypes.ExtendedCommitInfo{Votes: []abcitypes.ExtendedVoteInfo{
+		extendedVote(keys[0], 3, extension.VoteExtension),
+		extendedVote(alice, 3, extension.VoteExtension),
+	}}
+
+	prepared, err := app.PrepareProposal(ctx, &abcitypes.RequestPrepareProposal{Height: 4, MaxTxBytes: 1 << 20, LocalLastCommit: commit})
+	assert.NoError(t, err)
+	assert.Len(t, prepared.Txs, 2)
+	assignment := &types.Transaction{}
+	assert.NoError(t, proto.Unmarshal(prepared.Txs[1], assignment))
+	assert.ElementsMatch(t, []string{ownerOf(alice), ownerOf(keys[0])}, assignment.GetAssignmentData().Datasources[0].Validators)
+
+	process, err := app.ProcessProposal(ctx, &abcitypes.RequestProcessProposal{Height: 4, Txs: prepared.Txs, ProposedLastCommit: proposedLastCommit(commit)})
+	assert.NoError(t, err)
+	assert.Equal(t, abcitypes.ResponseProcessProposal_ACCEPT, process.Status)
+
+	res = runBlock(t, app, 4, prepared.Txs...)
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeOK}, []uint32{res.TxResults[0].Code, res.TxResults[1].Code})
+	assigned, err := app.Assignment(ownerOf(alice))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"binance/btc.eth"}, assigned)
+}
+
+// signedMessage returns the encoding of a transaction signed with the given ed25519 key
+func signedMessage(t *testing.T, key ed25519.PrivateKey, tx *types.Transaction) []byte {
+	assert.NoError(t, types.SignEd25519(tx, key))
+	raw, err := proto.Marshal(tx)
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestResourceRewards(t *testing.T) {
+	_, outsider, _ := ed25519.GenerateKey(nil)
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 2, "")
+
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	runBlock(t, app, 2,
+		verificationTx(t, keys[0], 0, "ethereum/blocks", 1, c),
+		verificationTx(t, keys[1], 0, "ethereum/blocks", 1, c),
+	)
+
+	breakdown := types.NewResourceTransaction(1, 0, 100, 100)
+	breakdown.GetResourceData().BandwidthUnits = 60
+	breakdown.GetResourceData().StorageUnits = 40
+	mismatch := types.NewResourceTransaction(2, 0, 100, 100)
+	mismatch.GetResourceData().UptimeUnits = 1
+	res := runBlock(t, app, 3,
+		signedMessage(t, keys[0], types.NewResourceTransaction(1, 0, 900, 900)),
+		signedMessage(t, keys[0], types.NewResourceTransaction(2, 0, 300, 300)), // Replaces the first report
+		signedMessage(t, keys[1], breakdown),
+		signedMessage(t, keys[1], mismatch),
+		signedMessage(t, keys[1], types.NewResourceTransaction(3, 0, 100, 200)),
+		signedMessage(t, keys[1], types.NewResourceTransaction(4, 1, 100, 100)),
+		signedMessage(t, outsider, types.NewResourceTransaction(0, 0, 100, 100)),
+	)
+	assert.Equal(t, []uint32{
+		CodeTypeOK, CodeTypeOK, CodeTypeOK, CodeTypeInvalidAmount, CodeTypeInvalidAmount, CodeTypeInvalidEpoch, CodeTypeUnverifiedResources,
+	}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code, res.TxResults[3].Code,
+		res.TxResults[4].Code, res.TxResults[5].Code, res.TxResults[6].Code,
+	})
+
+	// Nothing is paid before the end of the epoch
+	runBlock(t, app, epochBlocks-1)
+	assert.Equal(t, uint64(0), queryAccount(t, app, ownerOf(keys[0])).Balance)
+	runBlock(t, app, epochBlocks)
+	assert.Equal(t, epochReward*3/4, queryAccount(t, app, ownerOf(keys[0])).Balance)
+	assert.Equal(t, epochReward/4, queryAccount(t, app, ownerOf(keys[1])).Balance)
+
+	query, err := app.Query(context.Background(), &abcitypes.RequestQuery{Path: "/rewards/0"})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeOK, query.Code)
+	ledger := &types.RewardLedger{}
+	assert.NoError(t, proto.Unmarshal(query.Value, ledger))
+	assert.Equal(t, epochReward, ledger.Distributed)
+	assert.Equal(t, uint64(400), ledger.TotalUnits)
+	assert.Len(t, ledger.Rewards, 2)
+	assert.Equal(t, uint64(1), ledger.Rewards[0].VerifiedRounds)
+
+	view(db, func(txn *badger.Txn) error {
+		supply, err := getCounter(txn, supplyKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 20*powerReduction+epochReward, supply)
+		return nil
+	})
+}
+
+func TestInflatedResourceReport(t *testing.T) {
+	app := NewVerificationApp(newTestDB(t))
+	keys := testValidators(t, app, 2, "")
+
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	runBlock(t, app, 2,
+		verificationTx(t, keys[0], 0, "ethereum/blocks", 1, c),
+		verificationTx(t, keys[1], 0, "ethereum/blocks", 1, c),
+	)
+	// Both agreed with a single round, the first claims far more than that is worth
+	runBlock(t, app, 3,
+		signedMessage(t, keys[0], types.NewResourceTransaction(1, 0, 1_000_000, 1_000_000)),
+		signedMessage(t, keys[1], types.NewResourceTransaction(1, 0, 500, 500)),
+	)
+	runBlock(t, app, epochBlocks)
+	assert.Equal(t, epochReward*2/3, queryAccount(t, app, ownerOf(keys[0])).Balance)
+	assert.Equal(t, epochReward/3, queryAccount(t, app, ownerOf(keys[1])).Balance)
+
+	query, err := app.Query(context.Background(), &abcitypes.RequestQuery{Path: "/rewards/0"})
+	assert.NoError(t, err)
+	ledger := &types.RewardLedger{}
+	assert.NoError(t, proto.Unmarshal(query.Value, ledger))
+	assert.Equal(t, uint64(1_500), ledger.TotalUnits)
+	for _, reward := range ledger.Rewards {
+		if reward.Owner == ownerOf(keys[0]) {
+			assert.Equal(t, uint64(1_000_000), reward.ReportedUnits)
+			assert.Equal(t, unitsPerVerifiedRound, reward.RewardedUnits)
+		}
+	}
+}
+
+func TestWrongDataTolerance(t *testing.T) {
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 4, `{"slashing": {"wrongDataSlash": 1000, "maxMissedBlocks": 50, "maxWrongRounds": 2, "jailBlocks": 5}}`)
+	stake := func(i int) *types.Stake {
+		var stake *types.Stake
+		view(db, func(txn *badger.Txn) (err error) {
+			stake, err = getStake(txn, ownerOf(keys[i]))
+			return err
+		})
+		return stake
+	}
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	other := "bafkreidvbhs33ighmljlvr7zbv2ywwzcmp5adtf4kqvlly67cy56bdtmve"
+	// round submits the CIDs of each validator in that order, the quorum is reached with the third agreeing one
+	round := func(height int64, cids [4]string, order ...int) *abcitypes.ResponseFinalizeBlock {
+		txs := make([][]byte, 0, len(order))
+		for _, i := range order {
+			txs = append(txs, verificationTx(t, keys[i], uint64(height-2), "ethereum/blocks", height-1, cids[i]))
+		}
+		res := runBlock(t, app, height, txs...)
+		for _, result := range res.TxResults {
+			assert.Equal(t, CodeTypeOK, result.Code)
+		}
+		return res
+	}
+
+	// Two honest validators whose windows were cut a little apart from the others disagree once, one of them
+	// before the round was decided and the other after. Neither is slashed nor jailed.
+	res := round(2, [4]string{c, c, c, other}, 3, 0, 1, 2)
+	assert.Empty(t, res.ValidatorUpdates)
+	res = round(3, [4]string{c, c, other, c}, 0, 1, 3, 2)
+	assert.Empty(t, res.ValidatorUpdates)
+	for _, result := range res.TxResults {
+		assert.Empty(t, eventsOfType(t, result.Events, EventValidatorSlashed))
+	}
+	view(db, func(txn *badger.Txn) error {
+		round, err := getRound(txn, "ethereum/blocks", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{ownerOf(keys[2])}, round.Disagreeing)
+		return nil
+	})
+	for i := range keys {
+		assert.False(t, stake(i).Jailed)
+		assert.Equal(t, 10*powerReduction, stake(i).Bonded)
+	}
+	assert.Equal(t, uint64(1), stake(3).WrongSubmissions)
+	assert.Equal(t, int64(0), stake(3).WrongRounds)
+	assert.Equal(t, uint64(0), stake(2).WrongSubmissions)
+
+	// Disagreeing with maxWrongRounds rounds in a row is wrong data
+	res = round(4, [4]string{c, c, c, other}, 3, 0, 1, 2)
+	assert.Empty(t, res.ValidatorUpdates)
+	assert.Equal(t, int64(1), stake(3).WrongRounds)
+	res = round(5, [4]string{c, c, c, other}, 3, 0, 1, 2)
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(keys[3].Public().(ed25519.PublicKey), 0)}, res.ValidatorUpdates)
+	assert.True(t, stake(3).Jailed)
+	assert.Equal(t, 9*powerReduction, stake(3).Bonded)
+	assert.Equal(t, uint64(3), stake(3).WrongSubmissions)
+	assert.Equal(t, int64(0), stake(3).WrongRounds)
+}
+
+func TestSlashing(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	keys := testValidators(t, app, 4, `{"slashing": {"doubleSignSlash": 5000, "wrongDataSlash": 1000, "downtimeSlash": 100, "maxMissedBlocks": 2, "maxWrongRounds": 1, "jailBlocks": 5}}`)
+	pubKey := func(i int) ed25519.PublicKey { return keys[i].Public().(ed25519.PublicKey) }
+	stake := func(i int) *types.Stake {
+		var stake *types.Stake
+		view(db, func(txn *badger.Txn) (err error) {
+			stake, err = getStake(txn, ownerOf(keys[i]))
+			return err
+		})
+		return stake
+	}
+	validator := func(i int) abcitypes.Validator {
+		return abcitypes.Validator{Address: cmted25519.PubKey(pubKey(i)).Address(), Power: 10}
+	}
+	finalize := func(req *abcitypes.RequestFinalizeBlock) *abcitypes.ResponseFinalizeBlock {
+		res, err := app.FinalizeBlock(ctx, req)
+		assert.NoError(t, err)
+		_, err = app.Commit(ctx, &abcitypes.RequestCommit{})
+		assert.NoError(t, err)
+		return res
+	}
+
+	// Submitting something else than the quorum costs 10% and a few blocks in jail
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	other := "bafkreidvbhs33ighmljlvr7zbv2ywwzcmp5adtf4kqvlly67cy56bdtmve"
+	res := runBlock(t, app, 2,
+		verificationTx(t, keys[3], 0, "ethereum/blocks", 1, other),
+		verificationTx(t, keys[0], 0, "ethereum/blocks", 1, c),
+		verificationTx(t, keys[1], 0, "ethereum/blocks", 1, c),
+		verificationTx(t, keys[2], 0, "ethereum/blocks", 1, c),
+	)
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(pubKey(3), 0)}, res.ValidatorUpdates)
+	assert.Equal(t, 9*powerReduction, stake(3).Bonded)
+	assert.Equal(t, []map[string]string{{
+		"owner": ownerOf(keys[3]), "reason": "wrong data", "burnt": strconv.FormatUint(powerReduction, 10), "jailed": "true", "height": "2",
+	}}, eventsOfType(t, res.TxResults[3].Events, EventValidatorSlashed))
+	assert.Equal(t, uint64(1), stake(3).WrongSubmissions)
+	isValidator := func(address []byte) bool {
+		validator, err := app.IsValidator(address)
+		assert.NoError(t, err)
+		return validator
+	}
+	assert.False(t, isValidator(validator(3).Address))
+	assert.True(t, isValidator(validator(0).Address))
+	assert.False(t, isValidator([]byte("unknown")))
+
+	unjail := func(i int, nonce uint64) []byte {
+		return stakingTx(t, keys[i], nonce, types.StakingAction_Unjail, 0)
+	}
+	res = runBlock(t, app, 3, unjail(3, 1), unjail(0, 1), stakingTx(t, keys[3], 2, types.StakingAction_Unjail, 1))
+	assert.Equal(t, []uint32{CodeTypeStillJailed, CodeTypeNotJailed, CodeTypeInvalidAmount}, []uint32{
+		res.TxResults[0].Code, res.TxResults[1].Code, res.TxResults[2].Code,
+	})
+	res = runBlock(t, app, 7, unjail(3, 3))
+	assert.Equal(t, CodeTypeOK, res.TxResults[0].Code)
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(pubKey(3), 9)}, res.ValidatorUpdates)
+
+	// Missing two commits in a row is downtime
+	absent := abcitypes.CommitInfo{Votes: []abcitypes.VoteInfo{
+		{Validator: validator(0), BlockIdFlag: cmtproto.BlockIDFlagAbsent},
+		{Validator: validator(1), BlockIdFlag: cmtproto.BlockIDFlagCommit},
+	}}
+	res = finalize(&abcitypes.RequestFinalizeBlock{Height: 8, DecidedLastCommit: absent})
+	assert.Empty(t, res.ValidatorUpdates)
+	assert.Equal(t, int64(1), stake(0).MissedBlocks)
+	res = finalize(&abcitypes.RequestFinalizeBlock{Height: 9, DecidedLastCommit: absent})
+	assert.Equal(t, []abcitypes.ValidatorUpdate{abcitypes.Ed25519ValidatorUpdate(pubKey(0), 0)}, res.ValidatorUpdates)
+	assert.Equal(t, 9_900_000, int(stake(0).Bonded))
+	assert.Equal(t, int64(0), stake(0).MissedBlocks)
+
+	// Double signing costs half the stake, including what is being unbonded
+	runBlock(t, app, 10, stakingTx(t, keys[1], 1, types.StakingAction_Unbond, 4*powerReduction))
+	doubleSign := func(i int) abcitypes.Misbehavior {
+		return abcitypes.Misbehavior{Type: abcitypes.MisbehaviorType_DUPLICATE_VOTE, Validator: validator(i), Height: 9}
+	}
+	res = finalize(&abcitypes.RequestFinalizeBlock{Height: 11, Misbehavior: []abcitypes.Misbehavior{doubleSign(1), doubleSign(2), doubleSign(3)}})
+	assert.Equal(t, 3*powerReduction, stake(1).Bonded)
+	assert.Equal(t, 5*powerReduction, stake(1).Slashed)
+	view(db, func(txn *badger.Txn) error {
+		unbonding := &types.Unbonding{}
+		_, err := getMessage(txn, scheduledKey(unbondingPrefix, 10+unbondingBlocks, ownerOf(keys[1])), unbonding)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*powerReduction, unbonding.Amount)
+		return nil
+	})
+	// The last validator with power is slashed but stays
+	assert.True(t, stake(2).Jailed)
+	assert.False(t, stake(3).Jailed)
+	powers := make(map[string]int64)
+	for _, update := range res.ValidatorUpdates {
+		powers[string(update.PubKey.GetEd25519())] = update.Power
+	}
+	assert.Equal(t, map[string]int64{string(pubKey(1)): 0, string(pubKey(2)): 0, string(pubKey(3)): 4}, powers)
+
+	query, err := app.Query(ctx, &abcitypes.RequestQuery{Path: "/stake/" + ownerOf(keys[3])})
+	assert.NoError(t, err)
+	queried := &types.Stake{}
+	assert.NoError(t, proto.Unmarshal(query.Value, queried))
+	assert.Equal(t, stake(3).Bonded, queried.Bonded)
+}
+
+// eventsOfType returns the attributes of the events of a type, in order, checking they are all indexed
+func eventsOfType(t *testing.T, events []abcitypes.Event, eventType string) []map[string]string {
+	found := make([]map[string]string, 0)
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		attributes := make(map[string]string)
+		for _, attribute := range event.Attributes {
+			assert.True(t, attribute.Index, attribute.Key)
+			attributes[attribute.Key] = attribute.Value
+		}
+		found = append(found, attributes)
+	}
+	return found
+}
+
+func TestEvents(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	app := NewVerificationApp(newTestDB(t))
+	keys := testValidators(t, app, 1, `{"accounts": [{"owner": "`+ownerOf(alice)+`", "balance": "100"}]}`)
+
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	res := runBlock(t, app, 2,
+		signedTx(t, alice, 0, &types.NormalTransactionData{Amount: 30, SentTo: ownerOf(keys[0])}),
+		signedTx(t, alice, 1, &types.NormalTransactionData{Amount: 300, SentTo: ownerOf(keys[0])}),
+		verificationTx(t, keys[0], 0, "ethereum/blocks", 1, c),
+	)
+	assert.Equal(t, []map[string]string{{"sender": ownerOf(alice), "recipient": ownerOf(keys[0]), "amount": "30"}},
+		eventsOfType(t, res.TxResults[0].Events, EventTransfer))
+	// Rejected transactions have no events
+	assert.Empty(t, res.TxResults[1].Events)
+
+	events := res.TxResults[2].Events
+	assert.Equal(t, []map[string]string{{
+		"owner": ownerOf(keys[0]), "datasource": "ethereum/blocks", "timestamp": "1", "attestation": types.AttestationDigest([]string{c}),
+	}}, eventsOfType(t, events, EventVerificationSubmitted))
+	assert.Equal(t, []map[string]string{{"cid": c, "datasource": "ethereum/blocks", "timestamp": "1", "height": "2"}},
+		eventsOfType(t, events, EventCidVerified))
+
+	// Rewards are paid by the block that ends the epoch
+	runBlock(t, app, 3, signedMessage(t, keys[0], types.NewResourceTransaction(1, 0, 10, 10)))
+	res = runBlock(t, app, epochBlocks)
+	assert.Equal(t, []map[string]string{{"owner": ownerOf(keys[0]), "epoch": "0", "amount": strconv.FormatUint(epochReward, 10)}},
+		eventsOfType(t, res.Events, EventRewardPaid))
+}
+
+func TestFees(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	app.SetMinGasPrice(1)
+	keys := testValidators(t, app, 1, `{"accounts": [{"owner": "`+ownerOf(alice)+`", "balance": "100000"}]}`)
+
+	send := func(nonce, amount, fee uint64) []byte {
+		return signedMessage(t, alice, &types.Transaction{
+			Type:    types.TransactionType_NormalTransaction,
+			Nonce:   nonce,
+			Fee:     fee,
+			Version: types.SchemaVersion,
+			Data:    &types.Transaction_NormalData{NormalData: &types.NormalTransactionData{Amount: amount, SentTo: ownerOf(bob)}},
+		})
+	}
+	checkTx := func(tx []byte) *abcitypes.ResponseCheckTx {
+		res, err := app.CheckTx(context.Background(), &abcitypes.RequestCheckTx{Tx: tx})
+		assert.NoError(t, err)
+		return res
+	}
+	assert.Equal(t, CodeTypeFeeTooLow, checkTx(send(0, 1, uint64(types.GasNormal)-1)).Code)
+	assert.Equal(t, CodeTypeInsufficientFunds, checkTx(send(0, 1, 200_000)).Code)
+	res := checkTx(send(0, 1, uint64(types.GasNormal)))
+	assert.Equal(t, CodeTypeOK, res.Code)
+	assert.Equal(t, types.GasNormal, res.GasWanted)
+	// The fees waiting in the mempool are spent already
+	assert.Equal(t, CodeTypeInsufficientFunds, checkTx(send(1, 1, 95_000)).Code)
+	assert.Equal(t, CodeTypeOK, checkTx(send(1, 1, 90_000)).Code)
+
+	// The minimum gas price is not a consensus rule
+	block := runBlock(t, app, 2,
+		send(0, 50_000, 10_000),
+		send(1, 50_000, 10_000), // Only 30,000 left after the fee, the fee and the nonce are still used up
+		send(2, 0, 200_000),     // Can't pay the fee
+		send(2, 1, 0),
+	)
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeInsufficientFunds, CodeTypeInsufficientFunds, CodeTypeOK}, []uint32{
+		block.TxResults[0].Code, block.TxResults[1].Code, block.TxResults[2].Code, block.TxResults[3].Code,
+	})
+	for _, result := range block.TxResults[:2] {
+		assert.Equal(t, []map[string]string{{"owner": ownerOf(alice), "fee": "10000"}},
+			eventsOfType(t, result.Events, EventFeePaid))
+	}
+	assert.Len(t, block.TxResults[1].Events, 1) // Only the fee was paid
+	assert.Zero(t, block.TxResults[2].GasUsed)
+	assert.Empty(t, eventsOfType(t, block.TxResults[3].Events, EventFeePaid))
+	assert.Equal(t, uint64(29_999), queryAccount(t, app, ownerOf(alice)).Balance)
+	assert.Equal(t, uint64(3), queryAccount(t, app, ownerOf(alice)).Nonce)
+	assert.Equal(t, uint64(50_001), queryAccount(t, app, ownerOf(bob)).Balance)
+
+	// The fees are paid out with the rewards of the epoch
+	c := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+	runBlock(t, app, 3, verificationTx(t, keys[0], 0, "ethereum/blocks", 1, c))
+	runBlock(t, app, 4, signedMessage(t, keys[0], types.NewResourceTransaction(1, 0, 10, 10)))
+	runBlock(t, app, epochBlocks)
+	assert.Equal(t, epochReward+20_000, queryAccount(t, app, ownerOf(keys[0])).Balance)
+
+	query, err := app.Query(context.Background(), &abcitypes.RequestQuery{Path: "/rewards/0"})
+	assert.NoError(t, err)
+	ledger := &types.RewardLedger{}
+	assert.NoError(t, proto.Unmarshal(query.Value, ledger))
+	assert.Equal(t, uint64(20_000), ledger.Fees)
+	assert.Equal(t, epochReward+20_000, ledger.Distributed)
+
+	view(db, func(txn *badger.Txn) error {
+		pool, err := getCounter(txn, feePoolKey)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), pool)
+		supply, err := getCounter(txn, supplyKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 10*powerReduction+100_000+epochReward, supply)
+		return nil
+	})
+}
+
+func TestPrioritise(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(nil)
+	_, bob, _ := ed25519.GenerateKey(nil)
+	_, carol, _ := ed25519.GenerateKey(nil)
+	send := func(key ed25519.PrivateKey, nonce uint64, gasPrice uint64) []byte {
+		return signedMessage(t, key, &types.Transaction{
+			Type:    types.TransactionType_NormalTransaction,
+			Nonce:   nonce,
+			Fee:     gasPrice * uint64(types.GasNormal),
+			Version: types.SchemaVersion,
+			Data:    &types.Transaction_NormalData{NormalData: &types.NormalTransactionData{Amount: 1, SentTo: ownerOf(bob)}},
+		})
+	}
+
+	bobTx := send(bob, 0, 1)
+	alice0, alice1 := send(alice, 0, 1), send(alice, 1, 5)
+	carolTx := send(carol, 0, 3)
+	garbage := []byte("not a transaction")
+	// The nonces of an owner stay in order, the price of its next transaction only counts once it is at the front
+	assert.Equal(t, [][]byte{carolTx, bobTx, alice0, alice1, garbage},
+		prioritise([][]byte{bobTx, alice0, alice1, carolTx, garbage}))
+}
+
+func TestGovernance(t *testing.T) {
+	_, outsider, _ := ed25519.GenerateKey(nil)
+	ctx := context.Background()
+	app := NewVerificationApp(newTestDB(t))
+	keys := testValidators(t, app, 3, `{"params": {"summaryIntervalBlocks": "2"}}`)
+	propose := func(key ed25519.PrivateKey, nonce uint64, changes ...string) []byte {
+		params := make([]*types.ParamChange, 0, len(changes)/2)
+		for i := 0; i+1 < len(changes); i += 2 {
+			params = append(params, &types.ParamChange{Name: changes[i], Value: changes[i+1]})
+		}
+		return signedMessage(t, key, types.NewProposalTransaction(nonce, params))
+	}
+	vote := func(key ed25519.PrivateKey, nonce uint64, id uint64, approve bool) []byte {
+		return signedMessage(t, key, types.NewVoteTransaction(nonce, id, approve))
+	}
+	codes := func(res *abcitypes.ResponseFinalizeBlock) []uint32 {
+		codes := make([]uint32, len(res.TxResults))
+		for i, r := range res.TxResults {
+			codes[i] = r.Code
+		}
+		return codes
+	}
+	proposal := func(id uint64) *types.Proposal {
+		res, err := app.Query(ctx, &abcitypes.RequestQuery{Path: "/proposal/" + strconv.FormatUint(id, 10)})
+		assert.NoError(t, err)
+		assert.Equal(t, CodeTypeOK, res.Code)
+		proposal := &types.Proposal{}
+		assert.NoError(t, proto.Unmarshal(res.Value, proposal))
+		return proposal
+	}
+
+	res := runBlock(t, app, 1,
+		propose(keys[0], 0, "collectorSlots", "4", "epochReward", "5", "downtimeSlash", "20", "assignmentReplication", "2"),
+		propose(keys[0], 1, "summaryBufferBytes", "10"),
+		propose(keys[0], 2, "noSuchParam", "1"),
+		propose(keys[0], 3, "collectorSlots", "2", "collectorSlots", "3"),
+		propose(keys[0], 4),
+		propose(outsider, 0, "collectorSlots", "2"),
+		vote(keys[1], 0, 2, true),
+		vote(keys[0], 5, 1, true),
+		vote(keys[1], 1, 1, false),
+		vote(keys[1], 2, 1, true), // Replaces the earlier vote
+	)
+	assert.Equal(t, []uint32{
+		CodeTypeOK, CodeTypeInvalidProposal, CodeTypeInvalidProposal, CodeTypeInvalidProposal, CodeTypeInvalidProposal,
+		CodeTypeNotValidator, CodeTypeUnknownProposal, CodeTypeOK, CodeTypeOK, CodeTypeOK,
+	}, codes(res))
+	assert.Equal(t, []map[string]string{{"proposal_id": "1", "proposer": ownerOf(keys[0])}},
+		eventsOfType(t, res.TxResults[0].Events, EventProposalSubmitted))
+
+	// 20 of 30 is not more than 2/3
+	assert.Equal(t, types.ProposalStatus_ProposalVoting, proposal(1).Status)
+	assert.Equal(t, int64(20), proposal(1).ApprovePower)
+	params, err := app.GovernanceParams()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), params.CollectorSlots)
+
+	res = runBlock(t, app, 2, vote(keys[2], 0, 1, true), propose(keys[1], 3, "collectorSlots", "8"))
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeOK}, codes(res))
+	assert.Equal(t, []map[string]string{{"proposal_id": "1", "status": "ProposalPassed"}},
+		eventsOfType(t, res.Events, EventProposalClosed))
+	passed := proposal(1)
+	assert.Equal(t, types.ProposalStatus_ProposalPassed, passed.Status)
+	assert.Equal(t, []int64{30, 0, 30}, []int64{passed.ApprovePower, passed.RejectPower, passed.TotalPower})
+
+	// The block that passed the proposal ran with the old parameters, the changes apply from the next one
+	params, err = app.GovernanceParams()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), params.CollectorSlots)
+
+	// Votes on closed proposals are refused, a third of the power against is enough to reject
+	res = runBlock(t, app, 3, vote(keys[0], 6, 1, true), vote(keys[0], 7, 2, false))
+	assert.Equal(t, []uint32{CodeTypeProposalClosed, CodeTypeOK}, codes(res))
+	assert.Equal(t, types.ProposalStatus_ProposalRejected, proposal(2).Status)
+	assert.Equal(t, int64(10), proposal(2).RejectPower)
+
+	params, err = app.GovernanceParams()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4), params.CollectorSlots)
+	assert.Equal(t, uint64(5), params.EpochReward)
+	assert.Equal(t, int64(2), params.SummaryIntervalBlocks)
+	query, err := app.Query(ctx, &abcitypes.RequestQuery{Path: "/params"})
+	assert.NoError(t, err)
+	current := &types.Params{}
+	assert.NoError(t, proto.Unmarshal(query.Value, current))
+	assert.Equal(t, uint64(5), current.EpochReward)
+	assert.Equal(t, uint32(20), current.Slashing.DowntimeSlash)
+	assert.Equal(t, uint32(4), current.Governance.CollectorSlots)
+	assert.Equal(t, uint32(2), current.AssignmentReplication)
+
+	// Proposals nobody approves expire with their voting period
+	runBlock(t, app, 4, propose(keys[2], 1, "updateThreshold", "3/4"))
+	runBlock(t, app, 4+votingPeriodBlocks-1)
+	assert.Equal(t, types.ProposalStatus_ProposalVoting, proposal(3).Status)
+	runBlock(t, app, 4+votingPeriodBlocks)
+	assert.Equal(t, types.ProposalStatus_ProposalRejected, proposal(3).Status)
+
+	// Only votes at the end of a summary interval carry summaries, the others leave them to the next one
+	calls := 0
+	app.SetSummarySource(func(start, end time.Time) []*types.VerificationTransactionData {
+		calls++
+		return []*types.VerificationTransactionData{{Datasource: "ethereum/blocks", Cids: []string{"bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"}}}
+	})
+	height := int64(5 + votingPeriodBlocks)
+	extension, err := app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: height})
+	assert.NoError(t, err)
+	assert.Empty(t, extension.VoteExtension)
+	extension, err = app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: height + 1})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, extension.VoteExtension)
+	assert.Equal(t, 1, calls)
+}
+
+func TestProposalLimit(t *testing.T) {
+	app := NewVerificationApp(newTestDB(t))
+	keys := testValidators(t, app, 3, "")
+	propose := func(nonce uint64) []byte {
+		change := []*types.ParamChange{{Name: "collectorSlots", Value: strconv.FormatUint(nonce+2, 10)}}
+		return signedMessage(t, keys[0], types.NewProposalTransaction(nonce, change))
+	}
+	codes := func(res *abcitypes.ResponseFinalizeBlock) []uint32 {
+		codes := make([]uint32, len(res.TxResults))
+		for i, r := range res.TxResults {
+			codes[i] = r.Code
+		}
+		return codes
+	}
+
+	res := runBlock(t, app, 1, propose(0), propose(1), propose(2), propose(3))
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeOK, CodeTypeOK, CodeTypeTooManyProposals}, codes(res))
+
+	// A closed proposal frees its place
+	res = runBlock(t, app, 2, signedMessage(t, keys[1], types.NewVoteTransaction(0, 1, false)), propose(4))
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeTooManyProposals}, codes(res))
+	res = runBlock(t, app, 3, propose(5))
+	assert.Equal(t, []uint32{CodeTypeOK}, codes(res))
+}
+
+func TestSummaryWindow(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	app := NewVerificationApp(db)
+	testValidators(t, app, 1, `{"params": {"summaryIntervalBlocks": "2"}}`)
+
+	block := func(height int64, seconds int64) {
+		_, err := app.FinalizeBlock(ctx, &abcitypes.RequestFinalizeBlock{Height: height, Time: time.Unix(seconds, 0)})
+		assert.NoError(t, err)
+		_, err = app.Commit(ctx, &abcitypes.RequestCommit{})
+		assert.NoError(t, err)
+	}
+	var windows [][2]int64
+	app.SetSummarySource(func(start, end time.Time) []*types.VerificationTransactionData {
+		windows = append(windows, [2]int64{start.Unix(), end.Unix()})
+		return nil
+	})
+	vote := func(height int64) {
+		// Every round asks again, as a validator that restarted would
+		app.extensionHeight = 0
+		_, err := app.ExtendVote(ctx, &abcitypes.RequestExtendVote{Height: height})
+		assert.NoError(t, err)
+	}
+
+	// The windows of closing votes follow each other, they end summaryDelay before the last block
+	block(1, 100)
+	vote(2)
+	vote(2)
+	block(2, 110)
+	vote(3)
+	block(3, 120)
+	vote(4)
+	block(4, 130)
+	block(5, 140)
+	vote(6)
+	assert.Equal(t, [][2]int64{{0, 95}, {0, 95}, {95, 115}, {115, 135}}, windows)
+}
+
+func TestUpgrade(t *testing.T) {
+	const binaryA, binaryB = "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy", "QmTytvFFrGE69EWw7f3bbJhzUb3F7WTsNcPnFRH6xULUJW"
+	ctx := context.Background()
+	_, outsider, _ := ed25519.GenerateKey(nil)
+	updateKeys := make([]ed25519.PrivateKey, 3)
+	owners := make([]string, len(updateKeys))
+	for i := range updateKeys {
+		_, updateKeys[i], _ = ed25519.GenerateKey(nil)
+		owners[i] = ownerOf(updateKeys[i])
+	}
+	app := NewVerificationApp(newTestDB(t))
+	testValidators(t, app, 1, `{"updateKeys": ["`+strings.Join(owners, `", "`)+`"]}`)
+	slices.Sort(owners)
+	approve := func(key ed25519.PrivateKey, nonce uint64, binaryCid string, activationHeight int64) []byte {
+		return signedMessage(t, key, types.NewUpgradeTransaction(nonce, binaryCid, activationHeight))
+	}
+	codes := func(res *abcitypes.ResponseFinalizeBlock) []uint32 {
+		codes := make([]uint32, len(res.TxResults))
+		for i, r := range res.TxResults {
+			codes[i] = r.Code
+		}
+		return codes
+	}
+
+	res := runBlock(t, app, 1,
+		approve(outsider, 0, binaryA, 200),
+		approve(updateKeys[0], 0, binaryA, 1+upgradeNoticeBlocks-1),
+		approve(updateKeys[0], 1, "not a cid", 200),
+		approve(updateKeys[0], 2, binaryA, 200),
+		approve(updateKeys[1], 0, binaryA, 300), // Another height is another plan
+	)
+	assert.Equal(t, []uint32{CodeTypeNotUpdateKey, CodeTypeInvalidUpgrade, CodeTypeInvalidCid, CodeTypeOK, CodeTypeOK}, codes(res))
+	assert.Equal(t, []map[string]string{{"approver": ownerOf(updateKeys[0]), "binary_cid": binaryA, "activation_height": "200"}},
+		eventsOfType(t, res.TxResults[3].Events, EventUpgradeApproved))
+	query, err := app.Query(ctx, &abcitypes.RequestQuery{Path: "/upgrade"})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeNotFound, query.Code)
+
+	// 2 of 3 update keys reach the default threshold of 2/3
+	res = runBlock(t, app, 2, approve(updateKeys[1], 1, binaryA, 200))
+	assert.Equal(t, []uint32{CodeTypeOK}, codes(res))
+	assert.Equal(t, []map[string]string{{"binary_cid": binaryA, "activation_height": "200"}},
+		eventsOfType(t, res.TxResults[0].Events, EventUpgradeScheduled))
+
+	// A late approval joins the plan without scheduling it again
+	res = runBlock(t, app, 3, approve(updateKeys[2], 0, binaryA, 200))
+	assert.Empty(t, eventsOfType(t, res.TxResults[0].Events, EventUpgradeScheduled))
+	query, err = app.Query(ctx, &abcitypes.RequestQuery{Path: "/upgrade"})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeTypeOK, query.Code)
+	plan := &types.UpgradePlan{}
+	assert.NoError(t, proto.Unmarshal(query.Value, plan))
+	assert.Equal(t, binaryA, plan.BinaryCid)
+	assert.Equal(t, int64(200), plan.ActivationHeight)
+	assert.Equal(t, int64(2), plan.ScheduledHeight)
+	assert.Equal(t, owners, plan.Approvers)
+
+	// Approving another binary withdraws the earlier approval, the plan is replaced once the new one is approved
+	res = runBlock(t, app, 4, approve(updateKeys[0], 3, binaryB, 400), approve(updateKeys[1], 2, binaryB, 400))
+	assert.Equal(t, []uint32{CodeTypeOK, CodeTypeOK}, codes(res))
+	assert.Empty(t, eventsOfType(t, res.TxResults[0].Events, EventUpgradeScheduled))
+	assert.Len(t, eventsOfType(t, res.TxResults[1].Events, EventUpgradeScheduled), 1)
+	plan, height, err := app.UpgradePlan()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), height)
+	assert.Equal(t, binaryB, plan.BinaryCid)
+	assert.Equal(t, int64(400), plan.ActivationHeight)
+	assert.Len(t, plan.Approvers, 2)
+
+	query, err = app.Query(ctx, &abcitypes.RequestQuery{Path: "/params"})
+	assert.NoError(t, err)
+	current := &types.Params{}
+	assert.NoError(t, proto.Unmarshal(query.Value, current))
+	assert.Equal(t, owners, current.UpdateKeys)
+
+	// Approvals give the full notice, so a plan replacing one within its notice window always activates after it
+	txn := app.db.NewTransactionAt(latestVersion, true)
+	app.height = 350
+	replace := func(activationHeight int64) (uint32, *types.UpgradePlan) {
+		approval := &types.UpgradeTransactionData{BinaryCid: binaryA, ActivationHeight: activationHeight}
+		var code uint32
+		for _, key := range updateKeys[:2] {
+			code, _ = app.handleUpgradeTransaction(txn, ownerOf(key), approval)
+		}
+		plan, err := getUpgradePlan(txn)
+		assert.NoError(t, err)
+		return code, plan
+	}
+	code, plan := replace(350 + upgradeNoticeBlocks - 1)
+	assert.Equal(t, CodeTypeInvalidUpgrade, code)
+	assert.Equal(t, binaryB, plan.BinaryCid)
+	code, plan = replace(350 + upgradeNoticeBlocks)
+	assert.Equal(t, CodeTypeOK, code)
+	assert.Equal(t, binaryA, plan.BinaryCid)
+	txn.Discard()
+
+	// Nodes stop once the block before the activation height is committed, unless they run the binary of the plan
+	var halted []string
+	assert.NoError(t, app.SetVersion(binaryA, func(plan *types.UpgradePlan) { halted = append(halted, plan.BinaryCid) }))
+	runBlock(t, app, 398)
+	assert.Empty(t, halted)
+	runBlock(t, app, 399)
+	assert.Equal(t, []string{binaryB}, halted)
+	_, err = app.FinalizeBlock(ctx, &abcitypes.RequestFinalizeBlock{Height: 400})
+	assert.ErrorContains(t, err, binaryB)
+
+	// Restarted with the old binary, it stops again
+	_, err = app.Info(ctx, &abcitypes.RequestInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{binaryB, binaryB}, halted)
+
+	assert.NoError(t, app.SetVersion(binaryB, func(plan *types.UpgradePlan) { halted = append(halted, plan.BinaryCid) }))
+	runBlock(t, app, 400)
+	assert.Len(t, halted, 2)
+}
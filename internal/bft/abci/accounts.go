@@ -0,0 +1,76 @@
+package verificationApp
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+)
+
+// Accounts are stored under this prefix, keyed by owner
+var accountPrefix = []byte("acc/")
+
+func accountKey(owner string) []byte {
+	return append(append([]byte{}, accountPrefix...), owner...)
+}
+
+// getAccount returns the account of the owner, or an empty account if it was never written
+func getAccount(txn *badger.Txn, owner string) (*types.Account, error) {
+	account := &types.Account{}
+	_, err := getMessage(txn, accountKey(owner), account)
+	return account, err
+}
+
+// setAccount writes the account of the owner
+func setAccount(txn *badger.Txn, owner string, account *types.Account) error {
+	return setMessage(txn, accountKey(owner), account)
+}
+
+// handleNormalTransaction transfers the amount from the owner of the transaction to the recipient.
+// Every check happens before the first write, so a rejected transfer leaves the state untouched.
+func (app *VerificationApp) handleNormalTransaction(txn *badger.Txn, owner string, tx *types.NormalTransactionData) (uint32, string) {
+	amount := tx.Amount
+	if amount == 0 {
+		return reject(CodeTypeInvalidAmount, "Rejected transfer: amount must be positive")
+	}
+	if _, _, err := types.ParseOwner(tx.SentTo); err != nil {
+		return reject(CodeTypeInvalidRecipient, "Rejected transfer: %v", err)
+	}
+
+	from, err := getAccount(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if from.Balance < amount {
+		return reject(CodeTypeInsufficientFunds, "Rejected transfer: %s has %d, needs %d", owner, from.Balance, amount)
+	}
+
+	to, err := getAccount(txn, tx.SentTo)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	// Sending to yourself leaves the balance as it is
+	if tx.SentTo != owner && to.Balance > math.MaxUint64-amount {
+		return reject(CodeTypeAmountOverflow, "Rejected transfer: balance of %s would overflow", tx.SentTo)
+	}
+
+	from.Balance -= amount
+	if err := setAccount(txn, owner, from); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+
+	// Read the recipient again after writing the sender, in case they are the same account
+	to, err = getAccount(txn, tx.SentTo)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	to.Balance += amount
+	if err := setAccount(txn, tx.SentTo, to); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+
+	app.emit(EventTransfer, "sender", owner, "recipient", tx.SentTo, "amount", strconv.FormatUint(amount, 10))
+	return CodeTypeOK, ""
+}
@@ -0,0 +1,238 @@
+package verificationApp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// assignmentReplication is how many validators collect each datasource, until governance changes it.
+// Rounds of assigned datasources need more than 2/3 of the power of these validators.
+const assignmentReplication = 3
+
+var (
+	datasourcePrefix = []byte("ds/")     // Datasource -> nothing, the datasources validators collect
+	assignmentPrefix = []byte("assign/") // Height -> Assignment made by the block at that height
+
+	// Hash of the last finalised block, seeds the assignment of the next one. It is part of the app hash, a node
+	// restored from a snapshot only gets a seed the other validators agreed on.
+	lastBlockHashKey = []byte("lastblockhash")
+)
+
+func datasourceKey(datasource string) []byte {
+	return append(append([]byte{}, datasourcePrefix...), datasource...)
+}
+
+func assignmentKey(height int64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, assignmentPrefix...), uint64(height))
+}
+
+// validDatasource reports whether a datasource name can be used in keys
+func validDatasource(datasource string) bool {
+	return datasource != "" && len(datasource) <= maxDatasourceLength && !bytes.ContainsRune([]byte(datasource), 0)
+}
+
+// listDatasources returns the registered datasources in order
+func listDatasources(txn *badger.Txn) ([]string, error) {
+	datasources := make([]string, 0)
+	err := iteratePrefix(txn, datasourcePrefix, func(item *badger.Item) error {
+		datasources = append(datasources, string(item.Key()[len(datasourcePrefix):]))
+		return nil
+	})
+	return datasources, err
+}
+
+// getAssignment returns the assignment made by the block at the given height, or nil if it made none
+func getAssignment(txn *badger.Txn, height int64) (*types.Assignment, error) {
+	assignment := &types.Assignment{}
+	found, err := getMessage(txn, assignmentKey(height), assignment)
+	if !found {
+		return nil, err
+	}
+	return assignment, err
+}
+
+// loadLastBlockHash returns the hash of the last finalised block, empty before the first block
+func loadLastBlockHash(txn *badger.Txn) ([]byte, error) {
+	item, err := txn.Get(lastBlockHashKey)
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// assignmentScore ranks a validator for a datasource, the lowest scores get assigned.
+// Hashing each pair on its own (rendezvous hashing) spreads datasources evenly and only moves
+// the datasources of a validator when that validator joins or leaves the set.
+func assignmentScore(seed []byte, datasource, owner string) []byte {
+	h := sha256.New()
+	h.Write(seed)
+	h.Write(binary.AppendUvarint(nil, uint64(len(datasource))))
+	h.Write([]byte(datasource))
+	h.Write([]byte(owner))
+	return h.Sum(nil)
+}
+
+// computeAssignment assigns the registered datasources to the validators for the block at the given height.
+// It returns nil if there is nothing to assign.
+func computeAssignment(txn *badger.Txn, height int64) (*types.Assignment, error) {
+	datasources, err := listDatasources(txn)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := listValidators(txn)
+	if err != nil {
+		return nil, err
+	}
+	if len(datasources) == 0 || len(validators) == 0 {
+		return nil, nil
+	}
+	seed, err := loadLastBlockHash(txn)
+	if err != nil {
+		return nil, err
+	}
+	params, err := getGovernanceParams(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &types.Assignment{Height: height, Seed: seed}
+	for _, datasource := range datasources {
+		owners := make([]string, len(validators))
+		scores := make(map[string][]byte, len(validators))
+		for i, validator := range validators {
+			owners[i] = validator.Owner
+			scores[validator.Owner] = assignmentScore(seed, datasource, validator.Owner)
+		}
+		sort.Slice(owners, func(i, j int) bool { return bytes.Compare(scores[owners[i]], scores[owners[j]]) < 0 })
+
+		assigned := owners[:min(int(params.AssignmentReplication), len(owners))]
+		sort.Strings(assigned)
+		assignment.Datasources = append(assignment.Datasources, &types.DatasourceAssignment{
+			Datasource: datasource,
+			Validators: assigned,
+		})
+	}
+	return assignment, nil
+}
+
+// newAssignmentTransaction returns the assignment transaction expected in the block at the given height, or nil if none is
+func newAssignmentTransaction(txn *badger.Txn, height int64) ([]byte, error) {
+	assignment, err := computeAssignment(txn, height)
+	if err != nil || assignment == nil {
+		return nil, err
+	}
+
+	tx := &types.Transaction{
+		Type:    types.TransactionType_AssignmentTransaction,
+		Version: types.SchemaVersion,
+		Data:    &types.Transaction_AssignmentData{AssignmentData: assignment},
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(tx)
+}
+
+// roundAssignment returns the validators assigned to a datasource for a collection interval, and whether
+// the interval had an assignment at all. Collectors switch assignment after a block is committed, so the
+// interval closed at a height is collected under the assignment of the block before it.
+func roundAssignment(txn *badger.Txn, datasource string, timestamp int64) ([]string, bool, error) {
+	assignment, err := getAssignment(txn, timestamp-1)
+	if err != nil || assignment == nil {
+		return nil, false, err
+	}
+	for _, entry := range assignment.Datasources {
+		if entry.Datasource == datasource {
+			return entry.Validators, true, nil
+		}
+	}
+	return nil, true, nil
+}
+
+// roundPower returns the voting power a round needs more than 2/3 of: its assigned validators if it has some,
+// the whole validator set otherwise.
+func roundPower(txn *badger.Txn, datasource string, timestamp int64) (int64, error) {
+	assigned, ok, err := roundAssignment(txn, datasource, timestamp)
+	if err != nil || !ok {
+		if err == nil {
+			return totalPower(txn)
+		}
+		return 0, err
+	}
+
+	var total int64
+	for _, owner := range assigned {
+		validator, err := getValidator(txn, owner)
+		if err != nil {
+			return 0, err
+		}
+		// A validator that left the set since the assignment no longer counts
+		if validator != nil {
+			total += validator.Power
+		}
+	}
+	return total, nil
+}
+
+// isAssigned reports whether a validator may submit for a collection interval
+func isAssigned(txn *badger.Txn, owner string, datasource string, timestamp int64) (bool, error) {
+	assigned, ok, err := roundAssignment(txn, datasource, timestamp)
+	if err != nil || !ok {
+		return err == nil, err
+	}
+	return slices.Contains(assigned, owner), nil
+}
+
+// handleAssignmentTransaction stores the assignment of the block being finalised, it must be exactly the expected one
+func (app *VerificationApp) handleAssignmentTransaction(txn *badger.Txn, tx []byte) (uint32, string) {
+	expected, err := newAssignmentTransaction(txn, app.height)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if !bytes.Equal(tx, expected) {
+		return reject(CodeTypeInvalidAssignment, "Rejected assignment: does not match the assignment of block %d", app.height)
+	}
+
+	transaction := &types.Transaction{}
+	if err := proto.Unmarshal(tx, transaction); err != nil {
+		log.Panicf("Error decoding expected assignment: %v", err)
+	}
+	if err := setMessage(txn, assignmentKey(app.height), transaction.GetAssignmentData()); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	// Rounds older than maxRoundAge can't be submitted to any more, neither can their assignment be used
+	if err := deleteKey(txn, assignmentKey(app.height-maxRoundAge-1)); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	return CodeTypeOK, ""
+}
+
+// Assignment returns the datasources assigned to a validator by the last committed block
+func (app *VerificationApp) Assignment(owner string) ([]string, error) {
+	height, _, err := loadCommitInfo(app.db)
+	if err != nil {
+		return nil, err
+	}
+
+	datasources := make([]string, 0)
+	err = view(app.db, func(txn *badger.Txn) error {
+		assignment, err := getAssignment(txn, height)
+		if err != nil || assignment == nil {
+			return err
+		}
+		for _, entry := range assignment.Datasources {
+			if slices.Contains(entry.Validators, owner) {
+				datasources = append(datasources, entry.Datasource)
+			}
+		}
+		return nil
+	})
+	return datasources, err
+}
@@ -0,0 +1,56 @@
+package verificationApp
+
+import "fmt"
+
+// ABCI result codes returned by CheckTx and FinalizeBlock. 0 is the only code CometBFT treats as success.
+const (
+	CodeTypeOK                   uint32 = 0
+	CodeTypeEncodingError        uint32 = 1  // The transaction is not a valid protobuf Transaction
+	CodeTypeInvalidTxType        uint32 = 2  // Unknown transaction type or the payload does not match the type
+	CodeTypeNonCanonicalTx       uint32 = 3  // The transaction bytes are not the canonical encoding of the transaction
+	CodeTypeInvalidOwner         uint32 = 4  // The owner is not a valid ed25519 public key or secp256k1 address
+	CodeTypeBadSignatureFormat   uint32 = 5  // The signature could not be decoded or has the wrong length
+	CodeTypeMalleableSignature   uint32 = 6  // The signature is valid but not in its canonical (low S) form
+	CodeTypeInvalidSignature     uint32 = 7  // The signature was not produced by the owner over this transaction
+	CodeTypeNonceTooLow          uint32 = 8  // The nonce was already used by the owner, the transaction is a replay or stale
+	CodeTypeInvalidAmount        uint32 = 9  // The amount is zero, not representable in base units or inconsistent
+	CodeTypeInvalidRecipient     uint32 = 10 // The recipient is not a valid owner
+	CodeTypeInsufficientFunds    uint32 = 11 // The owner's balance does not cover the transaction
+	CodeTypeNonceGap             uint32 = 12 // The nonce skips over nonces the owner has not used yet
+	CodeTypeUnsupportedVersion   uint32 = 13 // The transaction schema version is unknown to this node
+	CodeTypeAmountOverflow       uint32 = 14 // Applying the amount would overflow a uint64 balance
+	CodeTypeNotValidator         uint32 = 15 // Only members of the validator set can send this transaction
+	CodeTypeInvalidDatasource    uint32 = 16 // The datasource name is empty, too long or contains NUL
+	CodeTypeInvalidTimestamp     uint32 = 17 // The collection interval has not been closed by a block yet
+	CodeTypeInvalidCid           uint32 = 18 // No CIDs, too many CIDs, or a CID that is not in canonical form
+	CodeTypeInvalidAttestation   uint32 = 19 // The attestation is not the digest of the submitted CIDs
+	CodeTypeDuplicateSubmission  uint32 = 20 // The validator already submitted for this collection interval
+	CodeTypeProposerOnly         uint32 = 21 // Attestation and assignment transactions are only added to blocks by their proposer
+	CodeTypeInvalidVoteExtension uint32 = 22 // A vote extension is malformed or not signed by a validator for the previous height
+	CodeTypeInvalidAssignment    uint32 = 23 // The collector assignment is not the one computed from the state
+	CodeTypeTxTooLarge           uint32 = 24 // The transaction is larger than maxTxBytes
+	CodeTypeNotAssigned          uint32 = 25 // The validator was not assigned the datasource for this collection interval
+	CodeTypeUnknownQuery         uint32 = 26 // The query path is not routed
+	CodeTypeInvalidHeight        uint32 = 27 // The state at the queried height is not available, too recent or pruned
+	CodeTypeNotFound             uint32 = 28 // The queried item does not exist
+	CodeTypeAlreadyStaked        uint32 = 29 // The owner joined the validators already, increase the stake instead
+	CodeTypeNotStaked            uint32 = 30 // The owner has no stake to increase or unbond
+	CodeTypeLastValidator        uint32 = 31 // Unbonding would leave no validator with any voting power
+	CodeTypeInvalidEpoch         uint32 = 32 // The resource report is not for the current epoch
+	CodeTypeUnverifiedResources  uint32 = 33 // The node agreed with no verified round during the epoch of its report
+	CodeTypeNotJailed            uint32 = 34 // Only a jailed validator can unjail
+	CodeTypeStillJailed          uint32 = 35 // The jail time of the validator is not over yet
+	CodeTypeFeeTooLow            uint32 = 36 // The fee is below the gas of the transaction at the minimum gas price of the node
+	CodeTypeInvalidProposal      uint32 = 37 // A proposal changes no parameter, an unknown one, one twice, or sets a value out of bounds
+	CodeTypeUnknownProposal      uint32 = 38 // No proposal has the ID voted on
+	CodeTypeProposalClosed       uint32 = 39 // The proposal voted on passed or was rejected already
+	CodeTypeNotUpdateKey         uint32 = 40 // Only the update keys of genesis.json can approve upgrades
+	CodeTypeInvalidUpgrade       uint32 = 41 // The activation height of the upgrade is too close to the current height
+	CodeTypeTooManyProposals     uint32 = 42 // The proposer has maxOpenProposals proposals being voted on already
+)
+
+// reject returns the code of a rejected transaction along with why, the Log of its result.
+// Nothing is printed, anyone can send transactions that are rejected.
+func reject(code uint32, format string, args ...interface{}) (uint32, string) {
+	return code, fmt.Sprintf(format, args...)
+}
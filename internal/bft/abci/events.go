@@ -0,0 +1,41 @@
+package verificationApp
+
+import (
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+)
+
+// Types of the events in FinalizeBlock results. Every attribute is indexed, so CometBFT's /tx_search and
+// /block_search find them with queries like "cid_verified.cid='bafk...'" or "transfer.recipient='...'".
+const (
+	EventTransfer              = "transfer"               // sender, recipient, amount
+	EventVerificationSubmitted = "verification_submitted" // owner, datasource, timestamp, attestation
+	EventCidVerified           = "cid_verified"           // cid, datasource, timestamp, height
+	EventValidatorSlashed      = "validator_slashed"      // owner, reason, burnt, jailed, height
+	EventRewardPaid            = "reward_paid"            // owner, epoch, amount
+	EventFeePaid               = "fee_paid"               // owner, fee
+	EventProposalSubmitted     = "proposal_submitted"     // proposal_id, proposer
+	EventProposalVoted         = "proposal_voted"         // proposal_id, voter, approve
+	EventProposalClosed        = "proposal_closed"        // proposal_id, status
+	EventUpgradeApproved       = "upgrade_approved"       // approver, binary_cid, activation_height
+	EventUpgradeScheduled      = "upgrade_scheduled"      // binary_cid, activation_height
+)
+
+// emit records an event of the transaction or block being executed, attributes come as key, value pairs
+func (app *VerificationApp) emit(eventType string, attributes ...string) {
+	event := abcitypes.Event{Type: eventType}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		event.Attributes = append(event.Attributes, abcitypes.EventAttribute{
+			Key:   attributes[i],
+			Value: attributes[i+1],
+			Index: true,
+		})
+	}
+	app.events = append(app.events, event)
+}
+
+// takeEvents returns the events emitted since the last call
+func (app *VerificationApp) takeEvents() []abcitypes.Event {
+	events := app.events
+	app.events = nil
+	return events
+}
@@ -0,0 +1,312 @@
+package verificationApp
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmted25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxVoteExtensionSize bounds a vote extension, every validator gossips one with each precommit
+const maxVoteExtensionSize = 256 << 10
+
+// The chain ID is part of what vote extensions are signed over, it is set once by InitChain. It is part of the
+// app hash, a node restored from a snapshot only gets the chain ID the light client vouched for.
+var chainIDKey = []byte("chainid")
+
+// Summaries cover the messages the sources timestamped within a window of block times. The window ends this long
+// before the time of the last block, so messages on their way to the collectors are in it when it is cut.
+const summaryDelay = 5 * time.Second
+
+var (
+	summaryStartKey = []byte("window/start") // Unix milliseconds the window of the next closing vote starts at
+	summaryEndKey   = []byte("window/end")   // Unix milliseconds it ends at, excluded
+)
+
+// SummarySource returns the summaries of the messages timestamped in [start, end) by their sources and drops
+// everything older, with only the datasource and CIDs set
+type SummarySource func(start, end time.Time) []*types.VerificationTransactionData
+
+// SetSummarySource sets where ExtendVote takes the summaries of this validator from.
+// It must be called before the node starts, without a source this validator extends its votes with nothing.
+func (app *VerificationApp) SetSummarySource(source SummarySource) {
+	app.summarySource = source
+}
+
+// loadChainID returns the chain ID stored by InitChain, or an empty string before that
+func loadChainID(db *badger.DB) (chainID string, err error) {
+	err = view(db, func(txn *badger.Txn) error {
+		item, err := txn.Get(chainIDKey)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			chainID = string(val)
+			return nil
+		})
+	})
+	return chainID, err
+}
+
+// closesInterval tells whether the votes at a height close a summary interval, the ones in between carry nothing
+func closesInterval(params *types.GovernanceParams, height int64) bool {
+	interval := params.SummaryIntervalBlocks
+	return interval <= 1 || height%interval == 0
+}
+
+// summaryWindow returns the window of source timestamps the next closing vote summarizes
+func summaryWindow(txn *badger.Txn) (start, end time.Time, err error) {
+	startMillis, err := getCounter(txn, summaryStartKey)
+	if err != nil {
+		return start, end, err
+	}
+	endMillis, err := getCounter(txn, summaryEndKey)
+	if err != nil {
+		return start, end, err
+	}
+	return time.UnixMilli(int64(startMillis)), time.UnixMilli(int64(endMillis)), nil
+}
+
+// advanceSummaryWindow moves the window along with the block at a height. After the votes at the height closed an
+// interval the next window starts where theirs ended, and it always ends summaryDelay before the block time.
+// It runs before the block changes the parameters, which are the ones the votes at the height saw.
+func advanceSummaryWindow(txn *badger.Txn, height int64, blockTime time.Time) error {
+	params, err := getGovernanceParams(txn)
+	if err != nil {
+		return err
+	}
+	end, err := getCounter(txn, summaryEndKey)
+	if err != nil {
+		return err
+	}
+	if closesInterval(params, height) {
+		if err := setCounter(txn, summaryStartKey, end); err != nil {
+			return err
+		}
+	}
+	// Block times only grow, the end never moves back unless a block predates the delay
+	if millis := blockTime.Add(-summaryDelay).UnixMilli(); millis > int64(end) {
+		end = uint64(millis)
+	}
+	return setCounter(txn, summaryEndKey, end)
+}
+
+// buildVoteExtension collects the summaries of the interval closed by the height being voted on.
+// Intervals span the summary interval of the governed parameters, votes in between carry nothing and leave
+// the summaries with the source. The window they cover only depends on the committed blocks, so every
+// validator asks its source for the same one. Summaries that would not pass VerifyVoteExtension on the other
+// validators, or that don't fit, are dropped.
+func (app *VerificationApp) buildVoteExtension(height int64) ([]byte, error) {
+	if app.summarySource == nil {
+		return nil, nil
+	}
+	var params *types.GovernanceParams
+	var start, end time.Time
+	err := view(app.db, func(txn *badger.Txn) (err error) {
+		if params, err = getGovernanceParams(txn); err != nil {
+			return err
+		}
+		start, end, err = summaryWindow(txn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !closesInterval(params, height) {
+		return nil, nil
+	}
+	extension := &types.VoteExtension{}
+
+	summaries := app.summarySource(start, end)
+	sort.SliceStable(summaries, func(i, j int) bool { return summaries[i].Datasource < summaries[j].Datasource })
+
+	size := 0
+	for i, summary := range summaries {
+		if i > 0 && summary.Datasource == summaries[i-1].Datasource {
+			continue
+		}
+		summary.Timestamp = height
+		summary.Attestation = types.AttestationDigest(summary.SubmittedCids())
+		if code, _ := validateSubmission(summary, height+1); code != CodeTypeOK {
+			continue
+		}
+
+		// Upper bound of the encoded size of the entry within the extension
+		entrySize := proto.Size(summary) + 16
+		if size+entrySize > maxVoteExtensionSize {
+			logger.Warnf("Vote extension full, dropping summary of %s", summary.Datasource)
+			continue
+		}
+		size += entrySize
+		extension.Summaries = append(extension.Summaries, summary)
+	}
+
+	if len(extension.Summaries) == 0 {
+		return nil, nil
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(extension)
+}
+
+// decodeVoteExtension parses a vote extension made at the given height and checks its content.
+// Only the canonical form is accepted: one entry per datasource, in datasource order.
+func decodeVoteExtension(raw []byte, height int64) (*types.VoteExtension, uint32, string) {
+	extension := &types.VoteExtension{}
+	if len(raw) > maxVoteExtensionSize {
+		code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected vote extension: %d bytes", len(raw))
+		return nil, code, reason
+	}
+	if err := proto.Unmarshal(raw, extension); err != nil {
+		code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected vote extension: %v", err)
+		return nil, code, reason
+	}
+	canonical, err := proto.MarshalOptions{Deterministic: true}.Marshal(extension)
+	if err != nil || !bytes.Equal(canonical, raw) {
+		code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected vote extension: not canonically encoded")
+		return nil, code, reason
+	}
+
+	for i, summary := range extension.Summaries {
+		if i > 0 && summary.Datasource <= extension.Summaries[i-1].Datasource {
+			code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected vote extension: datasources are not sorted or repeated")
+			return nil, code, reason
+		}
+		if summary.Timestamp != height {
+			code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected vote extension: summary for %d in a vote for %d", summary.Timestamp, height)
+			return nil, code, reason
+		}
+		// The summaries are executed in the next block, which is when the interval counts as closed
+		if code, reason := validateSubmission(summary, height+1); code != CodeTypeOK {
+			return nil, code, reason
+		}
+	}
+	return extension, CodeTypeOK, ""
+}
+
+// attestationVote is a vote extension whose signature was checked against the validator that made it
+type attestationVote struct {
+	validator *types.Validator
+	extension *types.VoteExtension
+}
+
+// newAttestationTransaction aggregates the vote extensions of the last commit into a transaction.
+// It returns nil if no validator extended its vote.
+func newAttestationTransaction(commit abcitypes.ExtendedCommitInfo, height int64) ([]byte, error) {
+	data := &types.AttestationTransactionData{Height: height, Round: commit.Round}
+	for _, vote := range commit.Votes {
+		// Only precommits for the block carry a signed extension. Empty ones are kept too, so the attestation
+		// matches the last commit of the block and a proposer can't leave out the extensions it doesn't like.
+		if vote.BlockIdFlag != cmtproto.BlockIDFlagCommit {
+			continue
+		}
+		data.Votes = append(data.Votes, &types.ExtendedVote{
+			ValidatorAddress:   vote.Validator.Address,
+			Extension:          vote.VoteExtension,
+			ExtensionSignature: vote.ExtensionSignature,
+		})
+	}
+	if len(data.Votes) == 0 {
+		return nil, nil
+	}
+
+	tx := &types.Transaction{
+		Type:    types.TransactionType_AttestationTransaction,
+		Version: types.SchemaVersion,
+		Data:    &types.Transaction_AttestationData{AttestationData: data},
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(tx)
+}
+
+// attestsCommit tells whether an attestation carries the votes of the precommits for the block in a last commit,
+// in the same order. The signatures of the extensions are left to verifyAttestation.
+func attestsCommit(tx *types.AttestationTransactionData, commit abcitypes.CommitInfo) bool {
+	if tx.Round != commit.Round {
+		return false
+	}
+	i := 0
+	for _, vote := range commit.Votes {
+		if vote.BlockIdFlag != cmtproto.BlockIDFlagCommit {
+			continue
+		}
+		if i == len(tx.Votes) || !bytes.Equal(tx.Votes[i].ValidatorAddress, vote.Validator.Address) {
+			return false
+		}
+		i++
+	}
+	return i == len(tx.Votes)
+}
+
+// verifyAttestation checks that every vote extension of an attestation transaction executed at the given height
+// was signed by a distinct validator for the previous height, and returns them along with their validator.
+func (app *VerificationApp) verifyAttestation(txn *badger.Txn, tx *types.AttestationTransactionData, height int64) ([]attestationVote, uint32, string) {
+	if tx.Height != height-1 {
+		code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected attestation: votes for %d in block %d", tx.Height, height)
+		return nil, code, reason
+	}
+
+	validators, err := listValidators(txn)
+	if err != nil {
+		log.Panicf("Error reading database, unable to verify attestation: %v", err)
+	}
+	byAddress := make(map[string]*types.Validator, len(validators))
+	for _, validator := range validators {
+		byAddress[string(cmted25519.PubKey(validator.PubKey).Address())] = validator
+	}
+
+	votes := make([]attestationVote, 0, len(tx.Votes))
+	for _, vote := range tx.Votes {
+		validator := byAddress[string(vote.ValidatorAddress)]
+		if validator == nil {
+			code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected attestation: %X is not a validator or voted twice", vote.ValidatorAddress)
+			return nil, code, reason
+		}
+		delete(byAddress, string(vote.ValidatorAddress))
+
+		signBytes := cmttypes.VoteExtensionSignBytes(app.chainID, &cmtproto.Vote{
+			Type:      cmtproto.PrecommitType,
+			Height:    tx.Height,
+			Round:     tx.Round,
+			Extension: vote.Extension,
+		})
+		if !cmted25519.PubKey(validator.PubKey).VerifySignature(signBytes, vote.ExtensionSignature) {
+			code, reason := reject(CodeTypeInvalidVoteExtension, "Rejected attestation: invalid extension signature from %s", validator.Owner)
+			return nil, code, reason
+		}
+
+		extension, code, reason := decodeVoteExtension(vote.Extension, tx.Height)
+		if code != CodeTypeOK {
+			return nil, CodeTypeInvalidVoteExtension, fmt.Sprintf("Rejected attestation: extension of %s: %s", validator.Owner, reason)
+		}
+		votes = append(votes, attestationVote{validator: validator, extension: extension})
+	}
+	return votes, CodeTypeOK, ""
+}
+
+// handleAttestationTransaction records the summaries carried by the vote extensions as submissions of their validators
+func (app *VerificationApp) handleAttestationTransaction(txn *badger.Txn, tx *types.AttestationTransactionData) (uint32, string) {
+	votes, code, reason := app.verifyAttestation(txn, tx, app.height)
+	if code != CodeTypeOK {
+		return code, reason
+	}
+
+	for _, vote := range votes {
+		for _, summary := range vote.extension.Summaries {
+			// A validator that also submitted a verification transaction for the interval keeps its first submission
+			if code, reason := app.recordSubmission(txn, vote.validator, summary); code != CodeTypeOK {
+				logger.Debugf("Skipped summary of %s from %s: %s", summary.Datasource, vote.validator.Owner, reason)
+			}
+		}
+	}
+	return CodeTypeOK, ""
+}
@@ -0,0 +1,150 @@
+package verificationApp
+
+import (
+	"container/heap"
+	"log"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fees collected since the last reward distribution, part of the supply until they are paid out
+var feePoolKey = []byte("feepool")
+
+// SetMinGasPrice sets the fee per gas, in base units, this node's mempool requires. It is not a consensus rule,
+// blocks proposed by other validators may carry cheaper transactions.
+func (app *VerificationApp) SetMinGasPrice(price uint64) {
+	app.minGasPrice = price
+}
+
+// checkFee rejects the transactions of the mempool that pay less than the minimum gas price,
+// or whose owner can't pay their fee on top of the fees of its transactions already in the mempool
+func (app *VerificationApp) checkFee(tx *types.Transaction) (uint32, string) {
+	if minFee := types.MinFee(tx, app.minGasPrice); tx.Fee < minFee {
+		return reject(CodeTypeFeeTooLow, "Rejected transaction from %s: fee %d is below %d", tx.Owner, tx.Fee, minFee)
+	}
+	if tx.Fee == 0 {
+		return CodeTypeOK, ""
+	}
+	var balance uint64
+	err := view(app.db, func(txn *badger.Txn) error {
+		account, err := getAccount(txn, tx.Owner)
+		balance = account.GetBalance()
+		return err
+	})
+	if err != nil {
+		log.Panicf("Error reading database, unable to check tx: %v", err)
+	}
+	pending := app.pendingFees[tx.Owner]
+	if balance < pending || balance-pending < tx.Fee {
+		return reject(CodeTypeInsufficientFunds, "Rejected transaction from %s: has %d, %d of it pending, fee is %d", tx.Owner, balance, pending, tx.Fee)
+	}
+	return CodeTypeOK, ""
+}
+
+// chargeFee takes the fee from the balance of the owner before the transaction executes,
+// so the transaction can only spend what is left
+func chargeFee(txn *badger.Txn, owner string, fee uint64) (uint32, string) {
+	if fee == 0 {
+		return CodeTypeOK, ""
+	}
+	account, err := getAccount(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if account.Balance < fee {
+		return reject(CodeTypeInsufficientFunds, "Rejected transaction: %s has %d, fee is %d", owner, account.Balance, fee)
+	}
+	account.Balance -= fee
+	if err := setAccount(txn, owner, account); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	return CodeTypeOK, ""
+}
+
+// payFee moves the fee charged before the transaction executed to the pool, whether or not the transaction
+// succeeded: its gas was used either way.
+func (app *VerificationApp) payFee(txn *badger.Txn, owner string, fee uint64) {
+	if fee == 0 {
+		return
+	}
+	// The fees are part of the supply, so the pool can't overflow
+	pool, err := getCounter(txn, feePoolKey)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if err := setCounter(txn, feePoolKey, pool+fee); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	app.emit(EventFeePaid, "owner", owner, "fee", strconv.FormatUint(fee, 10))
+}
+
+// gasPrice is the fee per gas a transaction pays, which orders the transactions of a proposal
+func gasPrice(tx *types.Transaction) uint64 {
+	gas := types.Gas(tx)
+	if gas <= 0 {
+		return 0
+	}
+	return tx.Fee / uint64(gas)
+}
+
+// queuedTx is a mempool transaction waiting in the queue of its owner
+type queuedTx struct {
+	tx       []byte
+	price    uint64
+	position int // Index in the mempool
+}
+
+// txQueues is a heap of the owners' queues ordered by their first transaction: highest gas price, then mempool order
+type txQueues [][]queuedTx
+
+func (q txQueues) Len() int { return len(q) }
+func (q txQueues) Less(i, j int) bool {
+	if q[i][0].price != q[j][0].price {
+		return q[i][0].price > q[j][0].price
+	}
+	return q[i][0].position < q[j][0].position
+}
+func (q txQueues) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *txQueues) Push(x interface{}) { *q = append(*q, x.([]queuedTx)) }
+func (q *txQueues) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// prioritise orders the mempool transactions by gas price, highest first. The transactions of an owner keep
+// their mempool order, so their nonces still follow each other, and equal prices keep the mempool order.
+func prioritise(txs [][]byte) [][]byte {
+	byOwner := make(map[string]int)
+	queues := make(txQueues, 0)
+	for i, tx := range txs {
+		var transaction types.Transaction
+		owner, price := "", uint64(0)
+		if err := proto.Unmarshal(tx, &transaction); err == nil {
+			owner, price = transaction.Owner, gasPrice(&transaction)
+		}
+		index, ok := byOwner[owner]
+		if !ok {
+			index = len(queues)
+			byOwner[owner] = index
+			queues = append(queues, nil)
+		}
+		queues[index] = append(queues[index], queuedTx{tx: tx, price: price, position: i})
+	}
+
+	heap.Init(&queues)
+	ordered := make([][]byte, 0, len(txs))
+	for queues.Len() > 0 {
+		ordered = append(ordered, queues[0][0].tx)
+		if queues[0] = queues[0][1:]; len(queues[0]) == 0 {
+			heap.Pop(&queues)
+		} else {
+			heap.Fix(&queues, 0)
+		}
+	}
+	return ordered
+}
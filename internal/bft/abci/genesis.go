@@ -0,0 +1,171 @@
+package verificationApp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+)
+
+// GenesisState is the app_state section of genesis.json
+type GenesisState struct {
+	Accounts []GenesisAccount `json:"accounts"`
+	// Datasources the validators are assigned to collect, named like collector requests, e.g. "binance/btc.eth"
+	Datasources []string `json:"datasources"`
+	// Punishment of misbehaving validators, defaultSlashingParams if not set
+	Slashing *GenesisSlashing `json:"slashing"`
+	// Governed parameters by name, with values as proposals set them, e.g. {"collectorSlots": "4"}.
+	// They apply on top of the defaults and of the slashing section.
+	Params map[string]string `json:"params"`
+	// Owners allowed to approve new versions of the node binary, see UpgradeTransaction
+	UpdateKeys []string `json:"updateKeys"`
+}
+
+// GenesisSlashing sets the slashing parameters, fractions are in basis points
+type GenesisSlashing struct {
+	DoubleSignSlash uint32 `json:"doubleSignSlash"`
+	WrongDataSlash  uint32 `json:"wrongDataSlash"`
+	DowntimeSlash   uint32 `json:"downtimeSlash"`
+	MaxMissedBlocks int64  `json:"maxMissedBlocks"`
+	MaxWrongRounds  int64  `json:"maxWrongRounds"`
+	JailBlocks      int64  `json:"jailBlocks"`
+}
+
+// GenesisAccount allocates an initial balance to an owner.
+// The balance is a string so that amounts above 2^53 survive JSON tooling.
+type GenesisAccount struct {
+	Owner   string `json:"owner"`
+	Balance uint64 `json:"balance,string"`
+}
+
+// parseGenesisState decodes and validates the app_state of genesis.json, an empty app_state is an empty genesis
+func parseGenesisState(appState []byte) (*GenesisState, error) {
+	genesis := &GenesisState{}
+	if len(appState) == 0 {
+		return genesis, nil
+	}
+	if err := json.Unmarshal(appState, genesis); err != nil {
+		return nil, fmt.Errorf("invalid app_state: %w", err)
+	}
+
+	var supply uint64
+	seen := make(map[string]bool)
+	for _, account := range genesis.Accounts {
+		if _, _, err := types.ParseOwner(account.Owner); err != nil {
+			return nil, fmt.Errorf("invalid genesis account: %w", err)
+		}
+		if seen[account.Owner] {
+			return nil, fmt.Errorf("genesis account %s is allocated twice", account.Owner)
+		}
+		seen[account.Owner] = true
+
+		// Transfers rely on the total supply fitting in a uint64
+		if supply > math.MaxUint64-account.Balance {
+			return nil, fmt.Errorf("total genesis supply overflows")
+		}
+		supply += account.Balance
+	}
+
+	seen = make(map[string]bool)
+	for _, datasource := range genesis.Datasources {
+		if !validDatasource(datasource) {
+			return nil, fmt.Errorf("invalid genesis datasource %q", datasource)
+		}
+		if seen[datasource] {
+			return nil, fmt.Errorf("genesis datasource %s is listed twice", datasource)
+		}
+		seen[datasource] = true
+	}
+
+	seen = make(map[string]bool)
+	for _, owner := range genesis.UpdateKeys {
+		if _, _, err := types.ParseOwner(owner); err != nil {
+			return nil, fmt.Errorf("invalid genesis update key: %w", err)
+		}
+		if seen[owner] {
+			return nil, fmt.Errorf("genesis update key %s is listed twice", owner)
+		}
+		seen[owner] = true
+	}
+
+	if s := genesis.Slashing; s != nil {
+		if s.DoubleSignSlash > basisPoints || s.WrongDataSlash > basisPoints || s.DowntimeSlash > basisPoints {
+			return nil, fmt.Errorf("genesis slashing fractions are at most %d basis points", basisPoints)
+		}
+		if s.MaxMissedBlocks <= 0 || s.MaxWrongRounds <= 0 || s.JailBlocks < 0 {
+			return nil, fmt.Errorf("genesis slashing needs a positive maxMissedBlocks and maxWrongRounds and non-negative jailBlocks")
+		}
+	}
+	if _, _, err := genesis.params(); err != nil {
+		return nil, fmt.Errorf("invalid genesis params: %w", err)
+	}
+	return genesis, nil
+}
+
+// params returns the governed and slashing parameters the chain starts with
+func (g *GenesisState) params() (*types.GovernanceParams, *types.SlashingParams, error) {
+	params, slashing := defaultGovernanceParams(), defaultSlashingParams()
+	if s := g.Slashing; s != nil {
+		slashing = &types.SlashingParams{
+			DoubleSignSlash: s.DoubleSignSlash,
+			WrongDataSlash:  s.WrongDataSlash,
+			DowntimeSlash:   s.DowntimeSlash,
+			MaxMissedBlocks: s.MaxMissedBlocks,
+			MaxWrongRounds:  s.MaxWrongRounds,
+			JailBlocks:      s.JailBlocks,
+		}
+	}
+	// Every name sets its own field, the order only matters for which error is reported
+	names := make([]string, 0, len(g.Params))
+	for name := range g.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := setParam(params, slashing, &types.ParamChange{Name: name, Value: g.Params[name]}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return params, slashing, nil
+}
+
+// supply returns the total of the genesis balances, parseGenesisState checked it fits in a uint64
+func (g *GenesisState) supply() uint64 {
+	var supply uint64
+	for _, account := range g.Accounts {
+		supply += account.Balance
+	}
+	return supply
+}
+
+// apply writes the genesis allocation to the state
+func (g *GenesisState) apply(txn *badger.Txn) error {
+	for _, account := range g.Accounts {
+		if err := setAccount(txn, account.Owner, &types.Account{Balance: account.Balance}); err != nil {
+			return err
+		}
+	}
+	for _, datasource := range g.Datasources {
+		if err := setKey(txn, datasourceKey(datasource), nil); err != nil {
+			return err
+		}
+	}
+
+	for _, owner := range g.UpdateKeys {
+		if err := setKey(txn, updateKeyKey(owner), nil); err != nil {
+			return err
+		}
+	}
+
+	params, slashing, err := g.params()
+	if err != nil {
+		return err
+	}
+	if err := setMessage(txn, governanceParamsKey, params); err != nil {
+		return err
+	}
+	return setMessage(txn, slashingParamsKey, slashing)
+}
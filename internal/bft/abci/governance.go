@@ -0,0 +1,425 @@
+package verificationApp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+)
+
+const (
+	// votingPeriodBlocks is how long a proposal is open for votes
+	votingPeriodBlocks = 600
+	// maxOpenProposals bounds the proposals of a validator being voted on at once
+	maxOpenProposals = 3
+	// maxParamChanges bounds the changes of a single proposal
+	maxParamChanges = 16
+
+	// Bounds of the collector parameters, a collector panics on a buffer under 100 bytes
+	maxCollectorSlots     = 256
+	minSummaryBufferBytes = 1 << 10
+	maxSummaryBufferBytes = 16 << 20
+)
+
+var (
+	// Parameters changed by proposals, see defaultGovernanceParams
+	governanceParamsKey = []byte("params/governance")
+
+	// ID of the last proposal, the first one is 1
+	lastProposalKey = []byte("govlast")
+
+	proposalPrefix       = []byte("proposal/") // ID -> Proposal
+	proposalVotePrefix   = []byte("govvote/")  // ID, owner -> 1 to approve, 0 to reject, then the power counted for it
+	proposalEndPrefix    = []byte("govend/")   // Voting end height, ID -> nothing, for every proposal still being voted on
+	proposerPrefix       = []byte("govprop/")  // Owner -> how many of its proposals are being voted on
+	passedProposalPrefix = []byte("govpass/")  // ID -> nothing, for every passed proposal whose changes wait for the next block
+)
+
+func proposalKey(prefix []byte, id uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, prefix...), id)
+}
+
+func proposalEndKey(height int64, id uint64) []byte {
+	return binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(append([]byte{}, proposalEndPrefix...), uint64(height)), id)
+}
+
+func proposerKey(owner string) []byte {
+	return append(append([]byte{}, proposerPrefix...), owner...)
+}
+
+// defaultGovernanceParams apply until genesis.json or a proposal sets them
+func defaultGovernanceParams() *types.GovernanceParams {
+	return &types.GovernanceParams{
+		CollectorSlots:        1,
+		SummaryBufferBytes:    4 << 10,
+		SummaryIntervalBlocks: 1,
+		EpochReward:           epochReward,
+		UnitsPerVerifiedRound: unitsPerVerifiedRound,
+		UpdateThreshold:       &types.Fraction{Numerator: 2, Denominator: 3},
+		AssignmentReplication: assignmentReplication,
+	}
+}
+
+// getGovernanceParams returns the governed parameters of the chain
+func getGovernanceParams(txn *badger.Txn) (*types.GovernanceParams, error) {
+	params := &types.GovernanceParams{}
+	found, err := getMessage(txn, governanceParamsKey, params)
+	if !found && err == nil {
+		return defaultGovernanceParams(), nil
+	}
+	return params, err
+}
+
+// GovernanceParams returns the governed parameters of the committed state, for the collector and the updater
+func (app *VerificationApp) GovernanceParams() (params *types.GovernanceParams, err error) {
+	err = view(app.db, func(txn *badger.Txn) error {
+		params, err = getGovernanceParams(txn)
+		return err
+	})
+	return params, err
+}
+
+// parseParam parses a decimal value within [lo, hi]
+func parseParam(value string, lo, hi uint64) (uint64, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%d is not between %d and %d", n, lo, hi)
+	}
+	return n, nil
+}
+
+// parseThreshold parses a fraction such as 2/3, it must be more than half so two versions can't both reach it
+func parseThreshold(value string) (*types.Fraction, error) {
+	numerator, denominator, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil, fmt.Errorf("%q is not a fraction such as 2/3", value)
+	}
+	d, err := parseParam(denominator, 1, math.MaxUint32)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseParam(numerator, d/2+1, d)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Fraction{Numerator: n, Denominator: d}, nil
+}
+
+// setParam parses the value of a governed parameter into the parameters, it fails on unknown names and values out of bounds
+func setParam(params *types.GovernanceParams, slashing *types.SlashingParams, change *types.ParamChange) error {
+	var n uint64
+	var err error
+	switch change.Name {
+	case "collectorSlots":
+		n, err = parseParam(change.Value, 1, maxCollectorSlots)
+		params.CollectorSlots = uint32(n)
+	case "summaryBufferBytes":
+		n, err = parseParam(change.Value, minSummaryBufferBytes, maxSummaryBufferBytes)
+		params.SummaryBufferBytes = uint32(n)
+	case "summaryIntervalBlocks":
+		n, err = parseParam(change.Value, 1, epochBlocks)
+		params.SummaryIntervalBlocks = int64(n)
+	case "epochReward":
+		params.EpochReward, err = parseParam(change.Value, 0, math.MaxUint64)
+	case "unitsPerVerifiedRound":
+		params.UnitsPerVerifiedRound, err = parseParam(change.Value, 1, math.MaxUint64)
+	case "assignmentReplication":
+		n, err = parseParam(change.Value, 1, math.MaxUint32)
+		params.AssignmentReplication = uint32(n)
+	case "updateThreshold":
+		var threshold *types.Fraction
+		if threshold, err = parseThreshold(change.Value); err == nil {
+			params.UpdateThreshold = threshold
+		}
+	case "doubleSignSlash":
+		n, err = parseParam(change.Value, 0, basisPoints)
+		slashing.DoubleSignSlash = uint32(n)
+	case "wrongDataSlash":
+		n, err = parseParam(change.Value, 0, basisPoints)
+		slashing.WrongDataSlash = uint32(n)
+	case "downtimeSlash":
+		n, err = parseParam(change.Value, 0, basisPoints)
+		slashing.DowntimeSlash = uint32(n)
+	case "maxMissedBlocks":
+		n, err = parseParam(change.Value, 1, math.MaxInt64)
+		slashing.MaxMissedBlocks = int64(n)
+	case "maxWrongRounds":
+		n, err = parseParam(change.Value, 1, math.MaxInt64)
+		slashing.MaxWrongRounds = int64(n)
+	case "jailBlocks":
+		n, err = parseParam(change.Value, 0, math.MaxInt64)
+		slashing.JailBlocks = int64(n)
+	default:
+		return fmt.Errorf("unknown parameter %q", change.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", change.Name, err)
+	}
+	return nil
+}
+
+// applyParamChanges sets the parameters of the state, SlashingParams included
+func applyParamChanges(txn *badger.Txn, changes []*types.ParamChange) error {
+	params, err := getGovernanceParams(txn)
+	if err != nil {
+		return err
+	}
+	slashing, err := getSlashingParams(txn)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if err := setParam(params, slashing, change); err != nil {
+			return err
+		}
+	}
+	if err := setMessage(txn, governanceParamsKey, params); err != nil {
+		return err
+	}
+	return setMessage(txn, slashingParamsKey, slashing)
+}
+
+// governancePower is the weight of an owner in governance: the voting power of its stake, none when jailed
+func governancePower(stake *types.Stake) int64 {
+	if stake == nil || stake.Jailed {
+		return 0
+	}
+	return stakePower(stake.Bonded)
+}
+
+// handleGovernanceTransaction opens a proposal or records a vote, only validators with voting power take part.
+// Every check happens before the first write, so a rejected transaction leaves the state untouched.
+func (app *VerificationApp) handleGovernanceTransaction(txn *badger.Txn, owner string, tx *types.GovernanceTransactionData) (uint32, string) {
+	stake, err := getStake(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if governancePower(stake) == 0 {
+		return reject(CodeTypeNotValidator, "Rejected governance transaction: %s is not a validator", owner)
+	}
+
+	switch tx.Action {
+	case types.GovernanceAction_Propose:
+		if len(tx.Changes) == 0 || len(tx.Changes) > maxParamChanges || tx.ProposalId != 0 || tx.Approve {
+			return reject(CodeTypeInvalidProposal, "Rejected proposal: %d changes, between 1 and %d and nothing else expected", len(tx.Changes), maxParamChanges)
+		}
+		// Check the changes against throwaway parameters, they only apply once the proposal passes
+		params, slashing := defaultGovernanceParams(), defaultSlashingParams()
+		seen := make(map[string]bool)
+		for _, change := range tx.Changes {
+			if err := setParam(params, slashing, change); err != nil || seen[change.Name] {
+				return reject(CodeTypeInvalidProposal, "Rejected proposal: %v, or %s changed twice", err, change.Name)
+			}
+			seen[change.Name] = true
+		}
+
+		open, err := getCounter(txn, proposerKey(owner))
+		if err != nil {
+			log.Panicf("Error reading database, unable to execute tx: %v", err)
+		}
+		if open >= maxOpenProposals {
+			return reject(CodeTypeTooManyProposals, "Rejected proposal: %s has %d proposals being voted on", owner, open)
+		}
+
+		id, err := getCounter(txn, lastProposalKey)
+		if err != nil {
+			log.Panicf("Error reading database, unable to execute tx: %v", err)
+		}
+		id++
+		proposal := &types.Proposal{
+			Id:              id,
+			Proposer:        owner,
+			Changes:         tx.Changes,
+			SubmitHeight:    app.height,
+			VotingEndHeight: app.height + votingPeriodBlocks,
+		}
+		if err := setMessage(txn, proposalKey(proposalPrefix, id), proposal); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		if err := setKey(txn, proposalEndKey(proposal.VotingEndHeight, id), nil); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		if err := setCounter(txn, proposerKey(owner), open+1); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		if err := setCounter(txn, lastProposalKey, id); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		app.emit(EventProposalSubmitted, "proposal_id", strconv.FormatUint(id, 10), "proposer", owner)
+
+	case types.GovernanceAction_Vote:
+		if len(tx.Changes) != 0 {
+			return reject(CodeTypeInvalidProposal, "Rejected vote: votes carry no changes")
+		}
+		proposal := &types.Proposal{}
+		found, err := getMessage(txn, proposalKey(proposalPrefix, tx.ProposalId), proposal)
+		if err != nil {
+			log.Panicf("Error reading database, unable to execute tx: %v", err)
+		}
+		if !found {
+			return reject(CodeTypeUnknownProposal, "Rejected vote: no proposal %d", tx.ProposalId)
+		}
+		if proposal.Status != types.ProposalStatus_ProposalVoting {
+			return reject(CodeTypeProposalClosed, "Rejected vote: proposal %d is closed", tx.ProposalId)
+		}
+		// The tally moves with each vote, a vote replacing an earlier one takes back the power that one counted
+		key := append(proposalKey(proposalVotePrefix, tx.ProposalId), owner...)
+		if item, err := txn.Get(key); err == nil {
+			err = item.Value(func(val []byte) error {
+				if len(val) != 9 {
+					return fmt.Errorf("vote of %s on proposal %d is corrupted", owner, tx.ProposalId)
+				}
+				counted := int64(binary.BigEndian.Uint64(val[1:]))
+				if val[0] == 1 {
+					proposal.ApprovePower -= counted
+				} else {
+					proposal.RejectPower -= counted
+				}
+				return nil
+			})
+			if err != nil {
+				log.Panicf("Error reading database, unable to execute tx: %v", err)
+			}
+		} else if err != badger.ErrKeyNotFound {
+			log.Panicf("Error reading database, unable to execute tx: %v", err)
+		}
+		power := governancePower(stake)
+		vote := []byte{0}
+		if tx.Approve {
+			vote[0] = 1
+			proposal.ApprovePower += power
+		} else {
+			proposal.RejectPower += power
+		}
+		if err := setKey(txn, key, binary.BigEndian.AppendUint64(vote, uint64(power))); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		if err := setMessage(txn, proposalKey(proposalPrefix, tx.ProposalId), proposal); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		app.votedProposals[tx.ProposalId] = true
+		app.emit(EventProposalVoted, "proposal_id", strconv.FormatUint(tx.ProposalId, 10), "voter", owner,
+			"approve", strconv.FormatBool(tx.Approve))
+
+	default:
+		return reject(CodeTypeInvalidTxType, "Rejected governance transaction: unknown action")
+	}
+	return CodeTypeOK, ""
+}
+
+// applyPassedProposals runs at the start of every block, before anything reads the parameters: it applies the
+// changes of the proposals the previous block passed, in the order they were submitted.
+func applyPassedProposals(txn *badger.Txn) error {
+	ids := make([]uint64, 0)
+	err := iteratePrefix(txn, passedProposalPrefix, func(item *badger.Item) error {
+		ids = append(ids, binary.BigEndian.Uint64(item.Key()[len(passedProposalPrefix):]))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		proposal := &types.Proposal{}
+		if _, err := getMessage(txn, proposalKey(proposalPrefix, id), proposal); err != nil {
+			return err
+		}
+		if err := applyParamChanges(txn, proposal.Changes); err != nil {
+			return err
+		}
+		if err := deleteKey(txn, proposalKey(passedProposalPrefix, id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tallyProposals runs at the end of every block, over the proposals voted on during the block and the ones whose
+// voting period ends with it. A proposal passes once validators holding more than 2/3 of the power approve it, and
+// its changes apply from the next block, so the whole of a block runs with the same parameters. It is rejected once
+// that is out of reach at the power of the block, or when its voting period ends. Votes count with the power of the voter
+// when it voted. The votes of a closed proposal are dropped, its tally is kept.
+func (app *VerificationApp) tallyProposals(txn *badger.Txn) error {
+	ids := make([]uint64, 0, len(app.votedProposals))
+	for id := range app.votedProposals {
+		ids = append(ids, id)
+	}
+	prefix := binary.BigEndian.AppendUint64(append([]byte{}, proposalEndPrefix...), uint64(app.height))
+	err := iteratePrefix(txn, prefix, func(item *badger.Item) error {
+		if id := binary.BigEndian.Uint64(item.Key()[len(prefix):]); !app.votedProposals[id] {
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	// Proposals close in the order they were submitted
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	total, err := stakedPower(txn, "")
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		proposal := &types.Proposal{}
+		if _, err := getMessage(txn, proposalKey(proposalPrefix, id), proposal); err != nil {
+			return err
+		}
+		proposal.TotalPower = total
+
+		// Same rule as CometBFT's commits: strictly more than 2/3 of the total power
+		switch {
+		case proposal.ApprovePower*3 > total*2:
+			proposal.Status = types.ProposalStatus_ProposalPassed
+			if err := setKey(txn, proposalKey(passedProposalPrefix, id), nil); err != nil {
+				return err
+			}
+		case proposal.RejectPower*3 >= total || app.height >= proposal.VotingEndHeight:
+			proposal.Status = types.ProposalStatus_ProposalRejected
+		}
+		if err := setMessage(txn, proposalKey(proposalPrefix, id), proposal); err != nil {
+			return err
+		}
+		if proposal.Status == types.ProposalStatus_ProposalVoting {
+			continue
+		}
+
+		logger.Infof("Proposal %d closed: %s", id, proposal.Status)
+		app.emit(EventProposalClosed, "proposal_id", strconv.FormatUint(id, 10), "status", proposal.Status.String())
+		if err := closeProposal(txn, proposal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeProposal drops what a proposal needs while it is being voted on
+func closeProposal(txn *badger.Txn, proposal *types.Proposal) error {
+	if err := deleteKey(txn, proposalEndKey(proposal.VotingEndHeight, proposal.Id)); err != nil {
+		return err
+	}
+	open, err := getCounter(txn, proposerKey(proposal.Proposer))
+	if err != nil {
+		return err
+	}
+	if open > 1 {
+		err = setCounter(txn, proposerKey(proposal.Proposer), open-1)
+	} else {
+		err = deleteKey(txn, proposerKey(proposal.Proposer))
+	}
+	if err != nil {
+		return err
+	}
+	return dropPrefix(txn, proposalKey(proposalVotePrefix, proposal.Id))
+}
@@ -0,0 +1,51 @@
+package verificationApp
+
+import (
+	"log"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+)
+
+// checkNonce compares the nonce of a transaction to the next nonce expected from its owner
+func checkNonce(expected, nonce uint64) uint32 {
+	switch {
+	case nonce < expected:
+		return CodeTypeNonceTooLow
+	case nonce > expected:
+		return CodeTypeNonceGap
+	default:
+		return CodeTypeOK
+	}
+}
+
+// checkPendingNonce admits a transaction to the mempool only if it carries the next nonce of its owner,
+// counting the transactions from the same owner that are already waiting in the mempool. An admitted transaction
+// adds its fee to the pending fees of its owner, which checkFee counts.
+//
+// The pending nonces and fees are dropped on every Commit. CometBFT then rechecks the remaining mempool
+// transactions in order, which rebuilds them from the committed state and evicts everything
+// that was executed in the meantime.
+func (app *VerificationApp) checkPendingNonce(tx *types.Transaction) (uint32, string) {
+	expected, ok := app.pendingNonces[tx.Owner]
+	if !ok {
+		err := view(app.db, func(txn *badger.Txn) error {
+			account, err := getAccount(txn, tx.Owner)
+			if err != nil {
+				return err
+			}
+			expected = account.Nonce
+			return nil
+		})
+		if err != nil {
+			log.Panicf("Error reading database, unable to check tx: %v", err)
+		}
+	}
+
+	if code := checkNonce(expected, tx.Nonce); code != CodeTypeOK {
+		return reject(code, "Rejected transaction from %s: expected nonce %d, got %d", tx.Owner, expected, tx.Nonce)
+	}
+	app.pendingNonces[tx.Owner] = tx.Nonce + 1
+	app.pendingFees[tx.Owner] += tx.Fee
+	return CodeTypeOK, ""
+}
@@ -0,0 +1,192 @@
+package verificationApp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxTxBytes bounds the transactions sent by accounts, those added by the proposer are only bounded by the block size
+const maxTxBytes = 64 << 10
+
+// isProposerTransaction reports whether transactions of this type are added by the block proposer rather than sent to the mempool
+func isProposerTransaction(txType types.TransactionType) bool {
+	return txType == types.TransactionType_AttestationTransaction || txType == types.TransactionType_AssignmentTransaction
+}
+
+// proposalTxType returns the type of a transaction in a proposal, without checking anything else about it
+func proposalTxType(tx []byte) (types.TransactionType, bool) {
+	var transaction types.Transaction
+	if err := proto.Unmarshal(tx, &transaction); err != nil {
+		return 0, false
+	}
+	return transaction.Type, true
+}
+
+// blockTxs checks the transactions from the mempool in the order they appear in a block.
+// Every transaction is only there once, and the transactions of an owner have consecutive nonces,
+// so none of them is bound to fail because of the ones before it.
+type blockTxs struct {
+	seen   map[string]bool
+	nonces map[string]uint64
+}
+
+func newBlockTxs() *blockTxs {
+	return &blockTxs{seen: make(map[string]bool), nonces: make(map[string]uint64)}
+}
+
+// admit checks the next mempool transaction of the block and records it if it may follow the ones before it
+func (b *blockTxs) admit(tx []byte) bool {
+	if len(tx) > maxTxBytes || b.seen[string(tx)] {
+		return false
+	}
+	var transaction types.Transaction
+	if err := proto.Unmarshal(tx, &transaction); err != nil || isProposerTransaction(transaction.Type) {
+		return false
+	}
+	if next, ok := b.nonces[transaction.Owner]; ok && transaction.Nonce != next {
+		return false
+	}
+
+	b.seen[string(tx)] = true
+	b.nonces[transaction.Owner] = transaction.Nonce + 1
+	return true
+}
+
+// proposalView runs fn on the state the block at the given height starts from: the committed state with the
+// staking changes due at that height and the passed proposals, which FinalizeBlock applies before anything else.
+// Nothing is written.
+func proposalView(db *badger.DB, height int64, fn func(txn *badger.Txn) error) error {
+	txn := db.NewTransactionAt(latestVersion, true)
+	defer txn.Discard()
+	if err := applyDueStakingChanges(txn, height); err != nil {
+		return err
+	}
+	if err := applyPassedProposals(txn); err != nil {
+		return err
+	}
+	return fn(txn)
+}
+
+// PrepareProposal lays out the block: the attestation of the last commit, the collector assignment of the block,
+// then the mempool transactions that fit, highest gas price first.
+func (app *VerificationApp) PrepareProposal(_ context.Context, proposal *abcitypes.RequestPrepareProposal) (*abcitypes.ResponsePrepareProposal, error) {
+	txs := make([][]byte, 0, len(proposal.Txs)+2)
+	var size int64
+
+	attestation, err := newAttestationTransaction(proposal.LocalLastCommit, proposal.Height-1)
+	if err != nil {
+		return nil, err
+	}
+	if attestation != nil {
+		txs = append(txs, attestation)
+		size += int64(len(attestation))
+	}
+
+	var assignment []byte
+	err = proposalView(app.db, proposal.Height, func(txn *badger.Txn) (err error) {
+		assignment, err = newAssignmentTransaction(txn, proposal.Height)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if assignment != nil {
+		txs = append(txs, assignment)
+		size += int64(len(assignment))
+	}
+	// Without its attestation or its assignment the block would be rejected
+	if size > proposal.MaxTxBytes {
+		return nil, fmt.Errorf("the attestation and the assignment of block %d take %d bytes, more than the %d of a block", proposal.Height, size, proposal.MaxTxBytes)
+	}
+
+	block := newBlockTxs()
+	for _, tx := range prioritise(proposal.Txs) {
+		if size+int64(len(tx)) > proposal.MaxTxBytes {
+			break
+		}
+		if !block.admit(tx) {
+			continue
+		}
+		txs = append(txs, tx)
+		size += int64(len(tx))
+	}
+	return &abcitypes.ResponsePrepareProposal{Txs: txs}, nil
+}
+
+// hasPrecommits tells whether a last commit has precommits for the block, each of them carries a signed extension
+func hasPrecommits(commit abcitypes.CommitInfo) bool {
+	for _, vote := range commit.Votes {
+		if vote.BlockIdFlag == cmtproto.BlockIDFlagCommit {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessProposal accepts a block only if it is laid out as PrepareProposal does: the attestation of the
+// precommits of its last commit, whenever there are any, the expected assignment, then mempool transactions
+// that may follow each other.
+func (app *VerificationApp) ProcessProposal(_ context.Context, proposal *abcitypes.RequestProcessProposal) (*abcitypes.ResponseProcessProposal, error) {
+	accept := &abcitypes.ResponseProcessProposal{Status: abcitypes.ResponseProcessProposal_ACCEPT}
+	reject := &abcitypes.ResponseProcessProposal{Status: abcitypes.ResponseProcessProposal_REJECT}
+
+	txs := proposal.Txs
+	valid := true
+	err := proposalView(app.db, proposal.Height, func(txn *badger.Txn) error {
+		if hasPrecommits(proposal.ProposedLastCommit) {
+			if len(txs) == 0 {
+				valid = false
+				return nil
+			}
+			transaction, code, _ := decodeTransaction(txs[0])
+			if code != CodeTypeOK || transaction.Type != types.TransactionType_AttestationTransaction {
+				valid = false
+				return nil
+			}
+			attestation := transaction.GetAttestationData()
+			if !attestsCommit(attestation, proposal.ProposedLastCommit) {
+				valid = false
+				return nil
+			}
+			if _, code, _ = app.verifyAttestation(txn, attestation, proposal.Height); code != CodeTypeOK {
+				valid = false
+				return nil
+			}
+			txs = txs[1:]
+		}
+
+		assignment, err := newAssignmentTransaction(txn, proposal.Height)
+		if err != nil {
+			return err
+		}
+		if assignment != nil {
+			if len(txs) == 0 || !bytes.Equal(txs[0], assignment) {
+				logger.Warnf("Rejected proposal at height %d: missing or wrong collector assignment", proposal.Height)
+				valid = false
+				return nil
+			}
+			txs = txs[1:]
+		}
+		return nil
+	})
+	if err != nil || !valid {
+		return reject, err
+	}
+
+	block := newBlockTxs()
+	for i, tx := range txs {
+		if !block.admit(tx) {
+			logger.Warnf("Rejected proposal at height %d: transaction %d is misplaced, repeated, too large or out of nonce order", proposal.Height, i)
+			return reject, nil
+		}
+	}
+	return accept, nil
+}
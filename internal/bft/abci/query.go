@@ -0,0 +1,234 @@
+package verificationApp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Query paths. Answers are protobuf-encoded, except /store which returns the raw value of a key.
+const (
+	pathAccount      = "/account/"      // /account/{owner} -> Account
+	pathVerification = "/verification/" // /verification/{cid} -> VerifiedCid
+	pathDatasource   = "/datasource/"   // /datasource/{name}/latest -> latest verified VerificationRound
+	pathValidators   = "/validators"    // -> ValidatorSet
+	pathParams       = "/params"        // -> Params
+	pathRewards      = "/rewards/"      // /rewards/{epoch} -> RewardLedger of a finished epoch
+	pathStake        = "/stake/"        // /stake/{owner} -> Stake, with its jail and misbehaviour records
+	pathProposal     = "/proposal/"     // /proposal/{id} -> Proposal, with its last tally
+	pathUpgrade      = "/upgrade"       // -> UpgradePlan, the last one scheduled
+	pathStore        = "/store"         // Data is a raw key -> its value
+)
+
+// SetHistoryBlocks sets how many past blocks keep their state queryable, 0 keeps every block.
+// Older states are discarded by Badger compactions.
+func (app *VerificationApp) SetHistoryBlocks(blocks uint64) {
+	app.historyBlocks = int64(blocks)
+}
+
+// discardHistory lets Badger drop the versions no query can ask for any more.
+// Snapshots still being written pin the state they read.
+func (app *VerificationApp) discardHistory() {
+	if app.historyBlocks <= 0 || app.height <= app.historyBlocks {
+		return
+	}
+	oldest := app.height - app.historyBlocks
+	if app.snapshots != nil {
+		if pending, ok := app.snapshots.oldestPending(); ok {
+			oldest = min(oldest, int64(pending))
+		}
+	}
+	app.db.SetDiscardTs(stateVersion(oldest))
+}
+
+// Query answers from the state after the requested height, the latest one if it is 0.
+// With Prove set, single items come with a proof against the app hash of that height, checked with ProofRuntime,
+// which CometBFT puts in the header of the next block. Items that don't exist can't be proven.
+func (app *VerificationApp) Query(_ context.Context, req *abcitypes.RequestQuery) (*abcitypes.ResponseQuery, error) {
+	latest, _, err := loadCommitInfo(app.db)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute query: %v", err)
+	}
+	height := latest
+	if req.Height != 0 {
+		height = req.Height
+	}
+	if height < 0 || height > latest {
+		return &abcitypes.ResponseQuery{Code: CodeTypeInvalidHeight, Log: fmt.Sprintf("latest height is %d", latest), Height: height}, nil
+	}
+
+	resp := &abcitypes.ResponseQuery{Height: height}
+	err = viewAt(app.db, stateVersion(height), func(txn *badger.Txn) error {
+		// Every block records its height, a different one means the state of that block is gone
+		if stored, err := readLastHeight(txn); err != nil || stored != height {
+			resp.Code, resp.Log = CodeTypeInvalidHeight, fmt.Sprintf("state at height %d was pruned", height)
+			return err
+		}
+		return app.route(txn, req, resp)
+	})
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute query: %v", err)
+	}
+	return resp, nil
+}
+
+// route answers the query according to its path
+func (app *VerificationApp) route(txn *badger.Txn, req *abcitypes.RequestQuery, resp *abcitypes.ResponseQuery) error {
+	switch path := req.Path; {
+	case path == "/account":
+		// Older clients send the owner as data
+		return answerAccount(txn, string(req.Data), req.Prove, resp)
+	case strings.HasPrefix(path, pathAccount):
+		return answerAccount(txn, strings.TrimPrefix(path, pathAccount), req.Prove, resp)
+	case strings.HasPrefix(path, pathVerification):
+		return queryKey(txn, verifiedCidKey(strings.TrimPrefix(path, pathVerification)), req.Prove, resp)
+	case strings.HasPrefix(path, pathDatasource) && strings.HasSuffix(path, "/latest"):
+		datasource := strings.TrimSuffix(strings.TrimPrefix(path, pathDatasource), "/latest")
+		key, err := latestVerifiedRound(txn, datasource)
+		if err != nil || key == nil {
+			resp.Code, resp.Log = CodeTypeNotFound, "no verified round"
+			return err
+		}
+		return queryKey(txn, key, req.Prove, resp)
+	case strings.HasPrefix(path, pathStake):
+		return queryKey(txn, stakeKey(strings.TrimPrefix(path, pathStake)), req.Prove, resp)
+	case strings.HasPrefix(path, pathRewards):
+		epoch, err := strconv.ParseInt(strings.TrimPrefix(path, pathRewards), 10, 64)
+		if err != nil || epoch < 0 {
+			resp.Code, resp.Log = CodeTypeNotFound, "epochs are non-negative integers"
+			return nil
+		}
+		return queryKey(txn, ledgerKey(epoch), req.Prove, resp)
+	case strings.HasPrefix(path, pathProposal):
+		id, err := strconv.ParseUint(strings.TrimPrefix(path, pathProposal), 10, 64)
+		if err != nil {
+			resp.Code, resp.Log = CodeTypeNotFound, "proposal IDs are positive integers"
+			return nil
+		}
+		return queryKey(txn, proposalKey(proposalPrefix, id), req.Prove, resp)
+	case path == pathValidators:
+		validators, err := listValidators(txn)
+		if err != nil {
+			return err
+		}
+		return setAnswer(resp, &types.ValidatorSet{Validators: validators})
+	case path == pathUpgrade:
+		return queryKey(txn, upgradePlanKey, req.Prove, resp)
+	case path == pathParams:
+		params, err := currentParams(txn)
+		if err != nil {
+			return err
+		}
+		return setAnswer(resp, params)
+	case path == pathStore || path == "":
+		return queryKey(txn, req.Data, req.Prove, resp)
+	default:
+		resp.Code, resp.Log = CodeTypeUnknownQuery, fmt.Sprintf("unknown query path %s", path)
+		return nil
+	}
+}
+
+// answerAccount answers with the account of an owner, an account that was never written is empty
+func answerAccount(txn *badger.Txn, owner string, prove bool, resp *abcitypes.ResponseQuery) error {
+	if _, _, err := types.ParseOwner(owner); err != nil {
+		resp.Code, resp.Log = CodeTypeInvalidOwner, err.Error()
+		return nil
+	}
+	if err := queryKey(txn, accountKey(owner), prove, resp); err != nil || resp.Code != CodeTypeNotFound {
+		return err
+	}
+	resp.Code, resp.Log = CodeTypeOK, "account does not exist"
+	return setAnswer(resp, &types.Account{})
+}
+
+// queryKey answers with the value stored under the key, and its proof if asked for
+func queryKey(txn *badger.Txn, key []byte, prove bool, resp *abcitypes.ResponseQuery) error {
+	resp.Key = key
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		resp.Code, resp.Log = CodeTypeNotFound, "key does not exist"
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if resp.Value, err = item.ValueCopy(nil); err != nil {
+		return err
+	}
+
+	if prove {
+		if resp.ProofOps, err = stateProof(txn, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAnswer answers with an encoded message that is not stored as such, and so comes without a proof
+func setAnswer(resp *abcitypes.ResponseQuery, msg proto.Message) (err error) {
+	resp.Value, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	return err
+}
+
+// latestVerifiedRound returns the key of the most recent round of the datasource that reached a quorum, or nil
+func latestVerifiedRound(txn *badger.Txn, datasource string) ([]byte, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	opts.Prefix = roundID(roundPrefix, datasource, 0)[:len(roundPrefix)+len(datasource)+1]
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	// Reverse iteration starts at the largest key not above the seek key, past every timestamp of the datasource
+	for it.Seek(append(append([]byte{}, opts.Prefix...), 0xff)); it.Valid(); it.Next() {
+		round := &types.VerificationRound{}
+		err := it.Item().Value(func(val []byte) error {
+			return proto.Unmarshal(val, round)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if round.Attestation != "" {
+			return it.Item().KeyCopy(nil), nil
+		}
+	}
+	return nil, nil
+}
+
+// currentParams returns the rules the application runs with
+func currentParams(txn *badger.Txn) (*types.Params, error) {
+	slashing, err := getSlashingParams(txn)
+	if err != nil {
+		return nil, err
+	}
+	governance, err := getGovernanceParams(txn)
+	if err != nil {
+		return nil, err
+	}
+	updateKeys, err := listUpdateKeys(txn)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Params{
+		AssignmentReplication: governance.AssignmentReplication,
+		MaxRoundAge:           maxRoundAge,
+		MaxSubmittedCids:      maxSubmittedCids,
+		MaxTxBytes:            maxTxBytes,
+		MaxVoteExtensionBytes: maxVoteExtensionSize,
+		UnbondingBlocks:       unbondingBlocks,
+		PowerReduction:        powerReduction,
+		EpochBlocks:           epochBlocks,
+		EpochReward:           governance.EpochReward,
+		UnitsPerVerifiedRound: governance.UnitsPerVerifiedRound,
+		Slashing:              slashing,
+		Governance:            governance,
+		VotingPeriodBlocks:    votingPeriodBlocks,
+		UpdateKeys:            updateKeys,
+		UpgradeNoticeBlocks:   upgradeNoticeBlocks,
+	}, nil
+}
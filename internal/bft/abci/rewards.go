@@ -0,0 +1,208 @@
+package verificationApp
+
+import (
+	"encoding/binary"
+	"log"
+	"math"
+	"math/bits"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// epochBlocks is the length of a reward epoch, rewards are distributed by its last block
+	epochBlocks = 100
+	// epochReward is minted and shared between the resource reports of every epoch, 100 tokens, until governance changes it
+	epochReward uint64 = 100_000_000
+	// unitsPerVerifiedRound caps the units a node is rewarded for by the rounds it helped verify during the epoch,
+	// until governance changes it. The reports are the node's own word, the verified rounds are on chain.
+	unitsPerVerifiedRound uint64 = 1_000
+)
+
+var (
+	contributionPrefix = []byte("contrib/") // Epoch, owner -> verified rounds the owner agreed with during the epoch
+	reportPrefix       = []byte("report/")  // Epoch, owner -> ResourceTransactionData, the last report of the owner
+	ledgerPrefix       = []byte("ledger/")  // Epoch -> RewardLedger
+
+	// Total of the balances, stakes and unbonding stakes, minting never lets it overflow
+	supplyKey = []byte("supply")
+)
+
+// epochOf returns the epoch a height belongs to, the first epoch starts at height 1
+func epochOf(height int64) int64 {
+	return (height - 1) / epochBlocks
+}
+
+func epochKey(prefix []byte, epoch int64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, prefix...), uint64(epoch))
+}
+
+func ledgerKey(epoch int64) []byte {
+	return epochKey(ledgerPrefix, epoch)
+}
+
+// getCounter reads a big-endian counter, 0 if it was never written
+func getCounter(txn *badger.Txn, key []byte) (uint64, error) {
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var counter uint64
+	err = item.Value(func(val []byte) error {
+		counter = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	return counter, err
+}
+
+func setCounter(txn *badger.Txn, key []byte, counter uint64) error {
+	return setKey(txn, key, binary.BigEndian.AppendUint64(nil, counter))
+}
+
+// recordContributions counts a verified round for each validator that agreed with it
+func recordContributions(txn *badger.Txn, height int64, owners []string) error {
+	for _, owner := range owners {
+		key := append(epochKey(contributionPrefix, epochOf(height)), owner...)
+		rounds, err := getCounter(txn, key)
+		if err != nil {
+			return err
+		}
+		if err := setCounter(txn, key, rounds+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleResourceTransaction stores the resource report of a node for the current epoch, replacing its previous one.
+// Only nodes that agreed with a verified round during the epoch have contributed anything worth a reward.
+func (app *VerificationApp) handleResourceTransaction(txn *badger.Txn, owner string, tx *types.ResourceTransactionData) (uint32, string) {
+	if tx.RewardedResourceUnits > tx.TotalResourceUnits {
+		return reject(CodeTypeInvalidAmount, "Rejected resource transaction: more resources rewarded than contributed")
+	}
+	breakdown, carry := bits.Add64(tx.BandwidthUnits, tx.StorageUnits, 0)
+	breakdown, carry2 := bits.Add64(breakdown, tx.UptimeUnits, 0)
+	if carry+carry2 != 0 || (breakdown != 0 && breakdown != tx.TotalResourceUnits) {
+		return reject(CodeTypeInvalidAmount, "Rejected resource transaction: the breakdown does not add up to the total")
+	}
+	if tx.Epoch != epochOf(app.height) {
+		return reject(CodeTypeInvalidEpoch, "Rejected resource transaction: reports epoch %d during epoch %d", tx.Epoch, epochOf(app.height))
+	}
+
+	rounds, err := getCounter(txn, append(epochKey(contributionPrefix, tx.Epoch), owner...))
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if rounds == 0 {
+		return reject(CodeTypeUnverifiedResources, "Rejected resource transaction: %s agreed with no verified round in epoch %d", owner, tx.Epoch)
+	}
+
+	if err := setMessage(txn, append(epochKey(reportPrefix, tx.Epoch), owner...), tx); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	return CodeTypeOK, ""
+}
+
+// share returns pool * units / total rounded down, units must not exceed total
+func share(pool, units, total uint64) uint64 {
+	hi, lo := bits.Mul64(pool, units)
+	quotient, _ := bits.Div64(hi, lo, total)
+	return quotient
+}
+
+// distributeRewards runs at the end of the last block of an epoch: the reward pool, the collected fees
+// and what is minted, is shared between the reports of the epoch in proportion to their rewarded units,
+// capped by the verified rounds of their node. The fees are paid out first, what is left of them stays in
+// the pool for the next epoch. The ledger of the epoch is kept, the reports and contributions are dropped.
+func (app *VerificationApp) distributeRewards(txn *badger.Txn) error {
+	if app.height%epochBlocks != 0 {
+		return nil
+	}
+	epoch := epochOf(app.height)
+	ledger := &types.RewardLedger{Epoch: epoch, Rewards: make([]*types.Reward, 0)}
+	params, err := getGovernanceParams(txn)
+	if err != nil {
+		return err
+	}
+
+	prefix := epochKey(reportPrefix, epoch)
+	err = iteratePrefix(txn, prefix, func(item *badger.Item) error {
+		report := &types.ResourceTransactionData{}
+		if err := item.Value(func(val []byte) error { return proto.Unmarshal(val, report) }); err != nil {
+			return err
+		}
+		ledger.Rewards = append(ledger.Rewards, &types.Reward{
+			Owner:         string(item.Key()[len(prefix):]),
+			ReportedUnits: report.RewardedResourceUnits,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, reward := range ledger.Rewards {
+		reward.VerifiedRounds, err = getCounter(txn, append(epochKey(contributionPrefix, epoch), reward.Owner...))
+		if err != nil {
+			return err
+		}
+		overflow, capped := bits.Mul64(reward.VerifiedRounds, params.UnitsPerVerifiedRound)
+		reward.RewardedUnits = reward.ReportedUnits
+		if overflow == 0 && capped < reward.RewardedUnits {
+			reward.RewardedUnits = capped
+		}
+		// Units beyond what fits in the total don't change anyone's share by a measurable amount
+		ledger.TotalUnits += min(reward.RewardedUnits, math.MaxUint64-ledger.TotalUnits)
+	}
+
+	supply, err := getCounter(txn, supplyKey)
+	if err != nil {
+		return err
+	}
+	fees, err := getCounter(txn, feePoolKey)
+	if err != nil {
+		return err
+	}
+	ledger.Fees = fees
+	// The fees are part of the supply, so the pool can't overflow
+	pool := fees + min(params.EpochReward, math.MaxUint64-supply)
+	if ledger.TotalUnits > 0 {
+		for _, reward := range ledger.Rewards {
+			reward.Amount = share(pool, min(reward.RewardedUnits, ledger.TotalUnits), ledger.TotalUnits)
+			if reward.Amount == 0 {
+				continue
+			}
+			account, err := getAccount(txn, reward.Owner)
+			if err != nil {
+				return err
+			}
+			// The supply bounds every balance, so this can't overflow
+			account.Balance += reward.Amount
+			if err := setAccount(txn, reward.Owner, account); err != nil {
+				return err
+			}
+			ledger.Distributed += reward.Amount
+			app.emit(EventRewardPaid, "owner", reward.Owner, "epoch", strconv.FormatInt(epoch, 10), "amount", strconv.FormatUint(reward.Amount, 10))
+		}
+	}
+
+	paidFees := min(fees, ledger.Distributed)
+	if err := setCounter(txn, feePoolKey, fees-paidFees); err != nil {
+		return err
+	}
+	if err := setCounter(txn, supplyKey, supply+ledger.Distributed-paidFees); err != nil {
+		return err
+	}
+	if err := setMessage(txn, ledgerKey(epoch), ledger); err != nil {
+		return err
+	}
+	if err := dropPrefix(txn, prefix); err != nil {
+		return err
+	}
+	return dropPrefix(txn, epochKey(contributionPrefix, epoch))
+}
@@ -0,0 +1,277 @@
+package verificationApp
+
+import (
+	"strconv"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmted25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// basisPoints is a whole stake in the fractions of SlashingParams
+const basisPoints = 10_000
+
+// Slashing parameters set by genesis.json
+var slashingParamsKey = []byte("params/slashing")
+
+// defaultSlashingParams apply when genesis.json does not set any
+func defaultSlashingParams() *types.SlashingParams {
+	return &types.SlashingParams{
+		DoubleSignSlash: 500, // 5%
+		WrongDataSlash:  100, // 1%
+		DowntimeSlash:   10,  // 0.1%
+		MaxMissedBlocks: 50,
+		MaxWrongRounds:  3,
+		JailBlocks:      600,
+	}
+}
+
+// getSlashingParams returns the slashing parameters of the chain
+func getSlashingParams(txn *badger.Txn) (*types.SlashingParams, error) {
+	params := &types.SlashingParams{}
+	found, err := getMessage(txn, slashingParamsKey, params)
+	if !found && err == nil {
+		return defaultSlashingParams(), nil
+	}
+	return params, err
+}
+
+// Misbehaviours a validator is punished for
+type misbehaviour int
+
+const (
+	misbehaviourDoubleSign misbehaviour = iota
+	misbehaviourWrongData
+	misbehaviourDowntime
+)
+
+func (m misbehaviour) String() string {
+	switch m {
+	case misbehaviourDoubleSign:
+		return "double signing"
+	case misbehaviourWrongData:
+		return "wrong data"
+	default:
+		return "downtime"
+	}
+}
+
+// slashFraction returns the fraction of the stake a misbehaviour costs, in basis points
+func (m misbehaviour) slashFraction(params *types.SlashingParams) uint64 {
+	switch m {
+	case misbehaviourDoubleSign:
+		return uint64(params.DoubleSignSlash)
+	case misbehaviourWrongData:
+		return uint64(params.WrongDataSlash)
+	default:
+		return uint64(params.DowntimeSlash)
+	}
+}
+
+// punish slashes the bonded and unbonding stake of the owner and jails it.
+// The last validator with any power is slashed but stays in the set, CometBFT can't run without one.
+func (app *VerificationApp) punish(txn *badger.Txn, owner string, m misbehaviour) error {
+	params, err := getSlashingParams(txn)
+	if err != nil {
+		return err
+	}
+	fraction := m.slashFraction(params)
+
+	burnt, err := slashUnbonding(txn, owner, fraction)
+	if err != nil {
+		return err
+	}
+	stake, err := getStake(txn, owner)
+	if err != nil {
+		return err
+	}
+	jailed := false
+	if stake != nil {
+		slashed := share(stake.Bonded, fraction, basisPoints)
+		stake.Bonded -= slashed
+		stake.Slashed += slashed + burnt
+		burnt += slashed
+
+		others, err := stakedPower(txn, owner)
+		if err != nil {
+			return err
+		}
+		if others > 0 {
+			jailed = true
+			stake.Jailed = true
+			stake.JailedUntil = max(stake.JailedUntil, app.height+params.JailBlocks)
+		}
+		if err := setMessage(txn, stakeKey(owner), stake); err != nil {
+			return err
+		}
+		app.stakeChanges[owner] = true
+	}
+	logger.Infof("Punished %s for %s: %d burnt", owner, m, burnt)
+	app.emit(EventValidatorSlashed, "owner", owner, "reason", m.String(), "burnt", strconv.FormatUint(burnt, 10),
+		"jailed", strconv.FormatBool(jailed), "height", strconv.FormatInt(app.height, 10))
+
+	// Burnt stake leaves the supply
+	supply, err := getCounter(txn, supplyKey)
+	if err != nil {
+		return err
+	}
+	return setCounter(txn, supplyKey, supply-min(burnt, supply))
+}
+
+// slashUnbonding burns the fraction of every stake the owner is unbonding, misbehaviour during the
+// unbonding period is punished as if the stake was still bonded.
+func slashUnbonding(txn *badger.Txn, owner string, fraction uint64) (uint64, error) {
+	keys := make([][]byte, 0)
+	entries := make([]*types.Unbonding, 0)
+	err := iteratePrefix(txn, unbondingPrefix, func(item *badger.Item) error {
+		if string(item.Key()[len(unbondingPrefix)+8:]) != owner {
+			return nil
+		}
+		unbonding := &types.Unbonding{}
+		keys, entries = append(keys, item.KeyCopy(nil)), append(entries, unbonding)
+		return item.Value(func(val []byte) error { return proto.Unmarshal(val, unbonding) })
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var burnt uint64
+	for i, unbonding := range entries {
+		slashed := share(unbonding.Amount, fraction, basisPoints)
+		unbonding.Amount -= slashed
+		burnt += slashed
+		if err := setMessage(txn, keys[i], unbonding); err != nil {
+			return 0, err
+		}
+	}
+	return burnt, nil
+}
+
+// recordWrongData tracks the rounds validators disagreed with. A validator whose window was cut a little apart from
+// the others may miss a round now and then, only one that disagrees with maxWrongRounds rounds in a row, without
+// agreeing with any in between, is punished for wrong data.
+func (app *VerificationApp) recordWrongData(txn *badger.Txn, agreeing, disagreeing []string) error {
+	params, err := getSlashingParams(txn)
+	if err != nil {
+		return err
+	}
+	for _, owner := range agreeing {
+		stake, err := getStake(txn, owner)
+		if err != nil {
+			return err
+		}
+		if stake != nil && stake.WrongRounds > 0 {
+			stake.WrongRounds = 0
+			if err := setMessage(txn, stakeKey(owner), stake); err != nil {
+				return err
+			}
+		}
+	}
+	for _, owner := range disagreeing {
+		stake, err := getStake(txn, owner)
+		if err != nil {
+			return err
+		}
+		if stake == nil {
+			continue
+		}
+		stake.WrongSubmissions++
+		stake.WrongRounds++
+		wrong := stake.WrongRounds >= params.MaxWrongRounds
+		if wrong {
+			stake.WrongRounds = 0
+		}
+		if err := setMessage(txn, stakeKey(owner), stake); err != nil {
+			return err
+		}
+		if wrong {
+			if err := app.punish(txn, owner, misbehaviourWrongData); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stakesByAddress maps the consensus addresses CometBFT reports validators by to their owner
+func stakesByAddress(txn *badger.Txn) (map[string]string, error) {
+	owners := make(map[string]string)
+	err := iteratePrefix(txn, stakePrefix, func(item *badger.Item) error {
+		stake := &types.Stake{}
+		if err := item.Value(func(val []byte) error { return proto.Unmarshal(val, stake) }); err != nil {
+			return err
+		}
+		owners[string(cmted25519.PubKey(stake.PubKey).Address())] = string(item.Key()[len(stakePrefix):])
+		return nil
+	})
+	return owners, err
+}
+
+// handleCommitInfo punishes the misbehaviour CometBFT found evidence of, and the validators
+// that missed signing too many commits in a row, which also left their attestations out.
+func (app *VerificationApp) handleCommitInfo(txn *badger.Txn, commit abcitypes.CommitInfo, evidence []abcitypes.Misbehavior) error {
+	if len(commit.Votes) == 0 && len(evidence) == 0 {
+		return nil
+	}
+	owners, err := stakesByAddress(txn)
+	if err != nil {
+		return err
+	}
+
+	for _, misbehavior := range evidence {
+		owner, ok := owners[string(misbehavior.Validator.Address)]
+		if !ok {
+			// The validator unbonded everything and its unbonding period is over
+			continue
+		}
+		if err := app.punish(txn, owner, misbehaviourDoubleSign); err != nil {
+			return err
+		}
+	}
+
+	params, err := getSlashingParams(txn)
+	if err != nil {
+		return err
+	}
+	for _, vote := range commit.Votes {
+		owner, ok := owners[string(vote.Validator.Address)]
+		if !ok {
+			continue
+		}
+		stake, err := getStake(txn, owner)
+		if err != nil {
+			return err
+		}
+		// A validator that was just jailed is still in the set CometBFT collects votes from for a few blocks
+		if stake.Jailed {
+			continue
+		}
+
+		if vote.BlockIdFlag != cmtproto.BlockIDFlagAbsent {
+			if stake.MissedBlocks == 0 {
+				continue
+			}
+			stake.MissedBlocks = 0
+		} else {
+			stake.MissedBlocks++
+		}
+		// The count starts over once the validator is punished
+		down := stake.MissedBlocks >= params.MaxMissedBlocks
+		if down {
+			stake.MissedBlocks = 0
+		}
+		if err := setMessage(txn, stakeKey(owner), stake); err != nil {
+			return err
+		}
+		if down {
+			if err := app.punish(txn, owner, misbehaviourDowntime); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,444 @@
+package verificationApp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// snapshotFormat is bumped whenever the layout of the chunks changes, older nodes then refuse the snapshot
+	snapshotFormat uint32 = 3
+	// defaultSnapshotChunkSize is used when SnapshotConfig leaves it out, well below the CometBFT message limit
+	defaultSnapshotChunkSize = 4 << 20
+	// snapshotMetadataFile holds the encoded abcitypes.Snapshot inside a snapshot directory
+	snapshotMetadataFile = "metadata"
+)
+
+// SnapshotConfig sets when snapshots are taken and how many are kept
+type SnapshotConfig struct {
+	Dir        string // Directory holding one sub-directory per snapshot height
+	Interval   uint64 // Take a snapshot at every height that is a multiple of it
+	KeepRecent uint32 // Number of snapshots kept, 0 keeps them all
+	ChunkSize  int    // Bytes, 0 for defaultSnapshotChunkSize
+}
+
+// snapshotStore writes, lists and prunes the snapshots on disk
+type snapshotStore struct {
+	config SnapshotConfig
+	lock   sync.Mutex     // Held while a snapshot directory is created, read or removed
+	wg     sync.WaitGroup // Snapshots being written in the background
+
+	pending map[uint64]bool // Heights of the snapshots being written, guarded by lock
+}
+
+// restoreState tracks a snapshot accepted by OfferSnapshot while its chunks are applied
+type restoreState struct {
+	snapshot    *abcitypes.Snapshot
+	appHash     []byte
+	chunkHashes [][]byte
+	next        uint32
+}
+
+// EnableSnapshots makes the application take snapshots on Commit and serve them to nodes that state sync
+func (app *VerificationApp) EnableSnapshots(config SnapshotConfig) error {
+	if config.Interval == 0 {
+		return errors.New("snapshot interval must be positive")
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaultSnapshotChunkSize
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return err
+	}
+	app.snapshots = &snapshotStore{config: config, pending: make(map[uint64]bool)}
+	return nil
+}
+
+// appendPair encodes a key-value pair of the state into a chunk, both parts length-prefixed
+func appendPair(chunk, key, value []byte) []byte {
+	chunk = binary.AppendUvarint(chunk, uint64(len(key)))
+	chunk = append(chunk, key...)
+	chunk = binary.AppendUvarint(chunk, uint64(len(value)))
+	return append(chunk, value...)
+}
+
+// readPairs decodes the key-value pairs of a chunk
+func readPairs(chunk []byte, fn func(key, value []byte) error) error {
+	r := bytes.NewReader(chunk)
+	for r.Len() > 0 {
+		var pair [2][]byte
+		for i := range pair {
+			n, err := binary.ReadUvarint(r)
+			if err != nil {
+				return err
+			}
+			if n > uint64(r.Len()) {
+				return io.ErrUnexpectedEOF
+			}
+			pair[i] = make([]byte, n)
+			if _, err := io.ReadFull(r, pair[i]); err != nil {
+				return err
+			}
+		}
+		if err := fn(pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotStore) dir(height uint64) string {
+	return filepath.Join(s.config.Dir, strconv.FormatUint(height, 10))
+}
+
+// create writes the state seen by the read-only transaction as the snapshot of the given height.
+// Chunks never split a pair, a pair larger than a chunk gets a chunk of its own. Only the state under the app hash
+// is written, the node restoring the snapshot builds its tree and commit info itself.
+func (s *snapshotStore) create(txn *badger.Txn, height uint64) error {
+	tmp := s.dir(height) + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return err
+	}
+
+	hashes := make([][]byte, 0)
+	chunk := make([]byte, 0, s.config.ChunkSize)
+	flush := func() error {
+		sum := sha256.Sum256(chunk)
+		hashes = append(hashes, sum[:])
+		err := os.WriteFile(filepath.Join(tmp, strconv.Itoa(len(hashes)-1)), chunk, 0o644)
+		chunk = chunk[:0]
+		return err
+	}
+
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		if isMetaKey(item.Key()) {
+			continue
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			it.Close()
+			return err
+		}
+		pair := appendPair(nil, item.Key(), value)
+		if len(chunk) > 0 && len(chunk)+len(pair) > s.config.ChunkSize {
+			if err := flush(); err != nil {
+				it.Close()
+				return err
+			}
+		}
+		chunk = append(chunk, pair...)
+	}
+	it.Close()
+	if err := flush(); err != nil {
+		return err
+	}
+
+	metadata, err := proto.Marshal(&types.SnapshotMetadata{ChunkHashes: hashes})
+	if err != nil {
+		return err
+	}
+	snapshot := &abcitypes.Snapshot{
+		Height:   height,
+		Format:   snapshotFormat,
+		Chunks:   uint32(len(hashes)),
+		Hash:     snapshotHash(hashes),
+		Metadata: metadata,
+	}
+	encoded, err := snapshot.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, snapshotMetadataFile), encoded, 0o644); err != nil {
+		return err
+	}
+
+	// The snapshot only shows up in ListSnapshots once it is complete
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := os.RemoveAll(s.dir(height)); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.dir(height)); err != nil {
+		return err
+	}
+	return s.prune()
+}
+
+// snapshotHash commits to every chunk of a snapshot
+func snapshotHash(chunkHashes [][]byte) []byte {
+	h := sha256.New()
+	for _, hash := range chunkHashes {
+		h.Write(hash)
+	}
+	return h.Sum(nil)
+}
+
+// heights returns the heights of the complete snapshots, most recent first
+func (s *snapshotStore) heights() ([]uint64, error) {
+	entries, err := os.ReadDir(s.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	heights := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		// Snapshots being written end with .tmp and don't parse
+		if height, err := strconv.ParseUint(entry.Name(), 10, 64); err == nil && entry.IsDir() {
+			heights = append(heights, height)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	return heights, nil
+}
+
+// prune removes the snapshots past the KeepRecent most recent ones, the lock must be held
+func (s *snapshotStore) prune() error {
+	if s.config.KeepRecent == 0 {
+		return nil
+	}
+	heights, err := s.heights()
+	if err != nil {
+		return err
+	}
+	for _, height := range heights[min(int(s.config.KeepRecent), len(heights)):] {
+		if err := os.RemoveAll(s.dir(height)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list returns the metadata of the complete snapshots
+func (s *snapshotStore) list() ([]*abcitypes.Snapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	heights, err := s.heights()
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*abcitypes.Snapshot, 0, len(heights))
+	for _, height := range heights {
+		encoded, err := os.ReadFile(filepath.Join(s.dir(height), snapshotMetadataFile))
+		if err != nil {
+			return nil, err
+		}
+		snapshot := &abcitypes.Snapshot{}
+		if err := snapshot.Unmarshal(encoded); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// loadChunk reads a chunk of a snapshot, returning nil if the snapshot or chunk does not exist
+func (s *snapshotStore) loadChunk(height uint64, chunk uint32) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.dir(height), strconv.FormatUint(uint64(chunk), 10)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// snapshotAfterCommit starts writing a snapshot of the state just committed, if the height calls for one.
+// The read-only transaction pins the committed state, so the following blocks don't have to wait for the snapshot.
+func (app *VerificationApp) snapshotAfterCommit(height int64) {
+	if app.snapshots == nil || height <= 0 || uint64(height)%app.snapshots.config.Interval != 0 {
+		return
+	}
+
+	s := app.snapshots
+	txn := app.db.NewTransactionAt(stateVersion(height), false)
+	s.lock.Lock()
+	s.pending[uint64(height)] = true
+	s.lock.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer txn.Discard()
+		if err := s.create(txn, uint64(height)); err != nil {
+			logger.Errorf("Failed to take snapshot at height %d: %v", height, err)
+		}
+		s.lock.Lock()
+		delete(s.pending, uint64(height))
+		s.lock.Unlock()
+	}()
+}
+
+// WaitSnapshots returns once the snapshots being written in the background are on disk.
+// The node must be stopped first, so no commit starts another one, and the database must still be open.
+func (app *VerificationApp) WaitSnapshots() {
+	if app.snapshots != nil {
+		app.snapshots.wg.Wait()
+	}
+}
+
+// oldestPending returns the height of the oldest snapshot being written, if any
+func (s *snapshotStore) oldestPending() (uint64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var oldest uint64
+	found := false
+	for height := range s.pending {
+		if !found || height < oldest {
+			oldest, found = height, true
+		}
+	}
+	return oldest, found
+}
+
+func (app *VerificationApp) ListSnapshots(_ context.Context, _ *abcitypes.RequestListSnapshots) (*abcitypes.ResponseListSnapshots, error) {
+	if app.snapshots == nil {
+		return &abcitypes.ResponseListSnapshots{}, nil
+	}
+	snapshots, err := app.snapshots.list()
+	if err != nil {
+		return nil, err
+	}
+	return &abcitypes.ResponseListSnapshots{Snapshots: snapshots}, nil
+}
+
+func (app *VerificationApp) LoadSnapshotChunk(_ context.Context, req *abcitypes.RequestLoadSnapshotChunk) (*abcitypes.ResponseLoadSnapshotChunk, error) {
+	if app.snapshots == nil || req.Format != snapshotFormat {
+		return &abcitypes.ResponseLoadSnapshotChunk{}, nil
+	}
+	chunk, err := app.snapshots.loadChunk(req.Height, req.Chunk)
+	if err != nil {
+		return nil, err
+	}
+	return &abcitypes.ResponseLoadSnapshotChunk{Chunk: chunk}, nil
+}
+
+// OfferSnapshot accepts a snapshot whose chunk hashes add up to its hash, and wipes the state it is about to replace.
+// The app hash comes from the light client, it is what the restored state is checked against.
+func (app *VerificationApp) OfferSnapshot(_ context.Context, req *abcitypes.RequestOfferSnapshot) (*abcitypes.ResponseOfferSnapshot, error) {
+	snapshot := req.Snapshot
+	if snapshot == nil {
+		return &abcitypes.ResponseOfferSnapshot{Result: abcitypes.ResponseOfferSnapshot_REJECT}, nil
+	}
+	if snapshot.Format != snapshotFormat {
+		return &abcitypes.ResponseOfferSnapshot{Result: abcitypes.ResponseOfferSnapshot_REJECT_FORMAT}, nil
+	}
+
+	metadata := &types.SnapshotMetadata{}
+	if err := proto.Unmarshal(snapshot.Metadata, metadata); err != nil ||
+		len(metadata.ChunkHashes) != int(snapshot.Chunks) || snapshot.Chunks == 0 ||
+		!bytes.Equal(snapshotHash(metadata.ChunkHashes), snapshot.Hash) {
+		logger.Warnf("Rejected snapshot at height %d: inconsistent metadata", snapshot.Height)
+		return &abcitypes.ResponseOfferSnapshot{Result: abcitypes.ResponseOfferSnapshot_REJECT}, nil
+	}
+
+	if err := app.db.DropAll(); err != nil {
+		return nil, err
+	}
+	app.restore = &restoreState{snapshot: snapshot, appHash: req.AppHash, chunkHashes: metadata.ChunkHashes}
+	return &abcitypes.ResponseOfferSnapshot{Result: abcitypes.ResponseOfferSnapshot_ACCEPT}, nil
+}
+
+// ApplySnapshotChunk writes the chunks in order, and checks the restored state against the app hash after the last one
+func (app *VerificationApp) ApplySnapshotChunk(_ context.Context, req *abcitypes.RequestApplySnapshotChunk) (*abcitypes.ResponseApplySnapshotChunk, error) {
+	restore := app.restore
+	if restore == nil {
+		return &abcitypes.ResponseApplySnapshotChunk{Result: abcitypes.ResponseApplySnapshotChunk_ABORT}, nil
+	}
+	if req.Index != restore.next {
+		return &abcitypes.ResponseApplySnapshotChunk{
+			Result:        abcitypes.ResponseApplySnapshotChunk_RETRY,
+			RefetchChunks: []uint32{restore.next},
+		}, nil
+	}
+
+	sum := sha256.Sum256(req.Chunk)
+	if !bytes.Equal(sum[:], restore.chunkHashes[req.Index]) {
+		logger.Warnf("Rejected snapshot chunk %d from %s: hash mismatch", req.Index, req.Sender)
+		return &abcitypes.ResponseApplySnapshotChunk{
+			Result:        abcitypes.ResponseApplySnapshotChunk_RETRY,
+			RefetchChunks: []uint32{req.Index},
+			RejectSenders: []string{req.Sender},
+		}, nil
+	}
+
+	batch := app.db.NewWriteBatchAt(stateVersion(int64(restore.snapshot.Height)))
+	err := readPairs(req.Chunk, func(key, value []byte) error {
+		// Nothing outside the app hash can be checked, it must not come from the snapshot
+		if isMetaKey(key) {
+			return fmt.Errorf("snapshot carries %q, which is not part of the app hash", key)
+		}
+		return batch.Set(key, value)
+	})
+	if err == nil {
+		err = batch.Flush()
+	} else {
+		batch.Cancel()
+	}
+	if err != nil {
+		// The chunk matched its hash, so the snapshot itself is broken
+		logger.Warnf("Rejected snapshot chunk %d: %v", req.Index, err)
+		return app.rejectRestore()
+	}
+
+	restore.next++
+	if restore.next < restore.snapshot.Chunks {
+		return &abcitypes.ResponseApplySnapshotChunk{Result: abcitypes.ResponseApplySnapshotChunk_ACCEPT}, nil
+	}
+
+	// Every chunk is in, the state must be the one the light client vouched for. That covers everything the
+	// state holds, the chain ID and the block hash seeding the next assignment included.
+	height := int64(restore.snapshot.Height)
+	computed, err := rebuildTree(app.db, height)
+	if err != nil {
+		log.Panicf("Error writing to database, unable to restore snapshot: %v", err)
+	}
+	if !bytes.Equal(computed, restore.appHash) {
+		logger.Warnf("Rejected snapshot at height %d: restored state does not match the app hash", restore.snapshot.Height)
+		return app.rejectRestore()
+	}
+	err = update(app.db, height, func(txn *badger.Txn) error {
+		return saveCommitInfo(txn, height, computed)
+	})
+	if err != nil {
+		log.Panicf("Error writing to database, unable to restore snapshot: %v", err)
+	}
+
+	if app.chainID, err = loadChainID(app.db); err != nil {
+		return nil, err
+	}
+	app.restore = nil
+	return &abcitypes.ResponseApplySnapshotChunk{Result: abcitypes.ResponseApplySnapshotChunk_ACCEPT}, nil
+}
+
+// rejectRestore drops a partially restored snapshot so that CometBFT can try another one
+func (app *VerificationApp) rejectRestore() (*abcitypes.ResponseApplySnapshotChunk, error) {
+	app.restore = nil
+	if err := app.db.DropAll(); err != nil {
+		return nil, err
+	}
+	return &abcitypes.ResponseApplySnapshotChunk{Result: abcitypes.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}, nil
+}
@@ -0,0 +1,324 @@
+package verificationApp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// powerReduction is the stake behind one unit of voting power, one token
+	powerReduction uint64 = 1_000_000
+	// unbondingBlocks is how long unbonded stake stays locked, so that misbehaviour can still be punished meanwhile
+	unbondingBlocks = 1000
+	// validatorUpdateDelay is how many blocks after the block that returned it a validator update reaches the
+	// validator set of the application. CometBFT applies updates from block H to the votes of H+2, and the
+	// attestation in a block carries the votes of the block before it, so they are checked against that set.
+	validatorUpdateDelay = 3
+)
+
+var (
+	stakePrefix           = []byte("stake/")  // Owner -> Stake
+	unbondingPrefix       = []byte("unbond/") // Release height, owner -> Unbonding
+	validatorUpdatePrefix = []byte("valupd/") // Height, owner -> Validator, written to the validator set at that height
+)
+
+func stakeKey(owner string) []byte {
+	return append(append([]byte{}, stakePrefix...), owner...)
+}
+
+// scheduledKey orders entries that are due at a height, one per owner
+func scheduledKey(prefix []byte, height int64, owner string) []byte {
+	return append(binary.BigEndian.AppendUint64(append([]byte{}, prefix...), uint64(height)), owner...)
+}
+
+// getStake returns the stake of the owner, or nil if the owner has none
+func getStake(txn *badger.Txn, owner string) (*types.Stake, error) {
+	stake := &types.Stake{}
+	found, err := getMessage(txn, stakeKey(owner), stake)
+	if !found {
+		return nil, err
+	}
+	return stake, err
+}
+
+// stakePower is the voting power a stake is worth
+func stakePower(bonded uint64) int64 {
+	return int64(bonded / powerReduction)
+}
+
+// genesisStake stores the validators of genesis.json as bonded with the stake their power is worth,
+// and records the total supply. It fails if the genesis supply together with that stake does not fit in a uint64.
+func genesisStake(txn *badger.Txn, updates []abcitypes.ValidatorUpdate, supply uint64) error {
+	for _, update := range updates {
+		pubKey := update.PubKey.GetEd25519()
+		if pubKey == nil {
+			return fmt.Errorf("unsupported validator key type %T", update.PubKey.Sum)
+		}
+		if update.Power <= 0 || uint64(update.Power) > (math.MaxUint64-supply)/powerReduction {
+			return fmt.Errorf("genesis validator power %d does not fit in the token supply", update.Power)
+		}
+		stake := &types.Stake{PubKey: pubKey, Bonded: uint64(update.Power) * powerReduction, Power: update.Power}
+		supply += stake.Bonded
+		if err := setMessage(txn, stakeKey(types.Ed25519Owner(pubKey)), stake); err != nil {
+			return err
+		}
+	}
+	return setCounter(txn, supplyKey, supply)
+}
+
+// handleStakingTransaction moves tokens between the balance and the stake of the owner.
+// Every check happens before the first write, so a rejected transaction leaves the state untouched.
+func (app *VerificationApp) handleStakingTransaction(txn *badger.Txn, owner string, tx *types.StakingTransactionData) (uint32, string) {
+	if (tx.Amount == 0) != (tx.Action == types.StakingAction_Unjail) {
+		return reject(CodeTypeInvalidAmount, "Rejected staking transaction: amount must be positive, and 0 to unjail")
+	}
+	account, err := getAccount(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	stake, err := getStake(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+
+	switch tx.Action {
+	case types.StakingAction_Join, types.StakingAction_IncreaseStake:
+		if tx.Action == types.StakingAction_Join {
+			// The key that signs the transaction is the one that will sign blocks
+			keyType, pubKey, err := types.ParseOwner(owner)
+			if err != nil || keyType != types.KeyTypeEd25519 {
+				return reject(CodeTypeInvalidOwner, "Rejected join: %s is not an ed25519 key", owner)
+			}
+			if stake != nil {
+				return reject(CodeTypeAlreadyStaked, "Rejected join: %s already has a stake", owner)
+			}
+			if tx.Amount < powerReduction {
+				return reject(CodeTypeInvalidAmount, "Rejected join: a stake of %d is worth no voting power", tx.Amount)
+			}
+			stake = &types.Stake{PubKey: pubKey}
+		} else if stake == nil {
+			return reject(CodeTypeNotStaked, "Rejected stake increase: %s has not joined", owner)
+		}
+		if account.Balance < tx.Amount {
+			return reject(CodeTypeInsufficientFunds, "Rejected staking transaction: %s has %d, needs %d", owner, account.Balance, tx.Amount)
+		}
+		account.Balance -= tx.Amount
+		stake.Bonded += tx.Amount
+
+	case types.StakingAction_Unbond:
+		if stake == nil {
+			return reject(CodeTypeNotStaked, "Rejected unbond: %s has not joined", owner)
+		}
+		if tx.Amount > stake.Bonded {
+			return reject(CodeTypeInsufficientFunds, "Rejected unbond: %s has %d bonded, not %d", owner, stake.Bonded, tx.Amount)
+		}
+		remaining := stake.Bonded - tx.Amount
+		if remaining != 0 && stakePower(remaining) == 0 {
+			return reject(CodeTypeInvalidAmount, "Rejected unbond: the remaining stake would be worth no voting power, unbond all of it")
+		}
+		if remaining == 0 {
+			others, err := stakedPower(txn, owner)
+			if err != nil {
+				log.Panicf("Error reading database, unable to execute tx: %v", err)
+			}
+			if others == 0 {
+				return reject(CodeTypeLastValidator, "Rejected unbond: %s is the last validator", owner)
+			}
+		}
+		if err := scheduleUnbonding(txn, owner, tx.Amount, app.height+unbondingBlocks); err != nil {
+			log.Panicf("Error writing to database, unable to execute tx: %v", err)
+		}
+		stake.Bonded = remaining
+
+	case types.StakingAction_Unjail:
+		if stake == nil || !stake.Jailed {
+			return reject(CodeTypeNotJailed, "Rejected unjail: %s is not jailed", owner)
+		}
+		if app.height < stake.JailedUntil {
+			return reject(CodeTypeStillJailed, "Rejected unjail: %s is jailed until %d", owner, stake.JailedUntil)
+		}
+		// Slashing may have left too little stake for any power
+		if stakePower(stake.Bonded) == 0 {
+			return reject(CodeTypeInvalidAmount, "Rejected unjail: %s has too little stake left, increase it first", owner)
+		}
+		stake.Jailed = false
+
+	default:
+		return reject(CodeTypeInvalidTxType, "Rejected staking transaction: unknown action")
+	}
+
+	if err := setAccount(txn, owner, account); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	if err := setMessage(txn, stakeKey(owner), stake); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	app.stakeChanges[owner] = true
+	return CodeTypeOK, ""
+}
+
+// stakedPower returns the voting power of every stake but the one of the given owner, jailed validators have none
+func stakedPower(txn *badger.Txn, except string) (int64, error) {
+	var total int64
+	err := iteratePrefix(txn, stakePrefix, func(item *badger.Item) error {
+		if string(item.Key()[len(stakePrefix):]) == except {
+			return nil
+		}
+		stake := &types.Stake{}
+		if err := item.Value(func(val []byte) error { return proto.Unmarshal(val, stake) }); err != nil {
+			return err
+		}
+		if !stake.Jailed {
+			total += stakePower(stake.Bonded)
+		}
+		return nil
+	})
+	return total, err
+}
+
+// scheduleUnbonding locks the amount until the release height, adding to what the owner already unbonds then
+func scheduleUnbonding(txn *badger.Txn, owner string, amount uint64, release int64) error {
+	key := scheduledKey(unbondingPrefix, release, owner)
+	unbonding := &types.Unbonding{Owner: owner}
+	if _, err := getMessage(txn, key, unbonding); err != nil {
+		return err
+	}
+	unbonding.Amount += amount
+	return setMessage(txn, key, unbonding)
+}
+
+// dueEntries returns the keys and values of the entries scheduled at or before the height
+func dueEntries(txn *badger.Txn, prefix []byte, height int64) (keys, values [][]byte, err error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	end := scheduledKey(prefix, height+1, "")
+	for it.Rewind(); it.Valid() && bytes.Compare(it.Item().Key(), end) < 0; it.Next() {
+		value, err := it.Item().ValueCopy(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys, values = append(keys, it.Item().KeyCopy(nil)), append(values, value)
+	}
+	return keys, values, nil
+}
+
+// applyDueStakingChanges runs at the start of a block: validator updates reach the validator set of the
+// application and the stake whose unbonding period is over returns to the balance of its owner.
+func applyDueStakingChanges(txn *badger.Txn, height int64) error {
+	keys, values, err := dueEntries(txn, validatorUpdatePrefix, height)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		validator := &types.Validator{}
+		if err := proto.Unmarshal(values[i], validator); err != nil {
+			return err
+		}
+		if err := setValidator(txn, validator); err != nil {
+			return err
+		}
+		if err := deleteKey(txn, key); err != nil {
+			return err
+		}
+	}
+
+	keys, values, err = dueEntries(txn, unbondingPrefix, height)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		unbonding := &types.Unbonding{}
+		if err := proto.Unmarshal(values[i], unbonding); err != nil {
+			return err
+		}
+		// The supply bounds every balance, so this can't overflow
+		account, err := getAccount(txn, unbonding.Owner)
+		if err != nil {
+			return err
+		}
+		account.Balance += unbonding.Amount
+		if err := setAccount(txn, unbonding.Owner, account); err != nil {
+			return err
+		}
+		if err := deleteKey(txn, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatorUpdates returns the power changes caused by the staking transactions of the block, in owner order,
+// and schedules them for the validator set of the application.
+func (app *VerificationApp) validatorUpdates(txn *badger.Txn) ([]abcitypes.ValidatorUpdate, error) {
+	owners := make([]string, 0, len(app.stakeChanges))
+	for owner := range app.stakeChanges {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	updates := make([]abcitypes.ValidatorUpdate, 0, len(owners))
+	for _, owner := range owners {
+		stake, err := getStake(txn, owner)
+		if err != nil {
+			return nil, err
+		}
+		// Joining and leaving within a block, or a change below one unit of power, is nothing CometBFT needs to know
+		power := stakePower(stake.Bonded)
+		if stake.Jailed {
+			power = 0
+		}
+		if power != stake.Power {
+			updates = append(updates, abcitypes.Ed25519ValidatorUpdate(stake.PubKey, power))
+			validator := &types.Validator{Owner: owner, PubKey: stake.PubKey, Power: power}
+			if err := setMessage(txn, scheduledKey(validatorUpdatePrefix, app.height+validatorUpdateDelay, owner), validator); err != nil {
+				return nil, err
+			}
+			stake.Power = power
+		}
+
+		// A jailed validator keeps its record, leaving and joining again must not get it out of jail
+		if stake.Bonded == 0 && !stake.Jailed {
+			err = deleteKey(txn, stakeKey(owner))
+		} else {
+			err = setMessage(txn, stakeKey(owner), stake)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return updates, nil
+}
+
+// IsValidator tells whether the consensus address belongs to a validator with voting power in the committed state
+func (app *VerificationApp) IsValidator(address []byte) (bool, error) {
+	validator := false
+	err := view(app.db, func(txn *badger.Txn) error {
+		owners, err := stakesByAddress(txn)
+		if err != nil {
+			return err
+		}
+		owner, ok := owners[string(address)]
+		if !ok {
+			return nil
+		}
+		stake, err := getStake(txn, owner)
+		if err != nil {
+			return err
+		}
+		validator = !stake.Jailed && stakePower(stake.Bonded) > 0
+		return nil
+	})
+	return validator, err
+}
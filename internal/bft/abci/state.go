@@ -0,0 +1,215 @@
+package verificationApp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	"github.com/dgraph-io/badger/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// AppVersion is the version of the application protocol, reported to CometBFT in Info.
+// Bump it whenever a change alters how blocks are executed.
+const AppVersion uint64 = 6
+
+// latestVersion reads the latest committed state, see stateVersion
+const latestVersion = math.MaxUint64
+
+var (
+	// Keys under this prefix describe the state rather than being part of it, so they are left out of the app hash.
+	metaPrefix    = []byte("meta/")
+	lastHeightKey = []byte("meta/lastHeight")
+	appHashKey    = []byte("meta/appHash")
+)
+
+// stateVersion is the Badger version the state after the block at the given height is committed at.
+// The database runs in managed mode, so every past version can be read until it is discarded.
+// Badger reserves version 0, which would otherwise be the version of a genesis state at height 0.
+func stateVersion(height int64) uint64 {
+	return uint64(height) + 1
+}
+
+// view runs fn on the latest committed state
+func view(db *badger.DB, fn func(txn *badger.Txn) error) error {
+	return viewAt(db, latestVersion, fn)
+}
+
+// viewAt runs fn on the state committed at or before the given version
+func viewAt(db *badger.DB, version uint64, fn func(txn *badger.Txn) error) error {
+	txn := db.NewTransactionAt(version, false)
+	defer txn.Discard()
+	return fn(txn)
+}
+
+// update runs fn in a read-write transaction, committed at the version of the given height if fn succeeds
+func update(db *badger.DB, height int64, fn func(txn *badger.Txn) error) error {
+	txn := db.NewTransactionAt(latestVersion, true)
+	defer txn.Discard()
+	if err := fn(txn); err != nil {
+		return err
+	}
+	return txn.CommitAt(stateVersion(height), nil)
+}
+
+// isMetaKey reports whether the key is bookkeeping data excluded from the app hash
+func isMetaKey(key []byte) bool {
+	return bytes.HasPrefix(key, metaPrefix)
+}
+
+// stateLeaf encodes a key-value pair as a Merkle leaf.
+// Both parts are length-prefixed so that different pairs never produce the same leaf, and the value is hashed
+// the way merkle.ValueOp does it.
+func stateLeaf(key, value []byte) []byte {
+	valueHash := tmhash.Sum(value)
+	leaf := make([]byte, 0, 2*binary.MaxVarintLen64+len(key)+len(valueHash))
+	leaf = binary.AppendUvarint(leaf, uint64(len(key)))
+	leaf = append(leaf, key...)
+	leaf = binary.AppendUvarint(leaf, uint64(len(valueHash)))
+	leaf = append(leaf, valueHash...)
+	return leaf
+}
+
+// computeAppHash brings the tree over the state visible to the transaction up to date and returns its root.
+// Only the paths of the keys written since the last call are rehashed, see tree.go.
+func computeAppHash(txn *badger.Txn) ([]byte, error) {
+	return updateTree(txn)
+}
+
+// stateProof returns the proof that the key is part of the app hash of the state visible to the transaction,
+// or nil if the key does not exist. The proof is checked with ProofRuntime against the app hash.
+func stateProof(txn *badger.Txn, key []byte) (*cmtcrypto.ProofOps, error) {
+	if isMetaKey(key) {
+		return nil, nil
+	}
+	// Only the nodes on the path of the key and their siblings are read
+	path := keyPath(key)
+	siblings := make([][]byte, 0)
+	for depth := 0; ; depth++ {
+		if depth == maxTreeDepth {
+			return nil, fmt.Errorf("tree has no leaf on the path of %q", key)
+		}
+		node, err := getNode(txn, path, depth)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil || node.key != nil && !bytes.Equal(node.key, key) {
+			return nil, nil
+		}
+		if node.key != nil {
+			break
+		}
+		sibling, err := getNode(txn, childPath(path, depth, 1-pathBit(path, depth)), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		siblings = append(siblings, nodeHash(sibling))
+	}
+
+	op := treeValueOp{key: key, siblings: siblings}.ProofOp()
+	return &cmtcrypto.ProofOps{Ops: []cmtcrypto.ProofOp{op}}, nil
+}
+
+// saveCommitInfo records the height and app hash of the block being finalised
+func saveCommitInfo(txn *badger.Txn, height int64, appHash []byte) error {
+	h := make([]byte, 8)
+	binary.BigEndian.PutUint64(h, uint64(height))
+	if err := txn.Set(lastHeightKey, h); err != nil {
+		return err
+	}
+	return txn.Set(appHashKey, appHash)
+}
+
+// readLastHeight returns the height of the last block finalised in the state visible to the transaction, 0 before the first block
+func readLastHeight(txn *badger.Txn) (int64, error) {
+	item, err := txn.Get(lastHeightKey)
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var height int64
+	err = item.Value(func(val []byte) error {
+		height = int64(binary.BigEndian.Uint64(val))
+		return nil
+	})
+	return height, err
+}
+
+// loadCommitInfo returns the height and app hash of the last committed block, or zero values for a fresh state
+func loadCommitInfo(db *badger.DB) (height int64, appHash []byte, err error) {
+	err = view(db, func(txn *badger.Txn) error {
+		height, err = readLastHeight(txn)
+		if err != nil || height == 0 {
+			return err
+		}
+
+		item, err := txn.Get(appHashKey)
+		if err != nil {
+			return err
+		}
+		appHash, err = item.ValueCopy(nil)
+		return err
+	})
+	return height, appHash, err
+}
+
+// getMessage reads a protobuf message, returning false if the key does not exist
+func getMessage(txn *badger.Txn, key []byte, msg proto.Message) (bool, error) {
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, item.Value(func(val []byte) error {
+		return proto.Unmarshal(val, msg)
+	})
+}
+
+// setMessage writes the deterministic encoding of a protobuf message
+func setMessage(txn *badger.Txn, key []byte, msg proto.Message) error {
+	val, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return setKey(txn, key, val)
+}
+
+// iteratePrefix calls fn on every item with the given prefix, in key order.
+// Badger allows a single iterator at a time in a read-write transaction, so fn must not iterate itself.
+func iteratePrefix(txn *badger.Txn, prefix []byte, fn func(item *badger.Item) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		if err := fn(it.Item()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropPrefix deletes every key with the given prefix
+func dropPrefix(txn *badger.Txn, prefix []byte) error {
+	keys := make([][]byte, 0)
+	err := iteratePrefix(txn, prefix, func(item *badger.Item) error {
+		keys = append(keys, item.KeyCopy(nil))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := deleteKey(txn, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
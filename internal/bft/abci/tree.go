@@ -0,0 +1,478 @@
+package verificationApp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// The app hash is the root of a sparse Merkle tree over the state, persisted along with it so that a block only
+// rehashes the paths of the keys it wrote. A key sits on the path given by the bits of its SHA-256 hash, and a
+// subtree holding a single key is replaced by the leaf of that key, so a path is only as deep as it takes to tell
+// the keys apart. Writes to the state go through setKey and deleteKey, which mark the key for the next computeAppHash.
+
+var (
+	treePrefix  = []byte("meta/tree/")  // Depth, path -> node of the tree
+	dirtyPrefix = []byte("meta/dirty/") // Key -> nothing, for every key written since the last app hash
+)
+
+const (
+	// ProofOpTreeValue is the type of the proofs Query returns, see ProofRuntime
+	ProofOpTreeValue = "openmesh:tree"
+
+	// maxTreeDepth is the length of a path, two keys sharing all of it would share their hash
+	maxTreeDepth = 8 * sha256.Size
+
+	leafNode  byte = 0
+	innerNode byte = 1
+)
+
+// emptyHash is the hash of a subtree without keys, and so the app hash of an empty state
+var emptyHash = make([]byte, tmhash.Size)
+
+// treeNode is a node of the tree, a leaf if key is set
+type treeNode struct {
+	hash []byte
+	key  []byte
+}
+
+// treeLeaf is a key of the state along with where and how it sits in the tree
+type treeLeaf struct {
+	path []byte
+	key  []byte
+	hash []byte
+}
+
+// setKey writes a key of the state
+func setKey(txn *badger.Txn, key, value []byte) error {
+	if err := markDirty(txn, key); err != nil {
+		return err
+	}
+	return txn.Set(key, value)
+}
+
+// deleteKey removes a key of the state
+func deleteKey(txn *badger.Txn, key []byte) error {
+	if err := markDirty(txn, key); err != nil {
+		return err
+	}
+	return txn.Delete(key)
+}
+
+// markDirty records that the key changed, meta keys are not part of the tree
+func markDirty(txn *badger.Txn, key []byte) error {
+	if isMetaKey(key) {
+		return nil
+	}
+	return txn.Set(append(append([]byte{}, dirtyPrefix...), key...), nil)
+}
+
+// isTreeKey reports whether the key belongs to the tree rather than the state it is built over
+func isTreeKey(key []byte) bool {
+	return bytes.HasPrefix(key, treePrefix) || bytes.HasPrefix(key, dirtyPrefix)
+}
+
+// keyPath returns the path of a key in the tree
+func keyPath(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:]
+}
+
+// pathBit returns the bit of the path that picks the child of the node at the given depth
+func pathBit(path []byte, depth int) byte {
+	return (path[depth/8] >> (7 - depth%8)) & 1
+}
+
+// childPath returns the path through the given child of the node at the given depth of a path
+func childPath(path []byte, depth int, bit byte) []byte {
+	child := append([]byte{}, path...)
+	child[depth/8] = child[depth/8]&^(1<<(7-depth%8)) | bit<<(7-depth%8)
+	return child
+}
+
+// nodeKey returns where the node at the given depth of a path is stored, only the bits above it tell nodes apart
+func nodeKey(path []byte, depth int) []byte {
+	key := binary.BigEndian.AppendUint16(append([]byte{}, treePrefix...), uint16(depth))
+	key = append(key, path[:(depth+7)/8]...)
+	if depth%8 != 0 {
+		key[len(key)-1] &^= 0xff >> (depth % 8)
+	}
+	return key
+}
+
+// leafHash is the hash of a key-value pair, the leaf hash merkle.ValueOp uses as well
+func leafHash(key, value []byte) []byte {
+	return tmhash.Sum(append([]byte{leafNode}, stateLeaf(key, value)...))
+}
+
+func innerHash(left, right []byte) []byte {
+	data := make([]byte, 0, 1+len(left)+len(right))
+	data = append(append(append(data, innerNode), left...), right...)
+	return tmhash.Sum(data)
+}
+
+// getNode reads the node at the given depth of a path, or nil if its subtree is empty
+func getNode(txn *badger.Txn, path []byte, depth int) (*treeNode, error) {
+	item, err := txn.Get(nodeKey(path, depth))
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	val, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(val) < 1+tmhash.Size {
+		return nil, fmt.Errorf("tree node at depth %d is corrupted", depth)
+	}
+	node := &treeNode{hash: val[1 : 1+tmhash.Size]}
+	if val[0] == leafNode {
+		node.key = val[1+tmhash.Size:]
+	}
+	return node, nil
+}
+
+func encodeNode(node *treeNode) []byte {
+	if node.key == nil {
+		return append([]byte{innerNode}, node.hash...)
+	}
+	return append(append([]byte{leafNode}, node.hash...), node.key...)
+}
+
+func setNode(txn *badger.Txn, path []byte, depth int, node *treeNode) error {
+	return txn.Set(nodeKey(path, depth), encodeNode(node))
+}
+
+// nodeHash returns the hash of a subtree read by getNode
+func nodeHash(node *treeNode) []byte {
+	if node == nil {
+		return emptyHash
+	}
+	return node.hash
+}
+
+// treeInsert puts the leaf of a key on its path. The inner nodes it goes through or adds keep their old hash,
+// rehashTree fixes them.
+func treeInsert(txn *badger.Txn, key, hash []byte) error {
+	path := keyPath(key)
+	for depth := 0; depth < maxTreeDepth; depth++ {
+		node, err := getNode(txn, path, depth)
+		if err != nil {
+			return err
+		}
+		if node == nil || bytes.Equal(node.key, key) {
+			return setNode(txn, path, depth, &treeNode{hash: hash, key: key})
+		}
+		if node.key == nil {
+			continue
+		}
+
+		// The subtree holds another key, both leaves go down to where their paths part
+		other := keyPath(node.key)
+		split := depth
+		for pathBit(path, split) == pathBit(other, split) {
+			split++
+		}
+		for d := depth; d <= split; d++ {
+			if err := setNode(txn, path, d, &treeNode{hash: emptyHash}); err != nil {
+				return err
+			}
+		}
+		if err := setNode(txn, other, split+1, node); err != nil {
+			return err
+		}
+		return setNode(txn, path, split+1, &treeNode{hash: hash, key: key})
+	}
+	return errors.New("tree path is exhausted")
+}
+
+// treeRemove takes the leaf of a key off its path. An inner node left with a single leaf below it is replaced
+// by that leaf, the inner nodes that remain keep their old hash until rehashTree.
+func treeRemove(txn *badger.Txn, key []byte) error {
+	path := keyPath(key)
+	depth := 0
+	for ; depth < maxTreeDepth; depth++ {
+		node, err := getNode(txn, path, depth)
+		if err != nil || node == nil {
+			return err
+		}
+		if node.key != nil {
+			if !bytes.Equal(node.key, key) {
+				return nil
+			}
+			break
+		}
+	}
+	if depth == maxTreeDepth {
+		return nil
+	}
+	if err := txn.Delete(nodeKey(path, depth)); err != nil {
+		return err
+	}
+
+	for depth--; depth >= 0; depth-- {
+		var only *treeNode
+		var onlyPath []byte
+		for bit := byte(0); bit < 2; bit++ {
+			child := childPath(path, depth, bit)
+			node, err := getNode(txn, child, depth+1)
+			if err != nil {
+				return err
+			}
+			if node != nil {
+				if only != nil {
+					// Both children hold keys
+					return nil
+				}
+				only, onlyPath = node, child
+			}
+		}
+		if only != nil && only.key == nil {
+			// An inner node below holds two keys or more
+			return nil
+		}
+		if only == nil {
+			if err := txn.Delete(nodeKey(path, depth)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := txn.Delete(nodeKey(onlyPath, depth+1)); err != nil {
+			return err
+		}
+		if err := setNode(txn, path, depth, only); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rehashTree recomputes the inner nodes on the paths of the given keys, deepest first
+func rehashTree(txn *badger.Txn, keys [][]byte) error {
+	type position struct {
+		path  []byte
+		depth int
+	}
+	positions := make(map[string]position)
+	for _, key := range keys {
+		path := keyPath(key)
+		for depth := 0; depth < maxTreeDepth; depth++ {
+			node, err := getNode(txn, path, depth)
+			if err != nil {
+				return err
+			}
+			if node == nil || node.key != nil {
+				break
+			}
+			positions[string(nodeKey(path, depth))] = position{path: path, depth: depth}
+		}
+	}
+
+	inner := make([]position, 0, len(positions))
+	for _, pos := range positions {
+		inner = append(inner, pos)
+	}
+	sort.Slice(inner, func(i, j int) bool { return inner[i].depth > inner[j].depth })
+	for _, pos := range inner {
+		var children [2][]byte
+		for bit := byte(0); bit < 2; bit++ {
+			node, err := getNode(txn, childPath(pos.path, pos.depth, bit), pos.depth+1)
+			if err != nil {
+				return err
+			}
+			children[bit] = nodeHash(node)
+		}
+		if err := setNode(txn, pos.path, pos.depth, &treeNode{hash: innerHash(children[0], children[1])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateTree brings the tree up to date with the keys written since the last call and returns its root
+func updateTree(txn *badger.Txn) ([]byte, error) {
+	markers := make([][]byte, 0)
+	err := iteratePrefix(txn, dirtyPrefix, func(item *badger.Item) error {
+		markers = append(markers, item.KeyCopy(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([][]byte, 0, len(markers))
+	for _, marker := range markers {
+		key := marker[len(dirtyPrefix):]
+		item, err := txn.Get(key)
+		switch {
+		case err == badger.ErrKeyNotFound:
+			err = treeRemove(txn, key)
+		case err == nil:
+			var value []byte
+			if value, err = item.ValueCopy(nil); err == nil {
+				err = treeInsert(txn, key, leafHash(key, value))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := txn.Delete(marker); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rehashTree(txn, keys); err != nil {
+		return nil, err
+	}
+
+	root, err := getNode(txn, nil, 0)
+	return nodeHash(root), err
+}
+
+// buildTree returns the root of the tree holding the leaves, sorted by path, below the given depth.
+// If emit is not nil, it gets every node of the tree.
+func buildTree(leaves []treeLeaf, depth int, emit func(path []byte, depth int, node *treeNode) error) ([]byte, error) {
+	switch len(leaves) {
+	case 0:
+		return emptyHash, nil
+	case 1:
+		if emit != nil {
+			if err := emit(leaves[0].path, depth, &treeNode{hash: leaves[0].hash, key: leaves[0].key}); err != nil {
+				return nil, err
+			}
+		}
+		return leaves[0].hash, nil
+	}
+
+	split := sort.Search(len(leaves), func(i int) bool { return pathBit(leaves[i].path, depth) == 1 })
+	left, err := buildTree(leaves[:split], depth+1, emit)
+	if err != nil {
+		return nil, err
+	}
+	right, err := buildTree(leaves[split:], depth+1, emit)
+	if err != nil {
+		return nil, err
+	}
+	hash := innerHash(left, right)
+	if emit != nil {
+		if err := emit(leaves[0].path, depth, &treeNode{hash: hash}); err != nil {
+			return nil, err
+		}
+	}
+	return hash, nil
+}
+
+// treeLeaves returns the leaves of every non-meta key visible to the transaction, sorted by path
+func treeLeaves(txn *badger.Txn) ([]treeLeaf, error) {
+	leaves := make([]treeLeaf, 0)
+
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		if isMetaKey(item.Key()) {
+			continue
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, err
+		}
+		key := item.KeyCopy(nil)
+		leaves = append(leaves, treeLeaf{path: keyPath(key), key: key, hash: leafHash(key, value)})
+	}
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i].path, leaves[j].path) < 0 })
+	return leaves, nil
+}
+
+// rebuildTree replaces the tree of the state committed at the given height by one built from that state,
+// and returns its root. It reads the whole state, it is meant for a state that was not built block by block.
+func rebuildTree(db *badger.DB, height int64) ([]byte, error) {
+	var leaves []treeLeaf
+	err := viewAt(db, stateVersion(height), func(txn *badger.Txn) (err error) {
+		leaves, err = treeLeaves(txn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DropPrefix(treePrefix); err != nil {
+		return nil, err
+	}
+
+	batch := db.NewWriteBatchAt(stateVersion(height))
+	root, err := buildTree(leaves, 0, func(path []byte, depth int, node *treeNode) error {
+		return batch.Set(nodeKey(path, depth), encodeNode(node))
+	})
+	if err != nil {
+		batch.Cancel()
+		return nil, err
+	}
+	return root, batch.Flush()
+}
+
+// treeValueOp proves the value of a key against the root of the tree, see ProofRuntime
+type treeValueOp struct {
+	key      []byte
+	siblings [][]byte // Hashes of the siblings of the nodes on the path of the key, from the root down
+}
+
+var _ merkle.ProofOperator = treeValueOp{}
+
+// Run returns the root of the tree if the key has the given value
+func (op treeValueOp) Run(args [][]byte) ([][]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 arg, got %d", len(args))
+	}
+	path := keyPath(op.key)
+	hash := leafHash(op.key, args[0])
+	for depth := len(op.siblings) - 1; depth >= 0; depth-- {
+		if pathBit(path, depth) == 0 {
+			hash = innerHash(hash, op.siblings[depth])
+		} else {
+			hash = innerHash(op.siblings[depth], hash)
+		}
+	}
+	return [][]byte{hash}, nil
+}
+
+func (op treeValueOp) GetKey() []byte {
+	return op.key
+}
+
+func (op treeValueOp) ProofOp() cmtcrypto.ProofOp {
+	data := make([]byte, 0, len(op.siblings)*tmhash.Size)
+	for _, sibling := range op.siblings {
+		data = append(data, sibling...)
+	}
+	return cmtcrypto.ProofOp{Type: ProofOpTreeValue, Key: op.key, Data: data}
+}
+
+func decodeTreeValueOp(op cmtcrypto.ProofOp) (merkle.ProofOperator, error) {
+	if op.Type != ProofOpTreeValue {
+		return nil, fmt.Errorf("unexpected proof op type %s", op.Type)
+	}
+	if len(op.Data)%tmhash.Size != 0 || len(op.Data) > maxTreeDepth*tmhash.Size {
+		return nil, fmt.Errorf("proof op of %d bytes", len(op.Data))
+	}
+	siblings := make([][]byte, 0, len(op.Data)/tmhash.Size)
+	for i := 0; i < len(op.Data); i += tmhash.Size {
+		siblings = append(siblings, op.Data[i:i+tmhash.Size])
+	}
+	return treeValueOp{key: op.Key, siblings: siblings}, nil
+}
+
+// ProofRuntime checks the proofs of Query against the app hash, it knows CometBFT's proof ops as well
+func ProofRuntime() *merkle.ProofRuntime {
+	prt := merkle.DefaultProofRuntime()
+	prt.RegisterOpDecoder(ProofOpTreeValue, decodeTreeValueOp)
+	return prt
+}
@@ -0,0 +1,191 @@
+package verificationApp
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/ipfs/go-cid"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// upgradeNoticeBlocks is how far ahead of its approval an activation height must be, every node downloads
+// the binary in the meantime
+const upgradeNoticeBlocks = 100
+
+var (
+	// Upgrade the nodes switch to at its activation height, the last one scheduled
+	upgradePlanKey = []byte("upgrade/plan")
+
+	updateKeyPrefix       = []byte("updkey/")      // Owner -> nothing, for every update key of genesis.json
+	upgradeApprovalPrefix = []byte("updapproval/") // Owner -> UpgradeTransactionData, the latest approval of an update key
+)
+
+func updateKeyKey(owner string) []byte {
+	return append(append([]byte{}, updateKeyPrefix...), owner...)
+}
+
+func upgradeApprovalKey(owner string) []byte {
+	return append(append([]byte{}, upgradeApprovalPrefix...), owner...)
+}
+
+// listUpdateKeys returns the owners allowed to approve upgrades, in owner order
+func listUpdateKeys(txn *badger.Txn) ([]string, error) {
+	owners := make([]string, 0)
+	err := iteratePrefix(txn, updateKeyPrefix, func(item *badger.Item) error {
+		owners = append(owners, string(item.Key()[len(updateKeyPrefix):]))
+		return nil
+	})
+	return owners, err
+}
+
+// getUpgradePlan returns the last scheduled upgrade, nil if there was none
+func getUpgradePlan(txn *badger.Txn) (*types.UpgradePlan, error) {
+	plan := &types.UpgradePlan{}
+	found, err := getMessage(txn, upgradePlanKey, plan)
+	if !found {
+		return nil, err
+	}
+	return plan, err
+}
+
+// UpgradePlan returns the last upgrade scheduled in the committed state, nil if there was none,
+// along with the height of that state. It is the updater's source of truth.
+func (app *VerificationApp) UpgradePlan() (plan *types.UpgradePlan, height int64, err error) {
+	err = view(app.db, func(txn *badger.Txn) error {
+		if height, err = readLastHeight(txn); err != nil {
+			return err
+		}
+		plan, err = getUpgradePlan(txn)
+		return err
+	})
+	return plan, height, err
+}
+
+// UpgradeHandler is called once the block before the activation height of an upgrade to another binary is
+// committed, and again after restarts past that height. Consensus must stop, the node can only continue with
+// the binary of the plan. It must not block.
+type UpgradeHandler func(plan *types.UpgradePlan)
+
+// SetVersion sets the CID of the binary this node runs, empty for a binary that no upgrade installed, and what to
+// call when the node must stop for an upgrade. The handler is called right away if the state is past that point.
+func (app *VerificationApp) SetVersion(binaryCid string, handler UpgradeHandler) error {
+	app.version = binaryCid
+	app.upgradeHandler = handler
+	return app.checkUpgrade()
+}
+
+// checkUpgrade calls the upgrade handler if the committed state is at or past the height before the activation
+// height of an upgrade to another binary
+func (app *VerificationApp) checkUpgrade() error {
+	if app.upgradeHandler == nil {
+		return nil
+	}
+	plan, height, err := app.UpgradePlan()
+	if err != nil {
+		return err
+	}
+	if plan != nil && plan.BinaryCid != app.version && height >= plan.ActivationHeight-1 {
+		app.upgradeHandler(plan)
+	}
+	return nil
+}
+
+// checkVersion refuses to execute a block from the activation height of an upgrade to another binary on,
+// so nodes that did not stop in time can't execute it with the wrong version
+func (app *VerificationApp) checkVersion(height int64) error {
+	var plan *types.UpgradePlan
+	err := view(app.db, func(txn *badger.Txn) (err error) {
+		plan, err = getUpgradePlan(txn)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if plan != nil && plan.BinaryCid != app.version && height >= plan.ActivationHeight {
+		return fmt.Errorf("height %d must be executed by the binary of the upgrade to %s", height, plan.BinaryCid)
+	}
+	return nil
+}
+
+// handleUpgradeTransaction records the approval of an update key, replacing its earlier one. The binary is
+// scheduled once the share of the update keys set by the updateThreshold parameter approve the same binary and
+// activation height, replacing any plan scheduled before.
+func (app *VerificationApp) handleUpgradeTransaction(txn *badger.Txn, owner string, tx *types.UpgradeTransactionData) (uint32, string) {
+	if _, err := txn.Get(updateKeyKey(owner)); err == badger.ErrKeyNotFound {
+		return reject(CodeTypeNotUpdateKey, "Rejected upgrade: %s is not an update key", owner)
+	} else if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	// Only accept the canonical string of a CID, so approvals of the same binary are counted together
+	if c, err := cid.Decode(tx.BinaryCid); err != nil || c.String() != tx.BinaryCid {
+		return reject(CodeTypeInvalidCid, "Rejected upgrade: invalid CID %q", tx.BinaryCid)
+	}
+	if tx.ActivationHeight < app.height+upgradeNoticeBlocks {
+		return reject(CodeTypeInvalidUpgrade, "Rejected upgrade: activation at %d is less than %d blocks after %d", tx.ActivationHeight, upgradeNoticeBlocks, app.height)
+	}
+
+	if err := setMessage(txn, upgradeApprovalKey(owner), tx); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	app.emit(EventUpgradeApproved, "approver", owner, "binary_cid", tx.BinaryCid,
+		"activation_height", strconv.FormatInt(tx.ActivationHeight, 10))
+
+	if err := app.scheduleUpgrade(txn, tx); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	return CodeTypeOK, ""
+}
+
+// scheduleUpgrade makes the approved binary and activation height the upgrade plan if enough update keys
+// approve them. Thresholds are more than half, so two plans can't both reach theirs. Approvals give the full notice,
+// so a plan replacing one within its notice window activates after it and the nodes getting ready have time to switch.
+func (app *VerificationApp) scheduleUpgrade(txn *badger.Txn, approved *types.UpgradeTransactionData) error {
+	owners, err := listUpdateKeys(txn)
+	if err != nil {
+		return err
+	}
+	approvers := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		approval := &types.UpgradeTransactionData{}
+		found, err := getMessage(txn, upgradeApprovalKey(owner), approval)
+		if err != nil {
+			return err
+		}
+		if found && proto.Equal(approval, approved) {
+			approvers = append(approvers, owner)
+		}
+	}
+
+	params, err := getGovernanceParams(txn)
+	if err != nil {
+		return err
+	}
+	// Same rule as the updater used: approvers / update keys >= numerator / denominator
+	threshold := params.UpdateThreshold
+	if uint64(len(approvers))*threshold.Denominator < uint64(len(owners))*threshold.Numerator {
+		return nil
+	}
+
+	previous, err := getUpgradePlan(txn)
+	if err != nil {
+		return err
+	}
+	plan := &types.UpgradePlan{
+		BinaryCid:        approved.BinaryCid,
+		ActivationHeight: approved.ActivationHeight,
+		ScheduledHeight:  app.height,
+		Approvers:        approvers,
+	}
+	if previous.GetBinaryCid() == plan.BinaryCid && previous.GetActivationHeight() == plan.ActivationHeight {
+		// A late approval of the plan, it stays scheduled as it was
+		plan.ScheduledHeight = previous.ScheduledHeight
+	} else {
+		logger.Infof("Upgrade to %s scheduled at height %d", plan.BinaryCid, plan.ActivationHeight)
+		app.emit(EventUpgradeScheduled, "binary_cid", plan.BinaryCid, "activation_height", strconv.FormatInt(plan.ActivationHeight, 10))
+	}
+	return setMessage(txn, upgradePlanKey, plan)
+}
@@ -0,0 +1,84 @@
+package verificationApp
+
+import (
+	"fmt"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Validators are stored under this prefix, keyed by owner (their base64 ed25519 public key)
+var validatorPrefix = []byte("val/")
+
+func validatorKey(owner string) []byte {
+	return append(append([]byte{}, validatorPrefix...), owner...)
+}
+
+// getValidator returns the validator with the given owner, or nil if it is not in the validator set
+func getValidator(txn *badger.Txn, owner string) (*types.Validator, error) {
+	validator := &types.Validator{}
+	found, err := getMessage(txn, validatorKey(owner), validator)
+	if !found {
+		return nil, err
+	}
+	return validator, err
+}
+
+// setValidator writes a validator, a validator without power is removed from the set
+func setValidator(txn *badger.Txn, validator *types.Validator) error {
+	if validator.Power <= 0 {
+		return deleteKey(txn, validatorKey(validator.Owner))
+	}
+
+	return setMessage(txn, validatorKey(validator.Owner), validator)
+}
+
+// listValidators returns the validator set ordered by owner
+func listValidators(txn *badger.Txn) ([]*types.Validator, error) {
+	validators := make([]*types.Validator, 0)
+	err := iteratePrefix(txn, validatorPrefix, func(item *badger.Item) error {
+		validator := &types.Validator{}
+		validators = append(validators, validator)
+		return item.Value(func(val []byte) error {
+			return proto.Unmarshal(val, validator)
+		})
+	})
+	return validators, err
+}
+
+// totalPower returns the voting power of the whole validator set
+func totalPower(txn *badger.Txn) (int64, error) {
+	validators, err := listValidators(txn)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, validator := range validators {
+		total += validator.Power
+	}
+	return total, nil
+}
+
+// applyValidatorUpdates stores validator set changes coming from (or going to) CometBFT
+func applyValidatorUpdates(txn *badger.Txn, updates []abcitypes.ValidatorUpdate) error {
+	for _, update := range updates {
+		// Validators sign verification transactions with their consensus key, which has to be ed25519
+		pubKey := update.PubKey.GetEd25519()
+		if pubKey == nil {
+			return fmt.Errorf("unsupported validator key type %T", update.PubKey.Sum)
+		}
+
+		validator := &types.Validator{
+			Owner:  types.Ed25519Owner(pubKey),
+			PubKey: pubKey,
+			Power:  update.Power,
+		}
+		if err := setValidator(txn, validator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
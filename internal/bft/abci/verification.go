@@ -0,0 +1,249 @@
+package verificationApp
+
+import (
+	"encoding/binary"
+	"log"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/ipfs/go-cid"
+	"github.com/openmesh-network/core/internal/bft/types"
+)
+
+const (
+	// maxDatasourceLength bounds the datasource name, which ends up in several keys
+	maxDatasourceLength = 128
+	// maxSubmittedCids bounds how many CIDs a single verification transaction can carry
+	maxSubmittedCids = 1024
+	// maxRoundAge is how many blocks after its interval closed a round still takes submissions
+	maxRoundAge = 100
+)
+
+var (
+	roundPrefix       = []byte("vround/") // Datasource, timestamp -> VerificationRound
+	submissionPrefix  = []byte("vsub/")   // Datasource, timestamp, owner -> attestation
+	tallyPrefix       = []byte("vtally/") // Datasource, timestamp, attestation -> power behind it
+	verifiedCidPrefix = []byte("vcid/")   // CID -> VerifiedCid
+)
+
+// roundID identifies a collection interval of a datasource inside keys.
+// Datasources can't contain NUL, so the separator keeps different rounds from sharing a key prefix.
+func roundID(prefix []byte, datasource string, timestamp int64) []byte {
+	key := append(append([]byte{}, prefix...), datasource...)
+	key = append(key, 0)
+	return binary.BigEndian.AppendUint64(key, uint64(timestamp))
+}
+
+func roundKey(datasource string, timestamp int64) []byte {
+	return roundID(roundPrefix, datasource, timestamp)
+}
+
+func submissionKey(datasource string, timestamp int64, owner string) []byte {
+	return append(roundID(submissionPrefix, datasource, timestamp), owner...)
+}
+
+func tallyKey(datasource string, timestamp int64, attestation string) []byte {
+	return append(roundID(tallyPrefix, datasource, timestamp), attestation...)
+}
+
+func verifiedCidKey(c string) []byte {
+	return append(append([]byte{}, verifiedCidPrefix...), c...)
+}
+
+// getRound returns the verification round of a collection interval, or nil if nobody submitted to it yet
+func getRound(txn *badger.Txn, datasource string, timestamp int64) (*types.VerificationRound, error) {
+	round := &types.VerificationRound{}
+	found, err := getMessage(txn, roundKey(datasource, timestamp), round)
+	if !found {
+		return nil, err
+	}
+	return round, err
+}
+
+// getVerifiedCid returns where a CID was verified, or nil if it was not
+func getVerifiedCid(txn *badger.Txn, c string) (*types.VerifiedCid, error) {
+	verified := &types.VerifiedCid{}
+	found, err := getMessage(txn, verifiedCidKey(c), verified)
+	if !found {
+		return nil, err
+	}
+	return verified, err
+}
+
+// validateSubmission checks a verification transaction on its own, without looking at the state
+func validateSubmission(tx *types.VerificationTransactionData, height int64) (uint32, string) {
+	if !validDatasource(tx.Datasource) {
+		return reject(CodeTypeInvalidDatasource, "Rejected verification: invalid datasource %q", tx.Datasource)
+	}
+	// A collection interval is closed by a block, it can't be attested before that block exists
+	if tx.Timestamp <= 0 || tx.Timestamp >= height || tx.Timestamp < height-maxRoundAge {
+		return reject(CodeTypeInvalidTimestamp, "Rejected verification: interval %d is not open at height %d", tx.Timestamp, height)
+	}
+
+	cids := tx.SubmittedCids()
+	if len(cids) == 0 || len(cids) > maxSubmittedCids {
+		return reject(CodeTypeInvalidCid, "Rejected verification: %d CIDs submitted", len(cids))
+	}
+	for _, s := range cids {
+		// Only accept the canonical string of a CID, so everyone refers to the same data the same way
+		c, err := cid.Decode(s)
+		if err != nil || c.String() != s {
+			return reject(CodeTypeInvalidCid, "Rejected verification: invalid CID %q", s)
+		}
+	}
+	if tx.Attestation != types.AttestationDigest(cids) {
+		return reject(CodeTypeInvalidAttestation, "Rejected verification: attestation does not match the CIDs")
+	}
+	return CodeTypeOK, ""
+}
+
+// handleVerificationTransaction records the submission of a validator for a collection interval.
+// Once validators holding more than 2/3 of the voting power submitted the same attestation, its CIDs
+// are marked as verified and every validator that submitted something else is recorded as disagreeing.
+func (app *VerificationApp) handleVerificationTransaction(txn *badger.Txn, owner string, tx *types.VerificationTransactionData) (uint32, string) {
+	if code, reason := validateSubmission(tx, app.height); code != CodeTypeOK {
+		return code, reason
+	}
+
+	validator, err := getValidator(txn, owner)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if validator == nil {
+		return reject(CodeTypeNotValidator, "Rejected verification: %s is not a validator", owner)
+	}
+
+	return app.recordSubmission(txn, validator, tx)
+}
+
+// recordSubmission stores a validated submission of a validator and adds it to the round of its collection interval
+func (app *VerificationApp) recordSubmission(txn *badger.Txn, validator *types.Validator, tx *types.VerificationTransactionData) (uint32, string) {
+	owner := validator.Owner
+	assigned, err := isAssigned(txn, owner, tx.Datasource, tx.Timestamp)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if !assigned {
+		return reject(CodeTypeNotAssigned, "Rejected verification: %s is not assigned %s at %d", owner, tx.Datasource, tx.Timestamp)
+	}
+
+	subKey := submissionKey(tx.Datasource, tx.Timestamp, owner)
+	if _, err := txn.Get(subKey); err == nil {
+		return reject(CodeTypeDuplicateSubmission, "Rejected verification: %s already submitted for %s at %d", owner, tx.Datasource, tx.Timestamp)
+	} else if err != badger.ErrKeyNotFound {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if err := setKey(txn, subKey, []byte(tx.Attestation)); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+
+	round, err := getRound(txn, tx.Datasource, tx.Timestamp)
+	if err != nil {
+		log.Panicf("Error reading database, unable to execute tx: %v", err)
+	}
+	if round == nil {
+		round = &types.VerificationRound{Datasource: tx.Datasource, Timestamp: tx.Timestamp}
+	}
+
+	agreeing, disagreeing := len(round.Agreeing), len(round.Disagreeing)
+	late := round.Attestation != ""
+	if late {
+		// The round is already decided, late submissions only add to either side
+		if tx.Attestation == round.Attestation {
+			round.Agreeing = append(round.Agreeing, owner)
+		} else {
+			round.Disagreeing = append(round.Disagreeing, owner)
+		}
+	} else if err := app.tallySubmission(txn, round, validator, tx); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+
+	if err := setMessage(txn, roundKey(tx.Datasource, tx.Timestamp), round); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	app.emit(EventVerificationSubmitted, "owner", owner, "datasource", tx.Datasource,
+		"timestamp", strconv.FormatInt(tx.Timestamp, 10), "attestation", tx.Attestation)
+	// Whoever agreed with the round from this submission on helped verify it
+	if err := recordContributions(txn, app.height, round.Agreeing[agreeing:]); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	// and whoever disagreed before it was decided submitted wrong data. Late submissions are only recorded,
+	// the round was decided without them.
+	wrong := round.Disagreeing[disagreeing:]
+	if late {
+		wrong = nil
+	}
+	if err := app.recordWrongData(txn, round.Agreeing[agreeing:], wrong); err != nil {
+		log.Panicf("Error writing to database, unable to execute tx: %v", err)
+	}
+	return CodeTypeOK, ""
+}
+
+// tallySubmission adds the power of the validator to its attestation and decides the round once it has a quorum
+func (app *VerificationApp) tallySubmission(txn *badger.Txn, round *types.VerificationRound, validator *types.Validator, tx *types.VerificationTransactionData) error {
+	key := tallyKey(tx.Datasource, tx.Timestamp, tx.Attestation)
+	var tally uint64
+	if item, err := txn.Get(key); err == nil {
+		err = item.Value(func(val []byte) error {
+			tally = binary.BigEndian.Uint64(val)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	} else if err != badger.ErrKeyNotFound {
+		return err
+	}
+	tally += uint64(validator.Power)
+	if err := setKey(txn, key, binary.BigEndian.AppendUint64(nil, tally)); err != nil {
+		return err
+	}
+
+	total, err := roundPower(txn, tx.Datasource, tx.Timestamp)
+	if err != nil {
+		return err
+	}
+	// Same rule as CometBFT's commits: strictly more than 2/3 of the total power
+	if tally*3 <= uint64(total)*2 {
+		return nil
+	}
+
+	round.Attestation = tx.Attestation
+	round.Cids = tx.SubmittedCids()
+	round.VerifiedHeight = app.height
+	for _, c := range round.Cids {
+		// A CID verified earlier keeps pointing at the round that verified it first
+		verified, err := getVerifiedCid(txn, c)
+		if err != nil {
+			return err
+		}
+		if verified != nil {
+			continue
+		}
+		verified = &types.VerifiedCid{Datasource: round.Datasource, Timestamp: round.Timestamp, Height: app.height}
+		if err := setMessage(txn, verifiedCidKey(c), verified); err != nil {
+			return err
+		}
+		app.emit(EventCidVerified, "cid", c, "datasource", round.Datasource,
+			"timestamp", strconv.FormatInt(round.Timestamp, 10), "height", strconv.FormatInt(app.height, 10))
+	}
+
+	// Sort every submission so far into either side, in owner order, and drop the tallies that are no longer needed
+	prefix := roundID(submissionPrefix, round.Datasource, round.Timestamp)
+	err = iteratePrefix(txn, prefix, func(item *badger.Item) error {
+		owner := string(item.Key()[len(prefix):])
+		return item.Value(func(attestation []byte) error {
+			if string(attestation) == round.Attestation {
+				round.Agreeing = append(round.Agreeing, owner)
+			} else {
+				round.Disagreeing = append(round.Disagreeing, owner)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return dropPrefix(txn, roundID(tallyPrefix, round.Datasource, round.Timestamp))
+}
@@ -1,60 +1,71 @@
 package bft
 
 import (
-	"fmt"
 	cfg "github.com/cometbft/cometbft/config"
 	cmtflags "github.com/cometbft/cometbft/libs/cli/flags"
-	cmtlog "github.com/cometbft/cometbft/libs/log"
 	nm "github.com/cometbft/cometbft/node"
 	bftp2p "github.com/cometbft/cometbft/p2p"
-	"github.com/cometbft/cometbft/privval"
 	"github.com/cometbft/cometbft/proxy"
 	"github.com/dgraph-io/badger/v3"
-	"github.com/spf13/viper"
 	abci "github.com/openmesh-network/core/internal/bft/abci"
+	"github.com/openmesh-network/core/internal/bft/types"
 	"github.com/openmesh-network/core/internal/config"
 	"github.com/openmesh-network/core/internal/logger"
-	"os"
+	"github.com/openmesh-network/core/updater"
+	"path/filepath"
 )
 
 // Instance is the CometBFT instance
 type Instance struct {
 	Config  *cfg.Config
 	BftNode *nm.Node
+	App     *abci.VerificationApp
+
+	owner   string // Owner of the validator key of this node, as found in the collector assignment
+	address []byte // Consensus address of the validator key of this node
+
+	started chan struct{}      // Closed once the node runs
+	halt    chan *updater.Plan // Upgrade the node stopped for, see SetVersion
 }
 
 // NewInstance initialise a CometBFT instance use the config specified
 func NewInstance(db *badger.DB) (*Instance, error) {
-	conf := cfg.DefaultConfig()
-	homeDir := config.Config.BFT.HomeDir
-	conf.SetRoot(homeDir)
-
-	// Parse CometBFT config
-	bftConf := viper.New()
-	// TODO XXX: embed this into the executable instead of loading it from filesystem.
-	bftConf.SetConfigFile(fmt.Sprintf("%s/%s", homeDir, "config/config.toml"))
-	if err := bftConf.ReadInConfig(); err != nil {
+	conf, err := loadConfig(config.Config.BFT.HomeDir)
+	if err != nil {
 		return nil, err
 	}
-	if err := bftConf.Unmarshal(conf); err != nil {
+	log, err := cmtflags.ParseLogLevel(conf.LogLevel, logger.NewCometBFTLogger(), cfg.DefaultLogLevel)
+	if err != nil {
 		return nil, err
 	}
-	if err := conf.ValidateBasic(); err != nil {
+	pv, err := initHome(conf, log)
+	if err != nil {
 		return nil, err
 	}
-
-	app := abci.NewVerificationApp(db)
-	pv := privval.LoadFilePV(
-		conf.PrivValidatorKeyFile(),
-		conf.PrivValidatorStateFile(),
-	)
-	nodeKey, err := bftp2p.LoadNodeKey(conf.NodeKeyFile())
+	pubKey, err := pv.GetPubKey()
 	if err != nil {
 		return nil, err
 	}
 
-	log := cmtlog.NewTMLogger(cmtlog.NewSyncWriter(os.Stdout))
-	log, err = cmtflags.ParseLogLevel(conf.LogLevel, log, cfg.DefaultLogLevel)
+	app := abci.NewVerificationApp(db)
+	app.SetHistoryBlocks(config.Config.DB.HistoryBlocks)
+	app.SetMinGasPrice(config.Config.BFT.MinGasPrice)
+	if interval := config.Config.BFT.SnapshotInterval; interval > 0 {
+		dir := config.Config.BFT.SnapshotDir
+		if dir == "" {
+			dir = filepath.Join(conf.DBDir(), "snapshots")
+		}
+		err := app.EnableSnapshots(abci.SnapshotConfig{
+			Dir:        dir,
+			Interval:   interval,
+			KeepRecent: config.Config.BFT.SnapshotKeepRecent,
+			ChunkSize:  config.Config.BFT.SnapshotChunkSize << 20,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	nodeKey, err := bftp2p.LoadOrGenNodeKey(conf.NodeKeyFile())
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +85,15 @@ func NewInstance(db *badger.DB) (*Instance, error) {
 		return nil, err
 	}
 
-	return &Instance{Config: conf, BftNode: node}, nil
+	return &Instance{
+		Config:  conf,
+		BftNode: node,
+		App:     app,
+		owner:   types.Ed25519Owner(pubKey.Bytes()),
+		address: pubKey.Address(),
+		started: make(chan struct{}),
+		halt:    make(chan *updater.Plan, 1),
+	}, nil
 }
 
 // Start the CometBFT node
@@ -83,12 +102,19 @@ func (i *Instance) Start() {
 		if err := i.BftNode.Start(); err != nil {
 			logger.Fatalf("Failed to start CometBFT node: %s", err.Error())
 		}
+		close(i.started)
 	}()
 }
 
-// Stop the CometBFT node
+// Started is closed once the node runs
+func (i *Instance) Started() <-chan struct{} {
+	return i.started
+}
+
+// Stop the CometBFT node, and wait for the snapshots it was writing so the database can be closed
 func (i *Instance) Stop() error {
 	err := i.BftNode.Stop()
 	i.BftNode.Wait()
+	i.App.WaitSnapshots()
 	return err
 }
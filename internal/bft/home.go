@@ -0,0 +1,144 @@
+package bft
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	cfg "github.com/cometbft/cometbft/config"
+	cmtlog "github.com/cometbft/cometbft/libs/log"
+	cmtrand "github.com/cometbft/cometbft/libs/rand"
+	cmttypes "github.com/cometbft/cometbft/types"
+	cmttime "github.com/cometbft/cometbft/types/time"
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/spf13/viper"
+)
+
+// embeddedGenesis holds the genesis.json compiled into the executable, if any
+var embeddedGenesis fs.FS
+
+// embeddedGenesisFile is the name of the genesis file looked up in embeddedGenesis
+const embeddedGenesisFile = "genesis.json"
+
+// SetEmbeddedGenesis sets the files a genesis.json is looked up in when the home directory has none
+func SetEmbeddedGenesis(files fs.FS) {
+	embeddedGenesis = files
+}
+
+// loadConfig builds the CometBFT configuration: its defaults, then the config.toml of the home directory
+// if there is one, then the settings under bft.cometbft in config.yml, named as in config.toml.
+func loadConfig(homeDir string) (*cfg.Config, error) {
+	conf := cfg.DefaultConfig()
+	conf.SetRoot(homeDir)
+
+	bftConf := viper.New()
+	tomlFile := filepath.Join(homeDir, "config", "config.toml")
+	if _, err := os.Stat(tomlFile); err == nil {
+		bftConf.SetConfigFile(tomlFile)
+		if err := bftConf.ReadInConfig(); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := bftConf.MergeConfigMap(config.Config.BFT.CometBFT); err != nil {
+		return nil, err
+	}
+	if err := bftConf.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	// The home directory comes from config.yml only
+	conf.SetRoot(homeDir)
+	if err := conf.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// initHome creates what a node needs in its home directory on first run: the directories, a validator key
+// and a genesis. Existing files are left as they are.
+func initHome(conf *cfg.Config, log cmtlog.Logger) (cmttypes.PrivValidator, error) {
+	for _, dir := range []string{filepath.Dir(conf.GenesisFile()), conf.DBDir(), filepath.Dir(conf.PrivValidatorStateFile())} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	var genesis *cmttypes.GenesisDoc
+	_, err := os.Stat(conf.GenesisFile())
+	initialised := err == nil
+	if initialised {
+		genesis, err = cmttypes.GenesisDocFromFile(conf.GenesisFile())
+	} else if errors.Is(err, fs.ErrNotExist) {
+		genesis, err = providedGenesis()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := config.Config.BFT.ChainID
+	if genesis != nil {
+		chainID = genesis.ChainID
+	} else if chainID == "" {
+		chainID = fmt.Sprintf("openmesh-local-%s", cmtrand.Str(6))
+	}
+	pv, err := newPrivValidator(conf, chainID, log)
+	if err != nil {
+		return nil, err
+	}
+	if config.Config.BFT.PrivValidator == privValidatorRemote {
+		// The remote signer is already connected, CometBFT would wait for another one
+		conf.PrivValidatorListenAddr = ""
+	}
+	if initialised {
+		return pv, nil
+	}
+
+	if genesis == nil {
+		genesis, err = singleValidatorGenesis(chainID, pv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := genesis.ValidateAndComplete(); err != nil {
+		return nil, err
+	}
+	return pv, genesis.SaveAs(conf.GenesisFile())
+}
+
+// providedGenesis returns the genesis set by bft.genesis, or else the embedded one, nil if there is neither
+func providedGenesis() (*cmttypes.GenesisDoc, error) {
+	if file := config.Config.BFT.Genesis; file != "" {
+		return cmttypes.GenesisDocFromFile(file)
+	}
+	if embeddedGenesis == nil {
+		return nil, nil
+	}
+	raw, err := fs.ReadFile(embeddedGenesis, embeddedGenesisFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return cmttypes.GenesisDocFromJSON(raw)
+}
+
+// singleValidatorGenesis returns the genesis of a new chain with this node as its only validator
+func singleValidatorGenesis(chainID string, pv cmttypes.PrivValidator) (*cmttypes.GenesisDoc, error) {
+	pubKey, err := pv.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return &cmttypes.GenesisDoc{
+		ChainID:         chainID,
+		GenesisTime:     cmttime.Now(),
+		ConsensusParams: cmttypes.DefaultConsensusParams(),
+		Validators: []cmttypes.GenesisValidator{{
+			Address: pubKey.Address(),
+			PubKey:  pubKey,
+			Power:   10,
+		}},
+	}, nil
+}
@@ -0,0 +1,146 @@
+package bft
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	cmtnet "github.com/cometbft/cometbft/libs/net"
+	bftp2p "github.com/cometbft/cometbft/p2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/openmesh-network/core/internal/logger"
+	"github.com/openmesh-network/core/networking/p2p"
+)
+
+const (
+	// peerTopic is the libp2p pub-sub topic Xnodes announce their CometBFT node on
+	peerTopic = "openmesh-core-cometbft-peers"
+	// peerAnnounceInterval is how often the announcement is repeated, for the Xnodes that joined since
+	peerAnnounceInterval = time.Minute
+)
+
+// peerAnnouncement tells the libp2p network how to reach the CometBFT node of an Xnode
+type peerAnnouncement struct {
+	ChainID   string `json:"chainId"`
+	NodeID    string `json:"nodeId"`
+	Address   string `json:"address"`   // host:port of the CometBFT p2p listener
+	Validator []byte `json:"validator"` // Consensus address of the validator key of the node
+}
+
+// AttachP2P announces this CometBFT node to the Xnodes libp2p discovers, and has CometBFT dial the nodes they announce.
+// Validators are kept as persistent peers. The announcements are only hints, CometBFT still authenticates every node ID.
+func (i *Instance) AttachP2P(pi *p2p.Instance) {
+	if !config.Config.BFT.DiscoverPeers {
+		return
+	}
+	if err := pi.JoinTopic(peerTopic); err != nil {
+		logger.Errorf("Failed to join CometBFT peer topic: %s", err.Error())
+		return
+	}
+	messages, err := pi.Subscribe(peerTopic)
+	if err != nil {
+		logger.Errorf("Failed to subscribe to CometBFT peer topic: %s", err.Error())
+		return
+	}
+
+	go i.announcePeer(pi)
+	go i.receivePeers(messages)
+}
+
+// announcePeer publishes the announcement of this node until it stops
+func (i *Instance) announcePeer(pi *p2p.Instance) {
+	ticker := time.NewTicker(peerAnnounceInterval)
+	defer ticker.Stop()
+	for {
+		if address, ok := i.peerAddress(pi); ok {
+			announcement, err := json.Marshal(peerAnnouncement{
+				ChainID:   i.BftNode.GenesisDoc().ChainID,
+				NodeID:    string(i.BftNode.NodeInfo().ID()),
+				Address:   address,
+				Validator: i.address,
+			})
+			if err == nil {
+				err = pi.Publish(peerTopic, announcement)
+			}
+			if err != nil {
+				logger.Errorf("Failed to announce CometBFT peer: %s", err.Error())
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-i.BftNode.Quit():
+			return
+		}
+	}
+}
+
+// peerAddress returns the host:port other nodes reach the CometBFT p2p listener on: the external address if set,
+// or else the listen address, with the host libp2p listens on if CometBFT listens on every interface.
+func (i *Instance) peerAddress(pi *p2p.Instance) (string, bool) {
+	if external := i.Config.P2P.ExternalAddress; external != "" {
+		return strings.TrimPrefix(external, "tcp://"), true
+	}
+	_, address := cmtnet.ProtocolAndAddress(i.Config.P2P.ListenAddress)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", false
+	}
+	if ip := net.ParseIP(host); ip != nil && !ip.IsUnspecified() {
+		return address, true
+	}
+	for _, addr := range (*pi.Host).Addrs() {
+		ip, err := manet.ToIP(addr)
+		if err == nil && !ip.IsLoopback() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast() {
+			return net.JoinHostPort(ip.String(), port), true
+		}
+	}
+	return "", false
+}
+
+// receivePeers has CometBFT dial the nodes announced on the same chain, once per address
+func (i *Instance) receivePeers(messages <-chan *pubsub.Message) {
+	dialed := make(map[string]bool)     // Addresses CometBFT was asked to dial
+	persistent := make(map[string]bool) // Addresses of validators kept as persistent peers
+	for msg := range messages {
+		var announcement peerAnnouncement
+		if err := json.Unmarshal(msg.Data, &announcement); err != nil {
+			continue
+		}
+		if announcement.ChainID != i.BftNode.GenesisDoc().ChainID || announcement.NodeID == string(i.BftNode.NodeInfo().ID()) {
+			continue
+		}
+		address := bftp2p.IDAddressString(bftp2p.ID(announcement.NodeID), announcement.Address)
+		if _, err := bftp2p.NewNetAddressString(address); err != nil {
+			logger.Debugf("Ignored CometBFT peer announcement %s: %s", address, err.Error())
+			continue
+		}
+		// Announcements are repeated, the ones that arrive before the node runs are handled later
+		if !i.BftNode.IsRunning() {
+			continue
+		}
+
+		sw := i.BftNode.Switch()
+		if !persistent[address] {
+			validator, err := i.App.IsValidator(announcement.Validator)
+			if err != nil {
+				logger.Errorf("Failed to read the validator set: %s", err.Error())
+			} else if validator {
+				if err := sw.AddPersistentPeers([]string{address}); err != nil {
+					logger.Errorf("Failed to add persistent peer %s: %s", address, err.Error())
+				}
+				persistent[address] = true
+				logger.Infof("Added validator %s as persistent peer", address)
+			}
+		}
+		if !dialed[address] {
+			dialed[address] = true
+			if err := sw.DialPeersAsync([]string{address}); err != nil {
+				logger.Errorf("Failed to dial peer %s: %s", address, err.Error())
+			}
+		}
+	}
+}
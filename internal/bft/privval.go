@@ -0,0 +1,299 @@
+package bft
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	cmtlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/protoio"
+	"github.com/cometbft/cometbft/libs/tempfile"
+	"github.com/cometbft/cometbft/privval"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/openmesh-network/core/internal/config"
+)
+
+// Where the validator key is kept, see BFTConfig.PrivValidator
+const (
+	privValidatorFile   = "file"
+	privValidatorRemote = "remote"
+	privValidatorSealed = "sealed"
+)
+
+// Files of a sealed validator key, next to where CometBFT keeps the plain ones
+const (
+	sealedKeyFile   = "priv_validator_key.sealed"
+	sealedStateFile = "priv_validator_state.sealed"
+)
+
+// Retries of the remote signer before a signature fails, 50 * 100ms = 5s like CometBFT's own
+const (
+	remoteSignerRetries = 50
+	remoteSignerTimeout = 100 * time.Millisecond
+)
+
+// newPrivValidator returns the validator key set by the configuration, generating it if needed
+func newPrivValidator(conf *cfg.Config, chainID string, log cmtlog.Logger) (cmttypes.PrivValidator, error) {
+	switch mode := config.Config.BFT.PrivValidator; mode {
+	case "", privValidatorFile:
+		return privval.LoadOrGenFilePV(conf.PrivValidatorKeyFile(), conf.PrivValidatorStateFile()), nil
+	case privValidatorRemote:
+		addr := config.Config.BFT.PrivValidatorListenAddr
+		if addr == "" {
+			addr = conf.PrivValidatorListenAddr
+		}
+		if addr == "" {
+			return nil, errors.New("remote validator key requires privValidatorListenAddr")
+		}
+		return newRemoteSigner(addr, chainID, log)
+	case privValidatorSealed:
+		key, err := hex.DecodeString(config.Config.BFT.PrivValidatorSealingKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid privValidatorSealingKey: %w", err)
+		}
+		return loadOrGenSealedPV(
+			filepath.Join(filepath.Dir(conf.PrivValidatorKeyFile()), sealedKeyFile),
+			filepath.Join(filepath.Dir(conf.PrivValidatorStateFile()), sealedStateFile),
+			key,
+		)
+	default:
+		return nil, fmt.Errorf("unknown privValidator %q, expected file, remote or sealed", mode)
+	}
+}
+
+// newRemoteSigner listens for a remote signer, such as tmkms, and waits for it to connect.
+// The remote signer is in charge of the double-sign protection of the key.
+func newRemoteSigner(addr, chainID string, log cmtlog.Logger) (cmttypes.PrivValidator, error) {
+	listener, err := privval.NewSignerListener(addr, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for remote signer: %w", err)
+	}
+	client, err := privval.NewSignerClient(listener, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start remote signer client: %w", err)
+	}
+	if _, err := client.GetPubKey(); err != nil {
+		return nil, fmt.Errorf("remote signer did not provide its key: %w", err)
+	}
+	return privval.NewRetrySignerClient(client, remoteSignerRetries, remoteSignerTimeout), nil
+}
+
+// sealedPV signs like CometBFT's FilePV, but its key and last sign state only ever reach the disk
+// encrypted with the sealing key, so the host running the enclave never sees the key.
+type sealedPV struct {
+	mtx       sync.Mutex
+	aead      cipher.AEAD
+	key       privval.FilePVKey
+	state     privval.FilePVLastSignState
+	statePath string
+}
+
+var _ cmttypes.PrivValidator = (*sealedPV)(nil)
+
+// loadOrGenSealedPV unseals the validator key and its last sign state, generating and sealing a new key if there is none
+func loadOrGenSealedPV(keyPath, statePath string, sealingKey []byte) (*sealedPV, error) {
+	block, err := aes.NewCipher(sealingKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid privValidatorSealingKey: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	pv := &sealedPV{aead: aead, statePath: statePath}
+
+	found, err := pv.unseal(keyPath, &pv.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal validator key: %w", err)
+	}
+	if !found {
+		privKey := ed25519.GenPrivKey()
+		pv.key = privval.FilePVKey{Address: privKey.PubKey().Address(), PubKey: privKey.PubKey(), PrivKey: privKey}
+		if err := pv.seal(keyPath, pv.key); err != nil {
+			return nil, err
+		}
+		// A new key has never signed anything, an old sign state belongs to another key
+		return pv, pv.seal(statePath, pv.state)
+	}
+
+	found, err = pv.unseal(statePath, &pv.state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal validator state: %w", err)
+	}
+	if !found {
+		// Signing again without the state of the key risks double signing
+		return nil, fmt.Errorf("validator state %s is missing", statePath)
+	}
+	return pv, nil
+}
+
+// seal encrypts value to path, replacing the file atomically
+func (pv *sealedPV) seal(path string, value interface{}) error {
+	plain, err := cmtjson.Marshal(value)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, pv.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(path, pv.aead.Seal(nonce, nonce, plain, []byte(filepath.Base(path))), 0600)
+}
+
+// unseal decrypts path into value, it returns false if the file does not exist
+func (pv *sealedPV) unseal(path string, value interface{}) (bool, error) {
+	sealed, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if len(sealed) < pv.aead.NonceSize() {
+		return false, errors.New("sealed file is truncated")
+	}
+	nonce, ciphertext := sealed[:pv.aead.NonceSize()], sealed[pv.aead.NonceSize():]
+	plain, err := pv.aead.Open(nil, nonce, ciphertext, []byte(filepath.Base(path)))
+	if err != nil {
+		return false, err
+	}
+	return true, cmtjson.Unmarshal(plain, value)
+}
+
+// GetPubKey returns the public key of the validator
+func (pv *sealedPV) GetPubKey() (crypto.PubKey, error) {
+	return pv.key.PubKey, nil
+}
+
+// Steps of a height and round, as numbered by FilePV
+const (
+	stepPropose   int8 = 1
+	stepPrevote   int8 = 2
+	stepPrecommit int8 = 3
+)
+
+// SignVote signs the vote unless it conflicts with what was signed before, see FilePV.SignVote
+func (pv *sealedPV) SignVote(chainID string, vote *cmtproto.Vote) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	step := stepPrevote
+	if vote.Type == cmtproto.PrecommitType {
+		step = stepPrecommit
+	}
+	sameHRS, err := pv.state.CheckHRS(vote.Height, vote.Round, step)
+	if err != nil {
+		return err
+	}
+
+	// Vote extensions are not deterministic, so they are signed again every time
+	var extSig []byte
+	if vote.Type == cmtproto.PrecommitType && !cmttypes.ProtoBlockIDIsNil(&vote.BlockID) {
+		extSig, err = pv.key.PrivKey.Sign(cmttypes.VoteExtensionSignBytes(chainID, vote))
+		if err != nil {
+			return err
+		}
+	} else if len(vote.Extension) > 0 {
+		return errors.New("unexpected vote extension - extensions are only allowed in non-nil precommits")
+	}
+	vote.ExtensionSignature = extSig
+
+	signBytes := cmttypes.VoteSignBytes(chainID, vote)
+	if sameHRS {
+		// After a crash CometBFT may ask again for what it already signed, possibly with a new timestamp
+		timestamp, ok := voteOnlyTimestampDiffers(pv.state.SignBytes, signBytes)
+		if !ok {
+			return errors.New("conflicting data")
+		}
+		vote.Timestamp = timestamp
+		vote.Signature = pv.state.Signature
+		return nil
+	}
+
+	sig, err := pv.key.PrivKey.Sign(signBytes)
+	if err != nil {
+		return err
+	}
+	if err := pv.saveSigned(vote.Height, vote.Round, step, signBytes, sig); err != nil {
+		return err
+	}
+	vote.Signature = sig
+	return nil
+}
+
+// SignProposal signs the proposal unless it conflicts with what was signed before, see FilePV.SignProposal
+func (pv *sealedPV) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	sameHRS, err := pv.state.CheckHRS(proposal.Height, proposal.Round, stepPropose)
+	if err != nil {
+		return err
+	}
+
+	signBytes := cmttypes.ProposalSignBytes(chainID, proposal)
+	if sameHRS {
+		timestamp, ok := proposalOnlyTimestampDiffers(pv.state.SignBytes, signBytes)
+		if !ok {
+			return errors.New("conflicting data")
+		}
+		proposal.Timestamp = timestamp
+		proposal.Signature = pv.state.Signature
+		return nil
+	}
+
+	sig, err := pv.key.PrivKey.Sign(signBytes)
+	if err != nil {
+		return err
+	}
+	if err := pv.saveSigned(proposal.Height, proposal.Round, stepPropose, signBytes, sig); err != nil {
+		return err
+	}
+	proposal.Signature = sig
+	return nil
+}
+
+// saveSigned seals the new sign state before the signature is handed out, so it can never be signed over again
+func (pv *sealedPV) saveSigned(height int64, round int32, step int8, signBytes, sig []byte) error {
+	state := pv.state
+	state.Height, state.Round, state.Step = height, round, step
+	state.SignBytes, state.Signature = signBytes, sig
+	if err := pv.seal(pv.statePath, state); err != nil {
+		return err
+	}
+	pv.state = state
+	return nil
+}
+
+// voteOnlyTimestampDiffers returns the timestamp of the vote signed before if it only differs from the new one by its timestamp
+func voteOnlyTimestampDiffers(lastSignBytes, signBytes []byte) (time.Time, bool) {
+	var last, next cmtproto.CanonicalVote
+	if protoio.UnmarshalDelimited(lastSignBytes, &last) != nil || protoio.UnmarshalDelimited(signBytes, &next) != nil {
+		return time.Time{}, false
+	}
+	next.Timestamp = last.Timestamp
+	return last.Timestamp, proto.Equal(&last, &next)
+}
+
+// proposalOnlyTimestampDiffers returns the timestamp of the proposal signed before if it only differs from the new one by its timestamp
+func proposalOnlyTimestampDiffers(lastSignBytes, signBytes []byte) (time.Time, bool) {
+	var last, next cmtproto.CanonicalProposal
+	if protoio.UnmarshalDelimited(lastSignBytes, &last) != nil || protoio.UnmarshalDelimited(signBytes, &next) != nil {
+		return time.Time{}, false
+	}
+	next.Timestamp = last.Timestamp
+	return last.Timestamp, proto.Equal(&last, &next)
+}
@@ -0,0 +1,86 @@
+package bft
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/collector"
+	"github.com/openmesh-network/core/internal/logger"
+)
+
+// AttachCollector makes this validator collect the datasources the chain assigns to it, within the limits the chain
+// governs, and extend its precommits with the summaries of the collector. Each vote at the end of a summary interval
+// cuts the summaries of the window the chain set for it, out of the messages the sources timestamped within it.
+func (i *Instance) AttachCollector(c *collector.CollectorInstance) {
+	i.App.SetSummarySource(func(start, end time.Time) []*types.VerificationTransactionData {
+		summaries := c.CutSummaries(start, end)
+		data := make([]*types.VerificationTransactionData, len(summaries))
+		for j, summary := range summaries {
+			cids := make([]string, len(summary.DataHashes))
+			for k, c := range summary.DataHashes {
+				cids[k] = c.String()
+			}
+			data[j] = &types.VerificationTransactionData{Datasource: summary.Request.Datasource(), Cids: cids}
+		}
+		return data
+	})
+
+	go i.followAssignment(c)
+}
+
+// followAssignment hands the datasources assigned to this validator to the collector whenever they or the
+// collector limits change. Every block makes an assignment, it is followed once the block is committed.
+func (i *Instance) followAssignment(c *collector.CollectorInstance) {
+	// Blocks until the node starts its event bus
+	sub, err := i.BftNode.EventBus().Subscribe(context.Background(), "collector-assignment", cmttypes.EventQueryNewBlock)
+	if err != nil {
+		logger.Errorf("Failed to follow the collector assignment: %s", err.Error())
+		return
+	}
+
+	var current []string
+	var slots, bufferSize uint32
+	update := func() {
+		datasources, err := i.App.Assignment(i.owner)
+		if err != nil {
+			logger.Errorf("Failed to read the collector assignment: %s", err.Error())
+			return
+		}
+		params, err := i.App.GovernanceParams()
+		if err != nil {
+			logger.Errorf("Failed to read the collector limits: %s", err.Error())
+			return
+		}
+		if slices.Equal(datasources, current) && params.CollectorSlots == slots && params.SummaryBufferBytes == bufferSize {
+			return
+		}
+		current, slots, bufferSize = datasources, params.CollectorSlots, params.SummaryBufferBytes
+		c.SetLimits(int(slots), int(bufferSize))
+
+		requests := make([]collector.Request, 0, len(datasources))
+		for _, datasource := range datasources {
+			req, ok := collector.RequestFor(datasource)
+			if !ok {
+				logger.Errorf("Assigned datasource %s is not supported by this node", datasource)
+				continue
+			}
+			requests = append(requests, req)
+		}
+		logger.Infof("Collecting %d assigned datasources", len(requests))
+		c.SubmitRequests(requests)
+	}
+
+	update()
+	for {
+		select {
+		case <-sub.Out():
+			update()
+		case <-sub.Canceled():
+			// The node is shutting down
+			return
+		}
+	}
+}
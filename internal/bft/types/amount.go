@@ -0,0 +1,115 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Decimals is the number of decimal places of a token, amounts on chain are integers of 10^-Decimals tokens.
+	Decimals = 6
+	// SchemaVersion is the version of the transaction payloads produced by this node.
+	// Version 0 carried doubles, version 1 carries integer base units.
+	SchemaVersion uint32 = 1
+
+	// maxExactDouble is the largest integer a double represents exactly (2^53)
+	maxExactDouble = 1 << 53
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported transaction schema version")
+	ErrInvalidAmount      = errors.New("invalid amount")
+)
+
+// unitsFromDouble converts a legacy double amount, which was already expressed in base units.
+// Only whole, non-negative amounts that a double represents exactly can be migrated.
+func unitsFromDouble(amount float64) (uint64, error) {
+	if math.IsNaN(amount) || amount < 0 || amount > maxExactDouble || amount != math.Trunc(amount) {
+		return 0, fmt.Errorf("%w: %v is not a whole number of base units", ErrInvalidAmount, amount)
+	}
+	return uint64(amount), nil
+}
+
+// Migrate upgrades the payload of a transaction to SchemaVersion in place.
+// It must run after the signature check, since the signature covers the payload as it was sent.
+func Migrate(tx *Transaction) error {
+	switch tx.Version {
+	case SchemaVersion:
+		// The deprecated doubles have no meaning any more, refuse them rather than silently ignoring them.
+		if tx.GetNormalData().GetTransactionAmount() != 0 ||
+			tx.GetResourceData().GetTotalResources() != 0 || tx.GetResourceData().GetRewardedResources() != 0 {
+			return fmt.Errorf("%w: version %d transactions must not use double amounts", ErrInvalidAmount, tx.Version)
+		}
+		return nil
+	case 0:
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, tx.Version)
+	}
+
+	if data := tx.GetNormalData(); data != nil {
+		if data.Amount != 0 {
+			return fmt.Errorf("%w: version 0 transactions carry a double amount", ErrInvalidAmount)
+		}
+		amount, err := unitsFromDouble(data.TransactionAmount)
+		if err != nil {
+			return err
+		}
+		data.Amount, data.TransactionAmount = amount, 0
+	}
+
+	if data := tx.GetResourceData(); data != nil {
+		if data.TotalResourceUnits != 0 || data.RewardedResourceUnits != 0 {
+			return fmt.Errorf("%w: version 0 transactions carry double resources", ErrInvalidAmount)
+		}
+		total, err := unitsFromDouble(data.TotalResources)
+		if err != nil {
+			return err
+		}
+		rewarded, err := unitsFromDouble(data.RewardedResources)
+		if err != nil {
+			return err
+		}
+		data.TotalResourceUnits, data.TotalResources = total, 0
+		data.RewardedResourceUnits, data.RewardedResources = rewarded, 0
+	}
+
+	tx.Version = SchemaVersion
+	return nil
+}
+
+// FormatAmount formats base units as a decimal token amount, e.g. 1500000 as "1.5"
+func FormatAmount(units uint64) string {
+	scale := uint64(math.Pow10(Decimals))
+	whole, frac := units/scale, units%scale
+	if frac == 0 {
+		return strconv.FormatUint(whole, 10)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%0*d", whole, Decimals, frac), "0")
+}
+
+// ParseAmount parses a decimal token amount into base units without going through floating point
+func ParseAmount(amount string) (uint64, error) {
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" || len(frac) > Decimals || strings.HasPrefix(whole, "+") || strings.HasPrefix(frac, "+") {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+	frac += strings.Repeat("0", Decimals-len(frac))
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+	f, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+
+	scale := uint64(math.Pow10(Decimals))
+	if w > (math.MaxUint64-f)/scale {
+		return 0, fmt.Errorf("%w: %s overflows", ErrInvalidAmount, amount)
+	}
+	return w*scale + f, nil
+}
@@ -0,0 +1,44 @@
+package types
+
+import "math/bits"
+
+// Gas each type of transaction sent by an account costs, the fee of a transaction pays for its gas
+const (
+	GasNormal       int64 = 10_000
+	GasVerification int64 = 20_000
+	GasPerCid       int64 = 1_000 // On top of GasVerification for every submitted CID
+	GasResource     int64 = 15_000
+	GasStaking      int64 = 25_000
+	GasGovernance   int64 = 50_000
+	GasUpgrade      int64 = 50_000
+)
+
+// Gas returns the gas a transaction costs, 0 for the transactions added by the block proposer
+func Gas(tx *Transaction) int64 {
+	switch tx.Type {
+	case TransactionType_NormalTransaction:
+		return GasNormal
+	case TransactionType_VerificationTransaction:
+		// The number of CIDs is bounded by the transaction size, this can't overflow
+		return GasVerification + GasPerCid*int64(len(tx.GetVerificationData().SubmittedCids()))
+	case TransactionType_ResourceTransaction:
+		return GasResource
+	case TransactionType_StakingTransaction:
+		return GasStaking
+	case TransactionType_GovernanceTransaction:
+		return GasGovernance
+	case TransactionType_UpgradeTransaction:
+		return GasUpgrade
+	default:
+		return 0
+	}
+}
+
+// MinFee returns the fee a transaction needs at the gas price, in base units per gas, saturating at the largest amount
+func MinFee(tx *Transaction, gasPrice uint64) uint64 {
+	hi, fee := bits.Mul64(uint64(Gas(tx)), gasPrice)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return fee
+}
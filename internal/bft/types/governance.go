@@ -0,0 +1,28 @@
+package types
+
+// NewProposalTransaction builds an unsigned proposal to apply the parameter changes
+func NewProposalTransaction(nonce uint64, changes []*ParamChange) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_GovernanceTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_GovernanceData{GovernanceData: &GovernanceTransactionData{
+			Action:  GovernanceAction_Propose,
+			Changes: changes,
+		}},
+	}
+}
+
+// NewVoteTransaction builds an unsigned vote for or against a proposal
+func NewVoteTransaction(nonce uint64, proposalID uint64, approve bool) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_GovernanceTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_GovernanceData{GovernanceData: &GovernanceTransactionData{
+			Action:     GovernanceAction_Vote,
+			ProposalId: proposalID,
+			Approve:    approve,
+		}},
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+// NewResourceTransaction builds an unsigned resource report of the units a node contributed during an epoch
+func NewResourceTransaction(nonce uint64, epoch int64, totalUnits, rewardedUnits uint64) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_ResourceTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_ResourceData{ResourceData: &ResourceTransactionData{
+			Epoch:                 epoch,
+			TotalResourceUnits:    totalUnits,
+			RewardedResourceUnits: rewardedUnits,
+		}},
+	}
+}
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+// KeyType is the signature scheme of a transaction owner
+type KeyType int
+
+const (
+	// KeyTypeEd25519 owners are raw (unpadded) standard base64 encoded ed25519 public keys,
+	// the same encoding the updater uses for its trusted keys.
+	KeyTypeEd25519 KeyType = iota
+	// KeyTypeSecp256k1 owners are EIP-55 checksummed Ethereum addresses, signatures are recoverable [R || S || V].
+	KeyTypeSecp256k1
+)
+
+// signDomain separates transaction signatures from any other message signed with the same key
+const signDomain = "openmesh-core/transaction:"
+
+var (
+	ErrInvalidOwner             = errors.New("invalid transaction owner")
+	ErrInvalidSignatureEncoding = errors.New("invalid signature encoding")
+	ErrMalleableSignature       = errors.New("signature is not in canonical form")
+	ErrInvalidSignature         = errors.New("signature does not match owner")
+)
+
+// sigEncoding is used for signatures and ed25519 owners. Strict decoding rejects
+// non-zero padding bits, so every value has exactly one accepted encoding.
+var sigEncoding = base64.RawStdEncoding.Strict()
+
+// ParseOwner returns the key type and the public key (ed25519) or address (secp256k1) of an owner.
+// Only the canonical encoding of an owner is accepted, so an account cannot be referred to by two different strings.
+func ParseOwner(owner string) (KeyType, []byte, error) {
+	if strings.HasPrefix(owner, "0x") {
+		if !common.IsHexAddress(owner) || common.HexToAddress(owner).Hex() != owner {
+			return 0, nil, fmt.Errorf("%w: %s is not a checksummed address", ErrInvalidOwner, owner)
+		}
+		return KeyTypeSecp256k1, common.HexToAddress(owner).Bytes(), nil
+	}
+
+	key, err := sigEncoding.DecodeString(owner)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return 0, nil, fmt.Errorf("%w: %s is not an ed25519 public key", ErrInvalidOwner, owner)
+	}
+	return KeyTypeEd25519, key, nil
+}
+
+// Ed25519Owner returns the owner string for an ed25519 public key
+func Ed25519Owner(key ed25519.PublicKey) string {
+	return sigEncoding.EncodeToString(key)
+}
+
+// Secp256k1Owner returns the owner string for a secp256k1 public key
+func Secp256k1Owner(key *ecdsa.PublicKey) string {
+	return ethcrypto.PubkeyToAddress(*key).Hex()
+}
+
+// SignBytes returns the canonical encoding of the transaction that its signature covers,
+// which is the deterministic protobuf encoding of everything but the signature itself.
+func SignBytes(tx *Transaction) ([]byte, error) {
+	unsigned := proto.Clone(tx).(*Transaction)
+	unsigned.Signature = ""
+
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(signDomain), body...), nil
+}
+
+// SignEd25519 sets the owner and signature of the transaction using an ed25519 key
+func SignEd25519(tx *Transaction, key ed25519.PrivateKey) error {
+	tx.Owner = Ed25519Owner(key.Public().(ed25519.PublicKey))
+	msg, err := SignBytes(tx)
+	if err != nil {
+		return err
+	}
+	tx.Signature = sigEncoding.EncodeToString(ed25519.Sign(key, msg))
+	return nil
+}
+
+// SignSecp256k1 sets the owner and signature of the transaction using a secp256k1 key
+func SignSecp256k1(tx *Transaction, key *ecdsa.PrivateKey) error {
+	tx.Owner = Secp256k1Owner(&key.PublicKey)
+	msg, err := SignBytes(tx)
+	if err != nil {
+		return err
+	}
+	sig, err := ethcrypto.Sign(ethcrypto.Keccak256(msg), key)
+	if err != nil {
+		return err
+	}
+	tx.Signature = sigEncoding.EncodeToString(sig)
+	return nil
+}
+
+// VerifySignature checks the transaction is signed by its owner.
+// The returned error wraps one of ErrInvalidOwner, ErrInvalidSignatureEncoding, ErrMalleableSignature or ErrInvalidSignature.
+func VerifySignature(tx *Transaction) error {
+	keyType, key, err := ParseOwner(tx.Owner)
+	if err != nil {
+		return err
+	}
+	sig, err := sigEncoding.DecodeString(tx.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignatureEncoding, err.Error())
+	}
+	msg, err := SignBytes(tx)
+	if err != nil {
+		return err
+	}
+
+	switch keyType {
+	case KeyTypeEd25519:
+		if len(sig) != ed25519.SignatureSize {
+			return fmt.Errorf("%w: ed25519 signature must be %d bytes", ErrInvalidSignatureEncoding, ed25519.SignatureSize)
+		}
+		// ed25519.Verify rejects non-canonical S values, so a valid signature cannot be altered into another valid one.
+		if !ed25519.Verify(key, msg, sig) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case KeyTypeSecp256k1:
+		if len(sig) != ethcrypto.SignatureLength {
+			return fmt.Errorf("%w: secp256k1 signature must be %d bytes", ErrInvalidSignatureEncoding, ethcrypto.SignatureLength)
+		}
+		// Only accept low S values and V in {0, 1}, otherwise (R, N-S) would be a second valid signature.
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+		if !ethcrypto.ValidateSignatureValues(sig[64], r, s, true) {
+			return ErrMalleableSignature
+		}
+		pub, err := ethcrypto.SigToPub(ethcrypto.Keccak256(msg), sig)
+		if err != nil || ethcrypto.PubkeyToAddress(*pub) != common.BytesToAddress(key) {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return ErrInvalidOwner
+	}
+}
@@ -0,0 +1,14 @@
+package types
+
+// NewStakingTransaction builds an unsigned staking transaction moving the amount, in base units, between balance and stake
+func NewStakingTransaction(nonce uint64, action StakingAction, amount uint64) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_StakingTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_StakingData{StakingData: &StakingTransactionData{
+			Action: action,
+			Amount: amount,
+		}},
+	}
+}
@@ -7,372 +7,956 @@
 package types
 
 import (
-    protoreflect "google.golang.org/protobuf/reflect/protoreflect"
-    protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-    reflect "reflect"
-    sync "sync"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
 const (
-    // Verify that this generated code is sufficiently up-to-date.
-    _ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
-    // Verify that runtime/protoimpl is sufficiently up-to-date.
-    _ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
 type TransactionType int32
 
 const (
-    TransactionType_NormalTransaction       TransactionType = 0
-    TransactionType_VerificationTransaction TransactionType = 1
-    TransactionType_ResourceTransaction     TransactionType = 2
+	TransactionType_NormalTransaction       TransactionType = 0
+	TransactionType_VerificationTransaction TransactionType = 1
+	TransactionType_ResourceTransaction     TransactionType = 2
+	// Added by the block proposer only, carries the vote extensions of the previous height.
+	TransactionType_AttestationTransaction TransactionType = 3
+	// Added by the block proposer only, carries the collector assignment of the block.
+	TransactionType_AssignmentTransaction TransactionType = 4
+	// Bonds or unbonds the stake of a validator, sent by the owner of its consensus key.
+	TransactionType_StakingTransaction TransactionType = 5
+	// Proposes a change of the governed parameters, or votes on a proposal, sent by a validator.
+	TransactionType_GovernanceTransaction TransactionType = 6
+	// Approves a new version of the node binary from an activation height, sent by an update key of genesis.json.
+	TransactionType_UpgradeTransaction TransactionType = 7
 )
 
 // Enum value maps for TransactionType.
 var (
-    TransactionType_name = map[int32]string{
-        0: "NormalTransaction",
-        1: "VerificationTransaction",
-        2: "ResourceTransaction",
-    }
-    TransactionType_value = map[string]int32{
-        "NormalTransaction":       0,
-        "VerificationTransaction": 1,
-        "ResourceTransaction":     2,
-    }
+	TransactionType_name = map[int32]string{
+		0: "NormalTransaction",
+		1: "VerificationTransaction",
+		2: "ResourceTransaction",
+		3: "AttestationTransaction",
+		4: "AssignmentTransaction",
+		5: "StakingTransaction",
+		6: "GovernanceTransaction",
+		7: "UpgradeTransaction",
+	}
+	TransactionType_value = map[string]int32{
+		"NormalTransaction":       0,
+		"VerificationTransaction": 1,
+		"ResourceTransaction":     2,
+		"AttestationTransaction":  3,
+		"AssignmentTransaction":   4,
+		"StakingTransaction":      5,
+		"GovernanceTransaction":   6,
+		"UpgradeTransaction":      7,
+	}
 )
 
 func (x TransactionType) Enum() *TransactionType {
-    p := new(TransactionType)
-    *p = x
-    return p
+	p := new(TransactionType)
+	*p = x
+	return p
 }
 
 func (x TransactionType) String() string {
-    return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
 func (TransactionType) Descriptor() protoreflect.EnumDescriptor {
-    return file_transaction_proto_enumTypes[0].Descriptor()
+	return file_transaction_proto_enumTypes[0].Descriptor()
 }
 
 func (TransactionType) Type() protoreflect.EnumType {
-    return &file_transaction_proto_enumTypes[0]
+	return &file_transaction_proto_enumTypes[0]
 }
 
 func (x TransactionType) Number() protoreflect.EnumNumber {
-    return protoreflect.EnumNumber(x)
+	return protoreflect.EnumNumber(x)
 }
 
 // Deprecated: Use TransactionType.Descriptor instead.
 func (TransactionType) EnumDescriptor() ([]byte, []int) {
-    return file_transaction_proto_rawDescGZIP(), []int{0}
+	return file_transaction_proto_rawDescGZIP(), []int{0}
 }
 
-type VerificationTransactionData struct {
-    state         protoimpl.MessageState
-    sizeCache     protoimpl.SizeCache
-    unknownFields protoimpl.UnknownFields
+type StakingAction int32
+
+const (
+	// Enters the validator set with a first stake, the owner's ed25519 key becomes the consensus key.
+	StakingAction_Join          StakingAction = 0
+	StakingAction_IncreaseStake StakingAction = 1
+	// Unbonded stake returns to the balance of the owner once the unbonding period is over.
+	StakingAction_Unbond StakingAction = 2
+	// Puts a jailed validator back into the validator set once its jail time is over, the amount must be 0.
+	StakingAction_Unjail StakingAction = 3
+)
+
+// Enum value maps for StakingAction.
+var (
+	StakingAction_name = map[int32]string{
+		0: "Join",
+		1: "IncreaseStake",
+		2: "Unbond",
+		3: "Unjail",
+	}
+	StakingAction_value = map[string]int32{
+		"Join":          0,
+		"IncreaseStake": 1,
+		"Unbond":        2,
+		"Unjail":        3,
+	}
+)
+
+func (x StakingAction) Enum() *StakingAction {
+	p := new(StakingAction)
+	*p = x
+	return p
+}
+
+func (x StakingAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StakingAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[1].Descriptor()
+}
+
+func (StakingAction) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[1]
+}
+
+func (x StakingAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StakingAction.Descriptor instead.
+func (StakingAction) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{1}
+}
+
+type GovernanceAction int32
+
+const (
+	// Opens a proposal to apply the changes, the proposer does not vote for it.
+	GovernanceAction_Propose GovernanceAction = 0
+	// Votes on an open proposal, a later vote of the same validator replaces its earlier one.
+	GovernanceAction_Vote GovernanceAction = 1
+)
+
+// Enum value maps for GovernanceAction.
+var (
+	GovernanceAction_name = map[int32]string{
+		0: "Propose",
+		1: "Vote",
+	}
+	GovernanceAction_value = map[string]int32{
+		"Propose": 0,
+		"Vote":    1,
+	}
+)
+
+func (x GovernanceAction) Enum() *GovernanceAction {
+	p := new(GovernanceAction)
+	*p = x
+	return p
+}
+
+func (x GovernanceAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GovernanceAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[2].Descriptor()
+}
 
-    Attestation string `protobuf:"bytes,1,opt,name=attestation,proto3" json:"attestation,omitempty"`
-    Cid         string `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
-    Datasource  string `protobuf:"bytes,3,opt,name=datasource,proto3" json:"datasource,omitempty"`
-    Timestamp   int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+func (GovernanceAction) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[2]
+}
+
+func (x GovernanceAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GovernanceAction.Descriptor instead.
+func (GovernanceAction) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{2}
+}
+
+type ProposalStatus int32
+
+const (
+	ProposalStatus_ProposalVoting ProposalStatus = 0
+	// Validators holding more than 2/3 of the power approved, the changes apply from the next block.
+	ProposalStatus_ProposalPassed ProposalStatus = 1
+	// Approval became out of reach, or the voting period ended without it.
+	ProposalStatus_ProposalRejected ProposalStatus = 2
+)
+
+// Enum value maps for ProposalStatus.
+var (
+	ProposalStatus_name = map[int32]string{
+		0: "ProposalVoting",
+		1: "ProposalPassed",
+		2: "ProposalRejected",
+	}
+	ProposalStatus_value = map[string]int32{
+		"ProposalVoting":   0,
+		"ProposalPassed":   1,
+		"ProposalRejected": 2,
+	}
+)
+
+func (x ProposalStatus) Enum() *ProposalStatus {
+	p := new(ProposalStatus)
+	*p = x
+	return p
+}
+
+func (x ProposalStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProposalStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_transaction_proto_enumTypes[3].Descriptor()
+}
+
+func (ProposalStatus) Type() protoreflect.EnumType {
+	return &file_transaction_proto_enumTypes[3]
+}
+
+func (x ProposalStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProposalStatus.Descriptor instead.
+func (ProposalStatus) EnumDescriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{3}
+}
+
+type VerificationTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Hex-encoded digest of all the CIDs submitted, see AttestationDigest.
+	Attestation string `protobuf:"bytes,1,opt,name=attestation,proto3" json:"attestation,omitempty"`
+	// Single CID submissions, equivalent to cids = [cid].
+	Cid string `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
+	// Source name and topic the data was collected from, e.g. "binance/btc.eth".
+	Datasource string `protobuf:"bytes,3,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	// Height of the block that closed the collection interval.
+	Timestamp int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Cids      []string `protobuf:"bytes,5,rep,name=cids,proto3" json:"cids,omitempty"`
 }
 
 func (x *VerificationTransactionData) Reset() {
-    *x = VerificationTransactionData{}
-    if protoimpl.UnsafeEnabled {
-        mi := &file_transaction_proto_msgTypes[0]
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        ms.StoreMessageInfo(mi)
-    }
+	*x = VerificationTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *VerificationTransactionData) String() string {
-    return protoimpl.X.MessageStringOf(x)
+	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*VerificationTransactionData) ProtoMessage() {}
 
 func (x *VerificationTransactionData) ProtoReflect() protoreflect.Message {
-    mi := &file_transaction_proto_msgTypes[0]
-    if protoimpl.UnsafeEnabled && x != nil {
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        if ms.LoadMessageInfo() == nil {
-            ms.StoreMessageInfo(mi)
-        }
-        return ms
-    }
-    return mi.MessageOf(x)
+	mi := &file_transaction_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
 // Deprecated: Use VerificationTransactionData.ProtoReflect.Descriptor instead.
 func (*VerificationTransactionData) Descriptor() ([]byte, []int) {
-    return file_transaction_proto_rawDescGZIP(), []int{0}
+	return file_transaction_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *VerificationTransactionData) GetAttestation() string {
-    if x != nil {
-        return x.Attestation
-    }
-    return ""
+	if x != nil {
+		return x.Attestation
+	}
+	return ""
 }
 
 func (x *VerificationTransactionData) GetCid() string {
-    if x != nil {
-        return x.Cid
-    }
-    return ""
+	if x != nil {
+		return x.Cid
+	}
+	return ""
 }
 
 func (x *VerificationTransactionData) GetDatasource() string {
-    if x != nil {
-        return x.Datasource
-    }
-    return ""
+	if x != nil {
+		return x.Datasource
+	}
+	return ""
 }
 
 func (x *VerificationTransactionData) GetTimestamp() int64 {
-    if x != nil {
-        return x.Timestamp
-    }
-    return 0
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
 }
 
-type ResourceTransactionData struct {
-    state         protoimpl.MessageState
-    sizeCache     protoimpl.SizeCache
-    unknownFields protoimpl.UnknownFields
+func (x *VerificationTransactionData) GetCids() []string {
+	if x != nil {
+		return x.Cids
+	}
+	return nil
+}
 
-    TotalResources    float64 `protobuf:"fixed64,1,opt,name=total_resources,json=totalResources,proto3" json:"total_resources,omitempty"`
-    RewardedResources float64 `protobuf:"fixed64,2,opt,name=rewarded_resources,json=rewardedResources,proto3" json:"rewarded_resources,omitempty"`
+type ResourceTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Schema version 0 only, migrated to the integer fields below.
+	//
+	// Deprecated: Marked as deprecated in transaction.proto.
+	TotalResources float64 `protobuf:"fixed64,1,opt,name=total_resources,json=totalResources,proto3" json:"total_resources,omitempty"`
+	// Deprecated: Marked as deprecated in transaction.proto.
+	RewardedResources  float64 `protobuf:"fixed64,2,opt,name=rewarded_resources,json=rewardedResources,proto3" json:"rewarded_resources,omitempty"`
+	TotalResourceUnits uint64  `protobuf:"varint,3,opt,name=total_resource_units,json=totalResourceUnits,proto3" json:"total_resource_units,omitempty"`
+	// Part of the total the node claims a reward for, its weight in the reward pool of the epoch.
+	RewardedResourceUnits uint64 `protobuf:"varint,4,opt,name=rewarded_resource_units,json=rewardedResourceUnits,proto3" json:"rewarded_resource_units,omitempty"`
+	// Epoch the resources were contributed in, reports are only accepted during their epoch.
+	Epoch int64 `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// Optional breakdown of the total.
+	BandwidthUnits uint64 `protobuf:"varint,6,opt,name=bandwidth_units,json=bandwidthUnits,proto3" json:"bandwidth_units,omitempty"`
+	StorageUnits   uint64 `protobuf:"varint,7,opt,name=storage_units,json=storageUnits,proto3" json:"storage_units,omitempty"`
+	UptimeUnits    uint64 `protobuf:"varint,8,opt,name=uptime_units,json=uptimeUnits,proto3" json:"uptime_units,omitempty"`
 }
 
 func (x *ResourceTransactionData) Reset() {
-    *x = ResourceTransactionData{}
-    if protoimpl.UnsafeEnabled {
-        mi := &file_transaction_proto_msgTypes[1]
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        ms.StoreMessageInfo(mi)
-    }
+	*x = ResourceTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *ResourceTransactionData) String() string {
-    return protoimpl.X.MessageStringOf(x)
+	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*ResourceTransactionData) ProtoMessage() {}
 
 func (x *ResourceTransactionData) ProtoReflect() protoreflect.Message {
-    mi := &file_transaction_proto_msgTypes[1]
-    if protoimpl.UnsafeEnabled && x != nil {
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        if ms.LoadMessageInfo() == nil {
-            ms.StoreMessageInfo(mi)
-        }
-        return ms
-    }
-    return mi.MessageOf(x)
+	mi := &file_transaction_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
 // Deprecated: Use ResourceTransactionData.ProtoReflect.Descriptor instead.
 func (*ResourceTransactionData) Descriptor() ([]byte, []int) {
-    return file_transaction_proto_rawDescGZIP(), []int{1}
+	return file_transaction_proto_rawDescGZIP(), []int{1}
 }
 
+// Deprecated: Marked as deprecated in transaction.proto.
 func (x *ResourceTransactionData) GetTotalResources() float64 {
-    if x != nil {
-        return x.TotalResources
-    }
-    return 0
+	if x != nil {
+		return x.TotalResources
+	}
+	return 0
 }
 
+// Deprecated: Marked as deprecated in transaction.proto.
 func (x *ResourceTransactionData) GetRewardedResources() float64 {
-    if x != nil {
-        return x.RewardedResources
-    }
-    return 0
+	if x != nil {
+		return x.RewardedResources
+	}
+	return 0
 }
 
-type NormalTransactionData struct {
-    state         protoimpl.MessageState
-    sizeCache     protoimpl.SizeCache
-    unknownFields protoimpl.UnknownFields
+func (x *ResourceTransactionData) GetTotalResourceUnits() uint64 {
+	if x != nil {
+		return x.TotalResourceUnits
+	}
+	return 0
+}
+
+func (x *ResourceTransactionData) GetRewardedResourceUnits() uint64 {
+	if x != nil {
+		return x.RewardedResourceUnits
+	}
+	return 0
+}
+
+func (x *ResourceTransactionData) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *ResourceTransactionData) GetBandwidthUnits() uint64 {
+	if x != nil {
+		return x.BandwidthUnits
+	}
+	return 0
+}
+
+func (x *ResourceTransactionData) GetStorageUnits() uint64 {
+	if x != nil {
+		return x.StorageUnits
+	}
+	return 0
+}
+
+func (x *ResourceTransactionData) GetUptimeUnits() uint64 {
+	if x != nil {
+		return x.UptimeUnits
+	}
+	return 0
+}
 
-    TransactionAmount float64 `protobuf:"fixed64,1,opt,name=transaction_amount,json=transactionAmount,proto3" json:"transaction_amount,omitempty"`
-    SentTo            string  `protobuf:"bytes,2,opt,name=sent_to,json=sentTo,proto3" json:"sent_to,omitempty"`
+type NormalTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Schema version 0 only, migrated to amount.
+	//
+	// Deprecated: Marked as deprecated in transaction.proto.
+	TransactionAmount float64 `protobuf:"fixed64,1,opt,name=transaction_amount,json=transactionAmount,proto3" json:"transaction_amount,omitempty"`
+	SentTo            string  `protobuf:"bytes,2,opt,name=sent_to,json=sentTo,proto3" json:"sent_to,omitempty"`
+	// Base units, one token is 10^Decimals base units.
+	Amount uint64 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
 }
 
 func (x *NormalTransactionData) Reset() {
-    *x = NormalTransactionData{}
-    if protoimpl.UnsafeEnabled {
-        mi := &file_transaction_proto_msgTypes[2]
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        ms.StoreMessageInfo(mi)
-    }
+	*x = NormalTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *NormalTransactionData) String() string {
-    return protoimpl.X.MessageStringOf(x)
+	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*NormalTransactionData) ProtoMessage() {}
 
 func (x *NormalTransactionData) ProtoReflect() protoreflect.Message {
-    mi := &file_transaction_proto_msgTypes[2]
-    if protoimpl.UnsafeEnabled && x != nil {
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        if ms.LoadMessageInfo() == nil {
-            ms.StoreMessageInfo(mi)
-        }
-        return ms
-    }
-    return mi.MessageOf(x)
+	mi := &file_transaction_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
 // Deprecated: Use NormalTransactionData.ProtoReflect.Descriptor instead.
 func (*NormalTransactionData) Descriptor() ([]byte, []int) {
-    return file_transaction_proto_rawDescGZIP(), []int{2}
+	return file_transaction_proto_rawDescGZIP(), []int{2}
 }
 
+// Deprecated: Marked as deprecated in transaction.proto.
 func (x *NormalTransactionData) GetTransactionAmount() float64 {
-    if x != nil {
-        return x.TransactionAmount
-    }
-    return 0
+	if x != nil {
+		return x.TransactionAmount
+	}
+	return 0
 }
 
 func (x *NormalTransactionData) GetSentTo() string {
-    if x != nil {
-        return x.SentTo
-    }
-    return ""
+	if x != nil {
+		return x.SentTo
+	}
+	return ""
+}
+
+func (x *NormalTransactionData) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type StakingTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action StakingAction `protobuf:"varint,1,opt,name=action,proto3,enum=StakingAction" json:"action,omitempty"`
+	// Base units moved between the balance and the stake.
+	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *StakingTransactionData) Reset() {
+	*x = StakingTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StakingTransactionData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StakingTransactionData) ProtoMessage() {}
+
+func (x *StakingTransactionData) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StakingTransactionData.ProtoReflect.Descriptor instead.
+func (*StakingTransactionData) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StakingTransactionData) GetAction() StakingAction {
+	if x != nil {
+		return x.Action
+	}
+	return StakingAction_Join
+}
+
+func (x *StakingTransactionData) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// ParamChange sets a governed parameter, named as in the params of genesis.json, to a value in its text form.
+type ParamChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *ParamChange) Reset() {
+	*x = ParamChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParamChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParamChange) ProtoMessage() {}
+
+func (x *ParamChange) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParamChange.ProtoReflect.Descriptor instead.
+func (*ParamChange) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ParamChange) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ParamChange) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type GovernanceTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action GovernanceAction `protobuf:"varint,1,opt,name=action,proto3,enum=GovernanceAction" json:"action,omitempty"`
+	// Propose only.
+	Changes []*ParamChange `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	// Vote only.
+	ProposalId uint64 `protobuf:"varint,3,opt,name=proposal_id,json=proposalId,proto3" json:"proposal_id,omitempty"`
+	Approve    bool   `protobuf:"varint,4,opt,name=approve,proto3" json:"approve,omitempty"`
+}
+
+func (x *GovernanceTransactionData) Reset() {
+	*x = GovernanceTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GovernanceTransactionData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GovernanceTransactionData) ProtoMessage() {}
+
+func (x *GovernanceTransactionData) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GovernanceTransactionData.ProtoReflect.Descriptor instead.
+func (*GovernanceTransactionData) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GovernanceTransactionData) GetAction() GovernanceAction {
+	if x != nil {
+		return x.Action
+	}
+	return GovernanceAction_Propose
+}
+
+func (x *GovernanceTransactionData) GetChanges() []*ParamChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GovernanceTransactionData) GetProposalId() uint64 {
+	if x != nil {
+		return x.ProposalId
+	}
+	return 0
+}
+
+func (x *GovernanceTransactionData) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+type UpgradeTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Canonical string of the CID of the binary on IPFS.
+	BinaryCid string `protobuf:"bytes,1,opt,name=binary_cid,json=binaryCid,proto3" json:"binary_cid,omitempty"`
+	// First height the new version executes blocks at.
+	ActivationHeight int64 `protobuf:"varint,2,opt,name=activation_height,json=activationHeight,proto3" json:"activation_height,omitempty"`
+}
+
+func (x *UpgradeTransactionData) Reset() {
+	*x = UpgradeTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpgradeTransactionData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpgradeTransactionData) ProtoMessage() {}
+
+func (x *UpgradeTransactionData) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpgradeTransactionData.ProtoReflect.Descriptor instead.
+func (*UpgradeTransactionData) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpgradeTransactionData) GetBinaryCid() string {
+	if x != nil {
+		return x.BinaryCid
+	}
+	return ""
+}
+
+func (x *UpgradeTransactionData) GetActivationHeight() int64 {
+	if x != nil {
+		return x.ActivationHeight
+	}
+	return 0
 }
 
 type Transaction struct {
-    state         protoimpl.MessageState
-    sizeCache     protoimpl.SizeCache
-    unknownFields protoimpl.UnknownFields
-
-    Owner     string          `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
-    Signature string          `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
-    Type      TransactionType `protobuf:"varint,3,opt,name=type,proto3,enum=TransactionType" json:"type,omitempty"`
-    // Types that are assignable to Data:
-    //
-    //	*Transaction_RawData
-    //	*Transaction_VerificationData
-    //	*Transaction_ResourceData
-    //	*Transaction_NormalData
-    Data isTransaction_Data `protobuf_oneof:"data"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner     string          `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Signature string          `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Type      TransactionType `protobuf:"varint,3,opt,name=type,proto3,enum=TransactionType" json:"type,omitempty"`
+	// Number of transactions the owner had executed before this one, prevents replaying the same transaction.
+	Nonce uint64 `protobuf:"varint,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// Types that are assignable to Data:
+	//	*Transaction_RawData
+	//	*Transaction_VerificationData
+	//	*Transaction_ResourceData
+	//	*Transaction_NormalData
+	//	*Transaction_AttestationData
+	//	*Transaction_AssignmentData
+	//	*Transaction_StakingData
+	//	*Transaction_GovernanceData
+	//	*Transaction_UpgradeData
+	Data isTransaction_Data `protobuf_oneof:"data"`
+	// Schema version of the payload: 0 carries doubles, 1 carries integer base units.
+	Version uint32 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// Base units the owner pays for the gas of the transaction, collected into the reward pool of the epoch.
+	Fee uint64 `protobuf:"varint,13,opt,name=fee,proto3" json:"fee,omitempty"`
 }
 
 func (x *Transaction) Reset() {
-    *x = Transaction{}
-    if protoimpl.UnsafeEnabled {
-        mi := &file_transaction_proto_msgTypes[3]
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        ms.StoreMessageInfo(mi)
-    }
+	*x = Transaction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *Transaction) String() string {
-    return protoimpl.X.MessageStringOf(x)
+	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*Transaction) ProtoMessage() {}
 
 func (x *Transaction) ProtoReflect() protoreflect.Message {
-    mi := &file_transaction_proto_msgTypes[3]
-    if protoimpl.UnsafeEnabled && x != nil {
-        ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-        if ms.LoadMessageInfo() == nil {
-            ms.StoreMessageInfo(mi)
-        }
-        return ms
-    }
-    return mi.MessageOf(x)
+	mi := &file_transaction_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
 // Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
 func (*Transaction) Descriptor() ([]byte, []int) {
-    return file_transaction_proto_rawDescGZIP(), []int{3}
+	return file_transaction_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Transaction) GetOwner() string {
-    if x != nil {
-        return x.Owner
-    }
-    return ""
+	if x != nil {
+		return x.Owner
+	}
+	return ""
 }
 
 func (x *Transaction) GetSignature() string {
-    if x != nil {
-        return x.Signature
-    }
-    return ""
+	if x != nil {
+		return x.Signature
+	}
+	return ""
 }
 
 func (x *Transaction) GetType() TransactionType {
-    if x != nil {
-        return x.Type
-    }
-    return TransactionType_NormalTransaction
+	if x != nil {
+		return x.Type
+	}
+	return TransactionType_NormalTransaction
+}
+
+func (x *Transaction) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
 }
 
 func (m *Transaction) GetData() isTransaction_Data {
-    if m != nil {
-        return m.Data
-    }
-    return nil
+	if m != nil {
+		return m.Data
+	}
+	return nil
 }
 
 func (x *Transaction) GetRawData() []byte {
-    if x, ok := x.GetData().(*Transaction_RawData); ok {
-        return x.RawData
-    }
-    return nil
+	if x, ok := x.GetData().(*Transaction_RawData); ok {
+		return x.RawData
+	}
+	return nil
 }
 
 func (x *Transaction) GetVerificationData() *VerificationTransactionData {
-    if x, ok := x.GetData().(*Transaction_VerificationData); ok {
-        return x.VerificationData
-    }
-    return nil
+	if x, ok := x.GetData().(*Transaction_VerificationData); ok {
+		return x.VerificationData
+	}
+	return nil
 }
 
 func (x *Transaction) GetResourceData() *ResourceTransactionData {
-    if x, ok := x.GetData().(*Transaction_ResourceData); ok {
-        return x.ResourceData
-    }
-    return nil
+	if x, ok := x.GetData().(*Transaction_ResourceData); ok {
+		return x.ResourceData
+	}
+	return nil
 }
 
 func (x *Transaction) GetNormalData() *NormalTransactionData {
-    if x, ok := x.GetData().(*Transaction_NormalData); ok {
-        return x.NormalData
-    }
-    return nil
+	if x, ok := x.GetData().(*Transaction_NormalData); ok {
+		return x.NormalData
+	}
+	return nil
+}
+
+func (x *Transaction) GetAttestationData() *AttestationTransactionData {
+	if x, ok := x.GetData().(*Transaction_AttestationData); ok {
+		return x.AttestationData
+	}
+	return nil
+}
+
+func (x *Transaction) GetAssignmentData() *Assignment {
+	if x, ok := x.GetData().(*Transaction_AssignmentData); ok {
+		return x.AssignmentData
+	}
+	return nil
+}
+
+func (x *Transaction) GetStakingData() *StakingTransactionData {
+	if x, ok := x.GetData().(*Transaction_StakingData); ok {
+		return x.StakingData
+	}
+	return nil
+}
+
+func (x *Transaction) GetGovernanceData() *GovernanceTransactionData {
+	if x, ok := x.GetData().(*Transaction_GovernanceData); ok {
+		return x.GovernanceData
+	}
+	return nil
+}
+
+func (x *Transaction) GetUpgradeData() *UpgradeTransactionData {
+	if x, ok := x.GetData().(*Transaction_UpgradeData); ok {
+		return x.UpgradeData
+	}
+	return nil
+}
+
+func (x *Transaction) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Transaction) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
 }
 
 type isTransaction_Data interface {
-    isTransaction_Data()
+	isTransaction_Data()
 }
 
 type Transaction_RawData struct {
-    RawData []byte `protobuf:"bytes,5,opt,name=raw_data,json=rawData,proto3,oneof"`
+	RawData []byte `protobuf:"bytes,5,opt,name=raw_data,json=rawData,proto3,oneof"`
 }
 
 type Transaction_VerificationData struct {
-    VerificationData *VerificationTransactionData `protobuf:"bytes,6,opt,name=verification_data,json=verificationData,proto3,oneof"`
+	VerificationData *VerificationTransactionData `protobuf:"bytes,6,opt,name=verification_data,json=verificationData,proto3,oneof"`
 }
 
 type Transaction_ResourceData struct {
-    ResourceData *ResourceTransactionData `protobuf:"bytes,7,opt,name=resource_data,json=resourceData,proto3,oneof"`
+	ResourceData *ResourceTransactionData `protobuf:"bytes,7,opt,name=resource_data,json=resourceData,proto3,oneof"`
 }
 
 type Transaction_NormalData struct {
-    NormalData *NormalTransactionData `protobuf:"bytes,8,opt,name=normal_data,json=normalData,proto3,oneof"`
+	NormalData *NormalTransactionData `protobuf:"bytes,8,opt,name=normal_data,json=normalData,proto3,oneof"`
+}
+
+type Transaction_AttestationData struct {
+	AttestationData *AttestationTransactionData `protobuf:"bytes,10,opt,name=attestation_data,json=attestationData,proto3,oneof"`
+}
+
+type Transaction_AssignmentData struct {
+	AssignmentData *Assignment `protobuf:"bytes,11,opt,name=assignment_data,json=assignmentData,proto3,oneof"`
+}
+
+type Transaction_StakingData struct {
+	StakingData *StakingTransactionData `protobuf:"bytes,12,opt,name=staking_data,json=stakingData,proto3,oneof"`
+}
+
+type Transaction_GovernanceData struct {
+	GovernanceData *GovernanceTransactionData `protobuf:"bytes,14,opt,name=governance_data,json=governanceData,proto3,oneof"`
+}
+
+type Transaction_UpgradeData struct {
+	UpgradeData *UpgradeTransactionData `protobuf:"bytes,15,opt,name=upgrade_data,json=upgradeData,proto3,oneof"`
 }
 
 func (*Transaction_RawData) isTransaction_Data() {}
@@ -383,176 +967,2471 @@ func (*Transaction_ResourceData) isTransaction_Data() {}
 
 func (*Transaction_NormalData) isTransaction_Data() {}
 
+func (*Transaction_AttestationData) isTransaction_Data() {}
+
+func (*Transaction_AssignmentData) isTransaction_Data() {}
+
+func (*Transaction_StakingData) isTransaction_Data() {}
+
+func (*Transaction_GovernanceData) isTransaction_Data() {}
+
+func (*Transaction_UpgradeData) isTransaction_Data() {}
+
+// VoteExtension is what a validator attaches to its precommit: the summaries it collected during the interval
+// closed by the height being voted on, one per datasource in datasource order.
+type VoteExtension struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Summaries []*VerificationTransactionData `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+}
+
+func (x *VoteExtension) Reset() {
+	*x = VoteExtension{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoteExtension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteExtension) ProtoMessage() {}
+
+func (x *VoteExtension) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteExtension.ProtoReflect.Descriptor instead.
+func (*VoteExtension) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VoteExtension) GetSummaries() []*VerificationTransactionData {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+// ExtendedVote is a vote extension along with the signature CometBFT made over it.
+type ExtendedVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValidatorAddress   []byte `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Extension          []byte `protobuf:"bytes,2,opt,name=extension,proto3" json:"extension,omitempty"`
+	ExtensionSignature []byte `protobuf:"bytes,3,opt,name=extension_signature,json=extensionSignature,proto3" json:"extension_signature,omitempty"`
+}
+
+func (x *ExtendedVote) Reset() {
+	*x = ExtendedVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedVote) ProtoMessage() {}
+
+func (x *ExtendedVote) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedVote.ProtoReflect.Descriptor instead.
+func (*ExtendedVote) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExtendedVote) GetValidatorAddress() []byte {
+	if x != nil {
+		return x.ValidatorAddress
+	}
+	return nil
+}
+
+func (x *ExtendedVote) GetExtension() []byte {
+	if x != nil {
+		return x.Extension
+	}
+	return nil
+}
+
+func (x *ExtendedVote) GetExtensionSignature() []byte {
+	if x != nil {
+		return x.ExtensionSignature
+	}
+	return nil
+}
+
+// AttestationTransactionData aggregates the vote extensions that committed the previous block.
+type AttestationTransactionData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Height int64           `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round  int32           `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Votes  []*ExtendedVote `protobuf:"bytes,3,rep,name=votes,proto3" json:"votes,omitempty"`
+}
+
+func (x *AttestationTransactionData) Reset() {
+	*x = AttestationTransactionData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttestationTransactionData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttestationTransactionData) ProtoMessage() {}
+
+func (x *AttestationTransactionData) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttestationTransactionData.ProtoReflect.Descriptor instead.
+func (*AttestationTransactionData) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AttestationTransactionData) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *AttestationTransactionData) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *AttestationTransactionData) GetVotes() []*ExtendedVote {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+// Assignment says which validators collect each datasource during the intervals closed after its block.
+type Assignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// Hash of the previous block, the source of randomness of the assignment.
+	Seed []byte `protobuf:"bytes,2,opt,name=seed,proto3" json:"seed,omitempty"`
+	// One entry per registered datasource, in datasource order.
+	Datasources []*DatasourceAssignment `protobuf:"bytes,3,rep,name=datasources,proto3" json:"datasources,omitempty"`
+}
+
+func (x *Assignment) Reset() {
+	*x = Assignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Assignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignment) ProtoMessage() {}
+
+func (x *Assignment) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignment.ProtoReflect.Descriptor instead.
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Assignment) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Assignment) GetSeed() []byte {
+	if x != nil {
+		return x.Seed
+	}
+	return nil
+}
+
+func (x *Assignment) GetDatasources() []*DatasourceAssignment {
+	if x != nil {
+		return x.Datasources
+	}
+	return nil
+}
+
+type DatasourceAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Datasource string `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	// Owners of the assigned validators, in owner order.
+	Validators []string `protobuf:"bytes,2,rep,name=validators,proto3" json:"validators,omitempty"`
+}
+
+func (x *DatasourceAssignment) Reset() {
+	*x = DatasourceAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DatasourceAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatasourceAssignment) ProtoMessage() {}
+
+func (x *DatasourceAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatasourceAssignment.ProtoReflect.Descriptor instead.
+func (*DatasourceAssignment) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DatasourceAssignment) GetDatasource() string {
+	if x != nil {
+		return x.Datasource
+	}
+	return ""
+}
+
+func (x *DatasourceAssignment) GetValidators() []string {
+	if x != nil {
+		return x.Validators
+	}
+	return nil
+}
+
+// Account is the state of an owner stored by the application.
+type Account struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Balance uint64 `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	// Nonce the next transaction from this account must carry.
+	Nonce uint64 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Account) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Account) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// Validator is a member of the validator set as known by the application.
+type Validator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner  string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	PubKey []byte `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Power  int64  `protobuf:"varint,3,opt,name=power,proto3" json:"power,omitempty"`
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Validator) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Validator) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *Validator) GetPower() int64 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+// Stake is what an owner has bonded, and the power CometBFT was last told it has.
+type Stake struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Bonded uint64 `protobuf:"varint,2,opt,name=bonded,proto3" json:"bonded,omitempty"`
+	Power  int64  `protobuf:"varint,3,opt,name=power,proto3" json:"power,omitempty"`
+	// A jailed validator has no power until it unjails, which it can do from jailed_until on.
+	Jailed      bool  `protobuf:"varint,4,opt,name=jailed,proto3" json:"jailed,omitempty"`
+	JailedUntil int64 `protobuf:"varint,5,opt,name=jailed_until,json=jailedUntil,proto3" json:"jailed_until,omitempty"`
+	// Consecutive commits the validator did not sign.
+	MissedBlocks int64 `protobuf:"varint,6,opt,name=missed_blocks,json=missedBlocks,proto3" json:"missed_blocks,omitempty"`
+	// Submissions that disagreed with the quorum, and the stake burnt for any misbehaviour.
+	WrongSubmissions uint64 `protobuf:"varint,7,opt,name=wrong_submissions,json=wrongSubmissions,proto3" json:"wrong_submissions,omitempty"`
+	Slashed          uint64 `protobuf:"varint,8,opt,name=slashed,proto3" json:"slashed,omitempty"`
+	// Consecutive rounds the validator disagreed with before they were decided.
+	WrongRounds int64 `protobuf:"varint,9,opt,name=wrong_rounds,json=wrongRounds,proto3" json:"wrong_rounds,omitempty"`
+}
+
+func (x *Stake) Reset() {
+	*x = Stake{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stake) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stake) ProtoMessage() {}
+
+func (x *Stake) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stake.ProtoReflect.Descriptor instead.
+func (*Stake) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Stake) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *Stake) GetBonded() uint64 {
+	if x != nil {
+		return x.Bonded
+	}
+	return 0
+}
+
+func (x *Stake) GetPower() int64 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *Stake) GetJailed() bool {
+	if x != nil {
+		return x.Jailed
+	}
+	return false
+}
+
+func (x *Stake) GetJailedUntil() int64 {
+	if x != nil {
+		return x.JailedUntil
+	}
+	return 0
+}
+
+func (x *Stake) GetMissedBlocks() int64 {
+	if x != nil {
+		return x.MissedBlocks
+	}
+	return 0
+}
+
+func (x *Stake) GetWrongSubmissions() uint64 {
+	if x != nil {
+		return x.WrongSubmissions
+	}
+	return 0
+}
+
+func (x *Stake) GetSlashed() uint64 {
+	if x != nil {
+		return x.Slashed
+	}
+	return 0
+}
+
+func (x *Stake) GetWrongRounds() int64 {
+	if x != nil {
+		return x.WrongRounds
+	}
+	return 0
+}
+
+// Unbonding is stake of an owner waiting for the end of its unbonding period.
+type Unbonding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner  string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *Unbonding) Reset() {
+	*x = Unbonding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Unbonding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Unbonding) ProtoMessage() {}
+
+func (x *Unbonding) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Unbonding.ProtoReflect.Descriptor instead.
+func (*Unbonding) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Unbonding) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Unbonding) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// SlashingParams set the punishment of validators, fractions are in basis points of their bonded and unbonding stake.
+type SlashingParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DoubleSignSlash uint32 `protobuf:"varint,1,opt,name=double_sign_slash,json=doubleSignSlash,proto3" json:"double_sign_slash,omitempty"`
+	WrongDataSlash  uint32 `protobuf:"varint,2,opt,name=wrong_data_slash,json=wrongDataSlash,proto3" json:"wrong_data_slash,omitempty"`
+	DowntimeSlash   uint32 `protobuf:"varint,3,opt,name=downtime_slash,json=downtimeSlash,proto3" json:"downtime_slash,omitempty"`
+	// Consecutive commits a validator may miss before it is punished for downtime.
+	MaxMissedBlocks int64 `protobuf:"varint,4,opt,name=max_missed_blocks,json=maxMissedBlocks,proto3" json:"max_missed_blocks,omitempty"`
+	// Blocks a punished validator stays jailed for.
+	JailBlocks int64 `protobuf:"varint,5,opt,name=jail_blocks,json=jailBlocks,proto3" json:"jail_blocks,omitempty"`
+	// Consecutive rounds a validator may disagree with before it is punished for wrong data.
+	MaxWrongRounds int64 `protobuf:"varint,6,opt,name=max_wrong_rounds,json=maxWrongRounds,proto3" json:"max_wrong_rounds,omitempty"`
+}
+
+func (x *SlashingParams) Reset() {
+	*x = SlashingParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlashingParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlashingParams) ProtoMessage() {}
+
+func (x *SlashingParams) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlashingParams.ProtoReflect.Descriptor instead.
+func (*SlashingParams) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SlashingParams) GetDoubleSignSlash() uint32 {
+	if x != nil {
+		return x.DoubleSignSlash
+	}
+	return 0
+}
+
+func (x *SlashingParams) GetWrongDataSlash() uint32 {
+	if x != nil {
+		return x.WrongDataSlash
+	}
+	return 0
+}
+
+func (x *SlashingParams) GetDowntimeSlash() uint32 {
+	if x != nil {
+		return x.DowntimeSlash
+	}
+	return 0
+}
+
+func (x *SlashingParams) GetMaxMissedBlocks() int64 {
+	if x != nil {
+		return x.MaxMissedBlocks
+	}
+	return 0
+}
+
+func (x *SlashingParams) GetJailBlocks() int64 {
+	if x != nil {
+		return x.JailBlocks
+	}
+	return 0
+}
+
+func (x *SlashingParams) GetMaxWrongRounds() int64 {
+	if x != nil {
+		return x.MaxWrongRounds
+	}
+	return 0
+}
+
+// Fraction is numerator / denominator, the denominator is never 0.
+type Fraction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Numerator   uint64 `protobuf:"varint,1,opt,name=numerator,proto3" json:"numerator,omitempty"`
+	Denominator uint64 `protobuf:"varint,2,opt,name=denominator,proto3" json:"denominator,omitempty"`
+}
+
+func (x *Fraction) Reset() {
+	*x = Fraction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fraction) ProtoMessage() {}
+
+func (x *Fraction) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fraction.ProtoReflect.Descriptor instead.
+func (*Fraction) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Fraction) GetNumerator() uint64 {
+	if x != nil {
+		return x.Numerator
+	}
+	return 0
+}
+
+func (x *Fraction) GetDenominator() uint64 {
+	if x != nil {
+		return x.Denominator
+	}
+	return 0
+}
+
+// GovernanceParams are the parameters validators change through proposals, along with SlashingParams.
+type GovernanceParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Datasources a collector subscribes to at once, and the most bytes of a message it hashes into one CID.
+	CollectorSlots     uint32 `protobuf:"varint,1,opt,name=collector_slots,json=collectorSlots,proto3" json:"collector_slots,omitempty"`
+	SummaryBufferBytes uint32 `protobuf:"varint,2,opt,name=summary_buffer_bytes,json=summaryBufferBytes,proto3" json:"summary_buffer_bytes,omitempty"`
+	// Blocks covered by a summary, validators only extend the votes of every this many heights with summaries.
+	SummaryIntervalBlocks int64 `protobuf:"varint,3,opt,name=summary_interval_blocks,json=summaryIntervalBlocks,proto3" json:"summary_interval_blocks,omitempty"`
+	// Minted and shared between the resource reports of every epoch.
+	EpochReward           uint64 `protobuf:"varint,4,opt,name=epoch_reward,json=epochReward,proto3" json:"epoch_reward,omitempty"`
+	UnitsPerVerifiedRound uint64 `protobuf:"varint,5,opt,name=units_per_verified_round,json=unitsPerVerifiedRound,proto3" json:"units_per_verified_round,omitempty"`
+	// Share of the update keys that must approve a new version of the binary before it is scheduled.
+	UpdateThreshold *Fraction `protobuf:"bytes,6,opt,name=update_threshold,json=updateThreshold,proto3" json:"update_threshold,omitempty"`
+	// Validators collecting each datasource.
+	AssignmentReplication uint32 `protobuf:"varint,7,opt,name=assignment_replication,json=assignmentReplication,proto3" json:"assignment_replication,omitempty"`
+}
+
+func (x *GovernanceParams) Reset() {
+	*x = GovernanceParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GovernanceParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GovernanceParams) ProtoMessage() {}
+
+func (x *GovernanceParams) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GovernanceParams.ProtoReflect.Descriptor instead.
+func (*GovernanceParams) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GovernanceParams) GetCollectorSlots() uint32 {
+	if x != nil {
+		return x.CollectorSlots
+	}
+	return 0
+}
+
+func (x *GovernanceParams) GetSummaryBufferBytes() uint32 {
+	if x != nil {
+		return x.SummaryBufferBytes
+	}
+	return 0
+}
+
+func (x *GovernanceParams) GetSummaryIntervalBlocks() int64 {
+	if x != nil {
+		return x.SummaryIntervalBlocks
+	}
+	return 0
+}
+
+func (x *GovernanceParams) GetEpochReward() uint64 {
+	if x != nil {
+		return x.EpochReward
+	}
+	return 0
+}
+
+func (x *GovernanceParams) GetUnitsPerVerifiedRound() uint64 {
+	if x != nil {
+		return x.UnitsPerVerifiedRound
+	}
+	return 0
+}
+
+func (x *GovernanceParams) GetUpdateThreshold() *Fraction {
+	if x != nil {
+		return x.UpdateThreshold
+	}
+	return nil
+}
+
+func (x *GovernanceParams) GetAssignmentReplication() uint32 {
+	if x != nil {
+		return x.AssignmentReplication
+	}
+	return 0
+}
+
+// Proposal is a change of the governed parameters put to the vote of the validators.
+type Proposal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           uint64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Proposer     string         `protobuf:"bytes,2,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	Changes      []*ParamChange `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	SubmitHeight int64          `protobuf:"varint,4,opt,name=submit_height,json=submitHeight,proto3" json:"submit_height,omitempty"`
+	// Last height votes are counted at.
+	VotingEndHeight int64          `protobuf:"varint,5,opt,name=voting_end_height,json=votingEndHeight,proto3" json:"voting_end_height,omitempty"`
+	Status          ProposalStatus `protobuf:"varint,6,opt,name=status,proto3,enum=ProposalStatus" json:"status,omitempty"`
+	// Voting power behind each side and in total, as of the last tally.
+	ApprovePower int64 `protobuf:"varint,7,opt,name=approve_power,json=approvePower,proto3" json:"approve_power,omitempty"`
+	RejectPower  int64 `protobuf:"varint,8,opt,name=reject_power,json=rejectPower,proto3" json:"reject_power,omitempty"`
+	TotalPower   int64 `protobuf:"varint,9,opt,name=total_power,json=totalPower,proto3" json:"total_power,omitempty"`
+}
+
+func (x *Proposal) Reset() {
+	*x = Proposal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Proposal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proposal) ProtoMessage() {}
+
+func (x *Proposal) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proposal.ProtoReflect.Descriptor instead.
+func (*Proposal) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Proposal) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Proposal) GetProposer() string {
+	if x != nil {
+		return x.Proposer
+	}
+	return ""
+}
+
+func (x *Proposal) GetChanges() []*ParamChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *Proposal) GetSubmitHeight() int64 {
+	if x != nil {
+		return x.SubmitHeight
+	}
+	return 0
+}
+
+func (x *Proposal) GetVotingEndHeight() int64 {
+	if x != nil {
+		return x.VotingEndHeight
+	}
+	return 0
+}
+
+func (x *Proposal) GetStatus() ProposalStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProposalStatus_ProposalVoting
+}
+
+func (x *Proposal) GetApprovePower() int64 {
+	if x != nil {
+		return x.ApprovePower
+	}
+	return 0
+}
+
+func (x *Proposal) GetRejectPower() int64 {
+	if x != nil {
+		return x.RejectPower
+	}
+	return 0
+}
+
+func (x *Proposal) GetTotalPower() int64 {
+	if x != nil {
+		return x.TotalPower
+	}
+	return 0
+}
+
+// UpgradePlan is a version of the binary enough update keys approved, nodes switch to it at the activation height.
+type UpgradePlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BinaryCid        string `protobuf:"bytes,1,opt,name=binary_cid,json=binaryCid,proto3" json:"binary_cid,omitempty"`
+	ActivationHeight int64  `protobuf:"varint,2,opt,name=activation_height,json=activationHeight,proto3" json:"activation_height,omitempty"`
+	// Height of the block whose approval reached the threshold.
+	ScheduledHeight int64 `protobuf:"varint,3,opt,name=scheduled_height,json=scheduledHeight,proto3" json:"scheduled_height,omitempty"`
+	// Update keys whose latest approval is this plan, in owner order.
+	Approvers []string `protobuf:"bytes,4,rep,name=approvers,proto3" json:"approvers,omitempty"`
+}
+
+func (x *UpgradePlan) Reset() {
+	*x = UpgradePlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpgradePlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpgradePlan) ProtoMessage() {}
+
+func (x *UpgradePlan) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpgradePlan.ProtoReflect.Descriptor instead.
+func (*UpgradePlan) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpgradePlan) GetBinaryCid() string {
+	if x != nil {
+		return x.BinaryCid
+	}
+	return ""
+}
+
+func (x *UpgradePlan) GetActivationHeight() int64 {
+	if x != nil {
+		return x.ActivationHeight
+	}
+	return 0
+}
+
+func (x *UpgradePlan) GetScheduledHeight() int64 {
+	if x != nil {
+		return x.ScheduledHeight
+	}
+	return 0
+}
+
+func (x *UpgradePlan) GetApprovers() []string {
+	if x != nil {
+		return x.Approvers
+	}
+	return nil
+}
+
+// RewardLedger records how the reward pool of an epoch was distributed.
+type RewardLedger struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Epoch int64 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// Base units distributed, the pool minus what rounding left over.
+	Distributed uint64 `protobuf:"varint,2,opt,name=distributed,proto3" json:"distributed,omitempty"`
+	TotalUnits  uint64 `protobuf:"varint,3,opt,name=total_units,json=totalUnits,proto3" json:"total_units,omitempty"`
+	// One entry per report, in owner order.
+	Rewards []*Reward `protobuf:"bytes,4,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	// Transaction fees in the pool, collected since the last distribution. They are paid out before anything is minted.
+	Fees uint64 `protobuf:"varint,5,opt,name=fees,proto3" json:"fees,omitempty"`
+}
+
+func (x *RewardLedger) Reset() {
+	*x = RewardLedger{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RewardLedger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewardLedger) ProtoMessage() {}
+
+func (x *RewardLedger) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewardLedger.ProtoReflect.Descriptor instead.
+func (*RewardLedger) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RewardLedger) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *RewardLedger) GetDistributed() uint64 {
+	if x != nil {
+		return x.Distributed
+	}
+	return 0
+}
+
+func (x *RewardLedger) GetTotalUnits() uint64 {
+	if x != nil {
+		return x.TotalUnits
+	}
+	return 0
+}
+
+func (x *RewardLedger) GetRewards() []*Reward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+func (x *RewardLedger) GetFees() uint64 {
+	if x != nil {
+		return x.Fees
+	}
+	return 0
+}
+
+type Reward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner         string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	ReportedUnits uint64 `protobuf:"varint,2,opt,name=reported_units,json=reportedUnits,proto3" json:"reported_units,omitempty"`
+	// Reported units capped by the verified rounds of the owner during the epoch.
+	RewardedUnits  uint64 `protobuf:"varint,3,opt,name=rewarded_units,json=rewardedUnits,proto3" json:"rewarded_units,omitempty"`
+	VerifiedRounds uint64 `protobuf:"varint,4,opt,name=verified_rounds,json=verifiedRounds,proto3" json:"verified_rounds,omitempty"`
+	Amount         uint64 `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *Reward) Reset() {
+	*x = Reward{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reward) ProtoMessage() {}
+
+func (x *Reward) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reward.ProtoReflect.Descriptor instead.
+func (*Reward) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Reward) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Reward) GetReportedUnits() uint64 {
+	if x != nil {
+		return x.ReportedUnits
+	}
+	return 0
+}
+
+func (x *Reward) GetRewardedUnits() uint64 {
+	if x != nil {
+		return x.RewardedUnits
+	}
+	return 0
+}
+
+func (x *Reward) GetVerifiedRounds() uint64 {
+	if x != nil {
+		return x.VerifiedRounds
+	}
+	return 0
+}
+
+func (x *Reward) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// VerificationRound aggregates the submissions of all validators for a datasource and collection interval.
+type VerificationRound struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Datasource string `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	Timestamp  int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Attestation and CIDs that reached the quorum, empty until then.
+	Attestation    string   `protobuf:"bytes,3,opt,name=attestation,proto3" json:"attestation,omitempty"`
+	Cids           []string `protobuf:"bytes,4,rep,name=cids,proto3" json:"cids,omitempty"`
+	VerifiedHeight int64    `protobuf:"varint,5,opt,name=verified_height,json=verifiedHeight,proto3" json:"verified_height,omitempty"`
+	// Owners of the validators that submitted the agreed attestation, and of those that did not.
+	Agreeing    []string `protobuf:"bytes,6,rep,name=agreeing,proto3" json:"agreeing,omitempty"`
+	Disagreeing []string `protobuf:"bytes,7,rep,name=disagreeing,proto3" json:"disagreeing,omitempty"`
+}
+
+func (x *VerificationRound) Reset() {
+	*x = VerificationRound{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerificationRound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificationRound) ProtoMessage() {}
+
+func (x *VerificationRound) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificationRound.ProtoReflect.Descriptor instead.
+func (*VerificationRound) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *VerificationRound) GetDatasource() string {
+	if x != nil {
+		return x.Datasource
+	}
+	return ""
+}
+
+func (x *VerificationRound) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *VerificationRound) GetAttestation() string {
+	if x != nil {
+		return x.Attestation
+	}
+	return ""
+}
+
+func (x *VerificationRound) GetCids() []string {
+	if x != nil {
+		return x.Cids
+	}
+	return nil
+}
+
+func (x *VerificationRound) GetVerifiedHeight() int64 {
+	if x != nil {
+		return x.VerifiedHeight
+	}
+	return 0
+}
+
+func (x *VerificationRound) GetAgreeing() []string {
+	if x != nil {
+		return x.Agreeing
+	}
+	return nil
+}
+
+func (x *VerificationRound) GetDisagreeing() []string {
+	if x != nil {
+		return x.Disagreeing
+	}
+	return nil
+}
+
+// VerifiedCid points a CID verified by the quorum back to where it was collected.
+type VerifiedCid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Datasource string `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	Timestamp  int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Height     int64  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *VerifiedCid) Reset() {
+	*x = VerifiedCid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifiedCid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifiedCid) ProtoMessage() {}
+
+func (x *VerifiedCid) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifiedCid.ProtoReflect.Descriptor instead.
+func (*VerifiedCid) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *VerifiedCid) GetDatasource() string {
+	if x != nil {
+		return x.Datasource
+	}
+	return ""
+}
+
+func (x *VerifiedCid) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *VerifiedCid) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// SnapshotMetadata is carried by state sync snapshots so every chunk can be checked as it arrives.
+type SnapshotMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChunkHashes [][]byte `protobuf:"bytes,1,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
+}
+
+func (x *SnapshotMetadata) Reset() {
+	*x = SnapshotMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotMetadata) ProtoMessage() {}
+
+func (x *SnapshotMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotMetadata.ProtoReflect.Descriptor instead.
+func (*SnapshotMetadata) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SnapshotMetadata) GetChunkHashes() [][]byte {
+	if x != nil {
+		return x.ChunkHashes
+	}
+	return nil
+}
+
+// ValidatorSet is the answer to the /validators query, in owner order.
+type ValidatorSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Validators []*Validator `protobuf:"bytes,1,rep,name=validators,proto3" json:"validators,omitempty"`
+}
+
+func (x *ValidatorSet) Reset() {
+	*x = ValidatorSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidatorSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatorSet) ProtoMessage() {}
+
+func (x *ValidatorSet) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatorSet.ProtoReflect.Descriptor instead.
+func (*ValidatorSet) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ValidatorSet) GetValidators() []*Validator {
+	if x != nil {
+		return x.Validators
+	}
+	return nil
+}
+
+// Params are the rules the application runs with, the answer to the /params query.
+type Params struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AssignmentReplication uint32 `protobuf:"varint,1,opt,name=assignment_replication,json=assignmentReplication,proto3" json:"assignment_replication,omitempty"`
+	MaxRoundAge           int64  `protobuf:"varint,2,opt,name=max_round_age,json=maxRoundAge,proto3" json:"max_round_age,omitempty"`
+	MaxSubmittedCids      uint32 `protobuf:"varint,3,opt,name=max_submitted_cids,json=maxSubmittedCids,proto3" json:"max_submitted_cids,omitempty"`
+	MaxTxBytes            uint64 `protobuf:"varint,4,opt,name=max_tx_bytes,json=maxTxBytes,proto3" json:"max_tx_bytes,omitempty"`
+	MaxVoteExtensionBytes uint64 `protobuf:"varint,5,opt,name=max_vote_extension_bytes,json=maxVoteExtensionBytes,proto3" json:"max_vote_extension_bytes,omitempty"`
+	UnbondingBlocks       int64  `protobuf:"varint,6,opt,name=unbonding_blocks,json=unbondingBlocks,proto3" json:"unbonding_blocks,omitempty"`
+	// Base units of stake per unit of voting power.
+	PowerReduction        uint64            `protobuf:"varint,7,opt,name=power_reduction,json=powerReduction,proto3" json:"power_reduction,omitempty"`
+	EpochBlocks           int64             `protobuf:"varint,8,opt,name=epoch_blocks,json=epochBlocks,proto3" json:"epoch_blocks,omitempty"`
+	EpochReward           uint64            `protobuf:"varint,9,opt,name=epoch_reward,json=epochReward,proto3" json:"epoch_reward,omitempty"`
+	UnitsPerVerifiedRound uint64            `protobuf:"varint,10,opt,name=units_per_verified_round,json=unitsPerVerifiedRound,proto3" json:"units_per_verified_round,omitempty"`
+	Slashing              *SlashingParams   `protobuf:"bytes,11,opt,name=slashing,proto3" json:"slashing,omitempty"`
+	Governance            *GovernanceParams `protobuf:"bytes,12,opt,name=governance,proto3" json:"governance,omitempty"`
+	VotingPeriodBlocks    int64             `protobuf:"varint,13,opt,name=voting_period_blocks,json=votingPeriodBlocks,proto3" json:"voting_period_blocks,omitempty"`
+	// Owners allowed to approve upgrades, in owner order.
+	UpdateKeys []string `protobuf:"bytes,14,rep,name=update_keys,json=updateKeys,proto3" json:"update_keys,omitempty"`
+	// Blocks an activation height must be ahead of its approval, for nodes to fetch the binary.
+	UpgradeNoticeBlocks int64 `protobuf:"varint,15,opt,name=upgrade_notice_blocks,json=upgradeNoticeBlocks,proto3" json:"upgrade_notice_blocks,omitempty"`
+}
+
+func (x *Params) Reset() {
+	*x = Params{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transaction_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Params) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Params) ProtoMessage() {}
+
+func (x *Params) ProtoReflect() protoreflect.Message {
+	mi := &file_transaction_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Params.ProtoReflect.Descriptor instead.
+func (*Params) Descriptor() ([]byte, []int) {
+	return file_transaction_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Params) GetAssignmentReplication() uint32 {
+	if x != nil {
+		return x.AssignmentReplication
+	}
+	return 0
+}
+
+func (x *Params) GetMaxRoundAge() int64 {
+	if x != nil {
+		return x.MaxRoundAge
+	}
+	return 0
+}
+
+func (x *Params) GetMaxSubmittedCids() uint32 {
+	if x != nil {
+		return x.MaxSubmittedCids
+	}
+	return 0
+}
+
+func (x *Params) GetMaxTxBytes() uint64 {
+	if x != nil {
+		return x.MaxTxBytes
+	}
+	return 0
+}
+
+func (x *Params) GetMaxVoteExtensionBytes() uint64 {
+	if x != nil {
+		return x.MaxVoteExtensionBytes
+	}
+	return 0
+}
+
+func (x *Params) GetUnbondingBlocks() int64 {
+	if x != nil {
+		return x.UnbondingBlocks
+	}
+	return 0
+}
+
+func (x *Params) GetPowerReduction() uint64 {
+	if x != nil {
+		return x.PowerReduction
+	}
+	return 0
+}
+
+func (x *Params) GetEpochBlocks() int64 {
+	if x != nil {
+		return x.EpochBlocks
+	}
+	return 0
+}
+
+func (x *Params) GetEpochReward() uint64 {
+	if x != nil {
+		return x.EpochReward
+	}
+	return 0
+}
+
+func (x *Params) GetUnitsPerVerifiedRound() uint64 {
+	if x != nil {
+		return x.UnitsPerVerifiedRound
+	}
+	return 0
+}
+
+func (x *Params) GetSlashing() *SlashingParams {
+	if x != nil {
+		return x.Slashing
+	}
+	return nil
+}
+
+func (x *Params) GetGovernance() *GovernanceParams {
+	if x != nil {
+		return x.Governance
+	}
+	return nil
+}
+
+func (x *Params) GetVotingPeriodBlocks() int64 {
+	if x != nil {
+		return x.VotingPeriodBlocks
+	}
+	return 0
+}
+
+func (x *Params) GetUpdateKeys() []string {
+	if x != nil {
+		return x.UpdateKeys
+	}
+	return nil
+}
+
+func (x *Params) GetUpgradeNoticeBlocks() int64 {
+	if x != nil {
+		return x.UpgradeNoticeBlocks
+	}
+	return 0
+}
+
 var File_transaction_proto protoreflect.FileDescriptor
 
 var file_transaction_proto_rawDesc = []byte{
-    0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
-    0x6f, 0x74, 0x6f, 0x22, 0x8f, 0x01, 0x0a, 0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
-    0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44,
-    0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69,
-    0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
-    0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-    0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73,
-    0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74,
-    0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
-    0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
-    0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x71, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-    0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61,
-    0x12, 0x27, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72,
-    0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c,
-    0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x72, 0x65, 0x77,
-    0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18,
-    0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x52,
-    0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x5f, 0x0a, 0x15, 0x4e, 0x6f, 0x72, 0x6d,
-    0x61, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74,
-    0x61, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-    0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x74,
-    0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-    0x12, 0x17, 0x0a, 0x07, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
-    0x09, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x22, 0xd5, 0x02, 0x0a, 0x0b, 0x54, 0x72,
-    0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e,
-    0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12,
-    0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01,
-    0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x24, 0x0a,
-    0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x54, 0x72,
-    0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
-    0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x08, 0x72, 0x61, 0x77, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18,
-    0x05, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x72, 0x61, 0x77, 0x44, 0x61, 0x74, 0x61,
-    0x12, 0x4b, 0x0a, 0x11, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-    0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x56, 0x65,
-    0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
-    0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x10, 0x76, 0x65, 0x72,
-    0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x3f, 0x0a,
-    0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07,
-    0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54,
-    0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00,
-    0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x39,
-    0x0a, 0x0b, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20,
-    0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x6e,
-    0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0a, 0x6e,
-    0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74,
-    0x61, 0x2a, 0x5e, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-    0x54, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x54, 0x72,
-    0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x56,
-    0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73,
-    0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x6f,
-    0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10,
-    0x02, 0x42, 0x1e, 0x5a, 0x1c, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2d, 0x6e, 0x6f,
-    0x64, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x79, 0x70, 0x65,
-    0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xa3, 0x01, 0x0a, 0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x73, 0x22, 0xea, 0x02, 0x0a, 0x17, 0x52, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2b, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42, 0x02,
+	0x18, 0x01, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x12, 0x31, 0x0a, 0x12, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x42, 0x02,
+	0x18, 0x01, 0x52, 0x11, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x72, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x75, 0x6e, 0x69,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e,
+	0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x55, 0x6e,
+	0x69, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e,
+	0x69, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x70, 0x74, 0x69, 0x6d,
+	0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x22, 0x7b, 0x0a, 0x15, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x31, 0x0a, 0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42, 0x02, 0x18, 0x01, 0x52,
+	0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x58, 0x0a, 0x16, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x26, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e,
+	0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x37, 0x0a,
+	0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x19, 0x47, 0x6f, 0x76, 0x65, 0x72,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x29, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x47, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x26, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x07,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x70, 0x6f,
+	0x73, 0x61, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x70, 0x72,
+	0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x22, 0x64, 0x0a, 0x16, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a,
+	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x63, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x43, 0x69, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0xdc, 0x05, 0x0a, 0x0b, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x24, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x08, 0x72, 0x61, 0x77, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x72, 0x61,
+	0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x4b, 0x0a, 0x11, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00,
+	0x52, 0x10, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x3f, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x39, 0x0a, 0x0b, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x4e, 0x6f, 0x72, 0x6d, 0x61,
+	0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61,
+	0x48, 0x00, 0x52, 0x0a, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x12, 0x48,
+	0x0a, 0x10, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x41, 0x74, 0x74, 0x65, 0x73,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0f, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12, 0x36, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0b, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x0e, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x3c, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x48,
+	0x00, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x61, 0x74, 0x61, 0x12, 0x45,
+	0x0a, 0x0f, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x47, 0x6f, 0x76, 0x65, 0x72, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0e, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x3c, 0x0a, 0x0c, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x55, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x66, 0x65, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x66, 0x65, 0x65, 0x42,
+	0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x4b, 0x0a, 0x0d, 0x56, 0x6f, 0x74, 0x65, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x09, 0x73, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x52, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x69, 0x65, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x0c, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65,
+	0x64, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x6f, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x10, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x2f, 0x0a, 0x13, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x65,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x22, 0x6f, 0x0a, 0x1a, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x23, 0x0a,
+	0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x76, 0x6f, 0x74,
+	0x65, 0x73, 0x22, 0x71, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x0b,
+	0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x56, 0x0a, 0x14, 0x44, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x39, 0x0a,
+	0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x50, 0x0a, 0x09, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x70,
+	0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x22, 0x98, 0x02, 0x0a, 0x05, 0x53,
+	0x74, 0x61, 0x6b, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x62, 0x6f, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x62,
+	0x6f, 0x6e, 0x64, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6a,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6a, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6a, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x5f, 0x75, 0x6e,
+	0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6a, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d,
+	0x69, 0x73, 0x73, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x77,
+	0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6c, 0x61, 0x73,
+	0x68, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x73, 0x6c, 0x61, 0x73, 0x68,
+	0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x72, 0x6f, 0x75, 0x6e,
+	0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x52,
+	0x6f, 0x75, 0x6e, 0x64, 0x73, 0x22, 0x39, 0x0a, 0x09, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x84, 0x02, 0x0a, 0x0e, 0x53, 0x6c, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x69,
+	0x67, 0x6e, 0x5f, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f,
+	0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x6c, 0x61, 0x73, 0x68, 0x12,
+	0x28, 0x0a, 0x10, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x73, 0x6c,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x77, 0x72, 0x6f, 0x6e, 0x67,
+	0x44, 0x61, 0x74, 0x61, 0x53, 0x6c, 0x61, 0x73, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x6f, 0x77,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x6c, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x6c, 0x61, 0x73, 0x68,
+	0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x6d, 0x61, 0x78,
+	0x4d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6a, 0x61, 0x69, 0x6c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x6a, 0x61, 0x69, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x28, 0x0a,
+	0x10, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x57, 0x72, 0x6f, 0x6e,
+	0x67, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x22, 0x4a, 0x0a, 0x08, 0x46, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x22, 0xee, 0x02, 0x0a, 0x10, 0x47, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0e, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x6c, 0x6f, 0x74,
+	0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x62, 0x75, 0x66,
+	0x66, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x12, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65,
+	0x70, 0x6f, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0b, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x37,
+	0x0a, 0x18, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x15, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x50, 0x65, 0x72, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x65, 0x64, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x34, 0x0a, 0x10, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x35, 0x0a,
+	0x16, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x70, 0x6c,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x61,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc1, 0x02, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61,
+	0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x12, 0x26, 0x0a,
+	0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x07, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x5f,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x76, 0x6f,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x76, 0x6f, 0x74, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x64,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61,
+	0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x23, 0x0a, 0x0d, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x22, 0xa2, 0x01, 0x0a, 0x0b, 0x55, 0x70, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x69, 0x6e, 0x61,
+	0x72, 0x79, 0x5f, 0x63, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x43, 0x69, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x10, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x64, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x22, 0x9e, 0x01,
+	0x0a, 0x0c, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x65,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x52, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x65,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x66, 0x65, 0x65, 0x73, 0x22, 0xad,
+	0x01, 0x0a, 0x06, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12,
+	0x25, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d,
+	0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x27, 0x0a,
+	0x0f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x52, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xee,
+	0x01, 0x0a, 0x11, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x65, 0x64, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x48, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x67, 0x72, 0x65, 0x65, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x67, 0x72, 0x65, 0x65, 0x69, 0x6e, 0x67, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x69, 0x73, 0x61, 0x67, 0x72, 0x65, 0x65, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x61, 0x67, 0x72, 0x65, 0x65, 0x69, 0x6e, 0x67, 0x22,
+	0x63, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x43, 0x69, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x22, 0x35, 0x0a, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x3a, 0x0a, 0x0c, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x12, 0x2a, 0x0a, 0x0a, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x0a, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0xa6, 0x05, 0x0a, 0x06, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x12, 0x35, 0x0a, 0x16, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x15, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78,
+	0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x67, 0x65, 0x12, 0x2c, 0x0a,
+	0x12, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x63,
+	0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x43, 0x69, 0x64, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x6d,
+	0x61, 0x78, 0x5f, 0x74, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x37, 0x0a,
+	0x18, 0x6d, 0x61, 0x78, 0x5f, 0x76, 0x6f, 0x74, 0x65, 0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x15, 0x6d, 0x61, 0x78, 0x56, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0f, 0x75, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x64, 0x75, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x52, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x70,
+	0x6f, 0x63, 0x68, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a,
+	0x0c, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x12, 0x37, 0x0a, 0x18, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x15, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x50, 0x65, 0x72, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x65, 0x64, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x2b, 0x0a, 0x08, 0x73, 0x6c, 0x61,
+	0x73, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x53, 0x6c,
+	0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x08, 0x73, 0x6c,
+	0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a, 0x0a, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x47, 0x6f, 0x76,
+	0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x0a, 0x67,
+	0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x76, 0x6f, 0x74,
+	0x69, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x76, 0x6f, 0x74, 0x69, 0x6e, 0x67, 0x50,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x32, 0x0a, 0x15,
+	0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x6e, 0x6f, 0x74, 0x69, 0x63, 0x65, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x75, 0x70, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x63, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x2a, 0xe0, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10,
+	0x02, 0x12, 0x1a, 0x0a, 0x16, 0x41, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x03, 0x12, 0x19, 0x0a,
+	0x15, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x6b,
+	0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x05,
+	0x12, 0x19, 0x0a, 0x15, 0x47, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x55,
+	0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x10, 0x07, 0x2a, 0x44, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x4a, 0x6f, 0x69, 0x6e, 0x10, 0x00, 0x12, 0x11,
+	0x0a, 0x0d, 0x49, 0x6e, 0x63, 0x72, 0x65, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x10,
+	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x6e, 0x62, 0x6f, 0x6e, 0x64, 0x10, 0x02, 0x12, 0x0a, 0x0a,
+	0x06, 0x55, 0x6e, 0x6a, 0x61, 0x69, 0x6c, 0x10, 0x03, 0x2a, 0x29, 0x0a, 0x10, 0x47, 0x6f, 0x76,
+	0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x0a,
+	0x07, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x6f,
+	0x74, 0x65, 0x10, 0x01, 0x2a, 0x4e, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x61, 0x6c, 0x56, 0x6f, 0x74, 0x69, 0x6e, 0x67, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x72,
+	0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x50, 0x61, 0x73, 0x73, 0x65, 0x64, 0x10, 0x01, 0x12, 0x14,
+	0x0a, 0x10, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x10, 0x02, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x65, 0x73, 0x68, 0x2d, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x62, 0x66, 0x74, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
-    file_transaction_proto_rawDescOnce sync.Once
-    file_transaction_proto_rawDescData = file_transaction_proto_rawDesc
+	file_transaction_proto_rawDescOnce sync.Once
+	file_transaction_proto_rawDescData = file_transaction_proto_rawDesc
 )
 
 func file_transaction_proto_rawDescGZIP() []byte {
-    file_transaction_proto_rawDescOnce.Do(func() {
-        file_transaction_proto_rawDescData = protoimpl.X.CompressGZIP(file_transaction_proto_rawDescData)
-    })
-    return file_transaction_proto_rawDescData
+	file_transaction_proto_rawDescOnce.Do(func() {
+		file_transaction_proto_rawDescData = protoimpl.X.CompressGZIP(file_transaction_proto_rawDescData)
+	})
+	return file_transaction_proto_rawDescData
 }
 
-var file_transaction_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_transaction_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_transaction_proto_goTypes = []interface{}{
-    (TransactionType)(0),                // 0: TransactionType
-    (*VerificationTransactionData)(nil), // 1: VerificationTransactionData
-    (*ResourceTransactionData)(nil),     // 2: ResourceTransactionData
-    (*NormalTransactionData)(nil),       // 3: NormalTransactionData
-    (*Transaction)(nil),                 // 4: Transaction
+	(TransactionType)(0),                // 0: TransactionType
+	(StakingAction)(0),                  // 1: StakingAction
+	(GovernanceAction)(0),               // 2: GovernanceAction
+	(ProposalStatus)(0),                 // 3: ProposalStatus
+	(*VerificationTransactionData)(nil), // 4: VerificationTransactionData
+	(*ResourceTransactionData)(nil),     // 5: ResourceTransactionData
+	(*NormalTransactionData)(nil),       // 6: NormalTransactionData
+	(*StakingTransactionData)(nil),      // 7: StakingTransactionData
+	(*ParamChange)(nil),                 // 8: ParamChange
+	(*GovernanceTransactionData)(nil),   // 9: GovernanceTransactionData
+	(*UpgradeTransactionData)(nil),      // 10: UpgradeTransactionData
+	(*Transaction)(nil),                 // 11: Transaction
+	(*VoteExtension)(nil),               // 12: VoteExtension
+	(*ExtendedVote)(nil),                // 13: ExtendedVote
+	(*AttestationTransactionData)(nil),  // 14: AttestationTransactionData
+	(*Assignment)(nil),                  // 15: Assignment
+	(*DatasourceAssignment)(nil),        // 16: DatasourceAssignment
+	(*Account)(nil),                     // 17: Account
+	(*Validator)(nil),                   // 18: Validator
+	(*Stake)(nil),                       // 19: Stake
+	(*Unbonding)(nil),                   // 20: Unbonding
+	(*SlashingParams)(nil),              // 21: SlashingParams
+	(*Fraction)(nil),                    // 22: Fraction
+	(*GovernanceParams)(nil),            // 23: GovernanceParams
+	(*Proposal)(nil),                    // 24: Proposal
+	(*UpgradePlan)(nil),                 // 25: UpgradePlan
+	(*RewardLedger)(nil),                // 26: RewardLedger
+	(*Reward)(nil),                      // 27: Reward
+	(*VerificationRound)(nil),           // 28: VerificationRound
+	(*VerifiedCid)(nil),                 // 29: VerifiedCid
+	(*SnapshotMetadata)(nil),            // 30: SnapshotMetadata
+	(*ValidatorSet)(nil),                // 31: ValidatorSet
+	(*Params)(nil),                      // 32: Params
 }
 var file_transaction_proto_depIdxs = []int32{
-    0, // 0: Transaction.type:type_name -> TransactionType
-    1, // 1: Transaction.verification_data:type_name -> VerificationTransactionData
-    2, // 2: Transaction.resource_data:type_name -> ResourceTransactionData
-    3, // 3: Transaction.normal_data:type_name -> NormalTransactionData
-    4, // [4:4] is the sub-list for method output_type
-    4, // [4:4] is the sub-list for method input_type
-    4, // [4:4] is the sub-list for extension type_name
-    4, // [4:4] is the sub-list for extension extendee
-    0, // [0:4] is the sub-list for field type_name
+	1,  // 0: StakingTransactionData.action:type_name -> StakingAction
+	2,  // 1: GovernanceTransactionData.action:type_name -> GovernanceAction
+	8,  // 2: GovernanceTransactionData.changes:type_name -> ParamChange
+	0,  // 3: Transaction.type:type_name -> TransactionType
+	4,  // 4: Transaction.verification_data:type_name -> VerificationTransactionData
+	5,  // 5: Transaction.resource_data:type_name -> ResourceTransactionData
+	6,  // 6: Transaction.normal_data:type_name -> NormalTransactionData
+	14, // 7: Transaction.attestation_data:type_name -> AttestationTransactionData
+	15, // 8: Transaction.assignment_data:type_name -> Assignment
+	7,  // 9: Transaction.staking_data:type_name -> StakingTransactionData
+	9,  // 10: Transaction.governance_data:type_name -> GovernanceTransactionData
+	10, // 11: Transaction.upgrade_data:type_name -> UpgradeTransactionData
+	4,  // 12: VoteExtension.summaries:type_name -> VerificationTransactionData
+	13, // 13: AttestationTransactionData.votes:type_name -> ExtendedVote
+	16, // 14: Assignment.datasources:type_name -> DatasourceAssignment
+	22, // 15: GovernanceParams.update_threshold:type_name -> Fraction
+	8,  // 16: Proposal.changes:type_name -> ParamChange
+	3,  // 17: Proposal.status:type_name -> ProposalStatus
+	27, // 18: RewardLedger.rewards:type_name -> Reward
+	18, // 19: ValidatorSet.validators:type_name -> Validator
+	21, // 20: Params.slashing:type_name -> SlashingParams
+	23, // 21: Params.governance:type_name -> GovernanceParams
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_transaction_proto_init() }
 func file_transaction_proto_init() {
-    if File_transaction_proto != nil {
-        return
-    }
-    if !protoimpl.UnsafeEnabled {
-        file_transaction_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-            switch v := v.(*VerificationTransactionData); i {
-            case 0:
-                return &v.state
-            case 1:
-                return &v.sizeCache
-            case 2:
-                return &v.unknownFields
-            default:
-                return nil
-            }
-        }
-        file_transaction_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-            switch v := v.(*ResourceTransactionData); i {
-            case 0:
-                return &v.state
-            case 1:
-                return &v.sizeCache
-            case 2:
-                return &v.unknownFields
-            default:
-                return nil
-            }
-        }
-        file_transaction_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-            switch v := v.(*NormalTransactionData); i {
-            case 0:
-                return &v.state
-            case 1:
-                return &v.sizeCache
-            case 2:
-                return &v.unknownFields
-            default:
-                return nil
-            }
-        }
-        file_transaction_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-            switch v := v.(*Transaction); i {
-            case 0:
-                return &v.state
-            case 1:
-                return &v.sizeCache
-            case 2:
-                return &v.unknownFields
-            default:
-                return nil
-            }
-        }
-    }
-    file_transaction_proto_msgTypes[3].OneofWrappers = []interface{}{
-        (*Transaction_RawData)(nil),
-        (*Transaction_VerificationData)(nil),
-        (*Transaction_ResourceData)(nil),
-        (*Transaction_NormalData)(nil),
-    }
-    type x struct{}
-    out := protoimpl.TypeBuilder{
-        File: protoimpl.DescBuilder{
-            GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-            RawDescriptor: file_transaction_proto_rawDesc,
-            NumEnums:      1,
-            NumMessages:   4,
-            NumExtensions: 0,
-            NumServices:   0,
-        },
-        GoTypes:           file_transaction_proto_goTypes,
-        DependencyIndexes: file_transaction_proto_depIdxs,
-        EnumInfos:         file_transaction_proto_enumTypes,
-        MessageInfos:      file_transaction_proto_msgTypes,
-    }.Build()
-    File_transaction_proto = out.File
-    file_transaction_proto_rawDesc = nil
-    file_transaction_proto_goTypes = nil
-    file_transaction_proto_depIdxs = nil
+	if File_transaction_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_transaction_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerificationTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NormalTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StakingTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParamChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GovernanceTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpgradeTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Transaction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoteExtension); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttestationTransactionData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Assignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DatasourceAssignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Account); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Stake); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Unbonding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlashingParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Fraction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GovernanceParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Proposal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpgradePlan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RewardLedger); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reward); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerificationRound); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifiedCid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidatorSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transaction_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Params); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_transaction_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*Transaction_RawData)(nil),
+		(*Transaction_VerificationData)(nil),
+		(*Transaction_ResourceData)(nil),
+		(*Transaction_NormalData)(nil),
+		(*Transaction_AttestationData)(nil),
+		(*Transaction_AssignmentData)(nil),
+		(*Transaction_StakingData)(nil),
+		(*Transaction_GovernanceData)(nil),
+		(*Transaction_UpgradeData)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_transaction_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_transaction_proto_goTypes,
+		DependencyIndexes: file_transaction_proto_depIdxs,
+		EnumInfos:         file_transaction_proto_enumTypes,
+		MessageInfos:      file_transaction_proto_msgTypes,
+	}.Build()
+	File_transaction_proto = out.File
+	file_transaction_proto_rawDesc = nil
+	file_transaction_proto_goTypes = nil
+	file_transaction_proto_depIdxs = nil
 }
@@ -0,0 +1,14 @@
+package types
+
+// NewUpgradeTransaction builds an unsigned approval of a binary to run from the activation height
+func NewUpgradeTransaction(nonce uint64, binaryCid string, activationHeight int64) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_UpgradeTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_UpgradeData{UpgradeData: &UpgradeTransactionData{
+			BinaryCid:        binaryCid,
+			ActivationHeight: activationHeight,
+		}},
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// AttestationDigest returns the hex-encoded SHA-256 of an ordered list of CIDs.
+// Validators agree on a collection interval when their digests are equal.
+func AttestationDigest(cids []string) string {
+	h := sha256.New()
+	for _, c := range cids {
+		// Length-prefix every CID so that the list can't be re-split into different CIDs with the same digest
+		h.Write(binary.AppendUvarint(nil, uint64(len(c))))
+		h.Write([]byte(c))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SubmittedCids returns the CIDs carried by a verification transaction, including the single CID form
+func (x *VerificationTransactionData) SubmittedCids() []string {
+	if x.GetCid() == "" {
+		return x.GetCids()
+	}
+	return append([]string{x.Cid}, x.GetCids()...)
+}
+
+// NewVerificationTransaction builds an unsigned verification transaction for a collection interval
+func NewVerificationTransaction(nonce uint64, datasource string, timestamp int64, cids []string) *Transaction {
+	return &Transaction{
+		Type:    TransactionType_VerificationTransaction,
+		Nonce:   nonce,
+		Version: SchemaVersion,
+		Data: &Transaction_VerificationData{VerificationData: &VerificationTransactionData{
+			Attestation: AttestationDigest(cids),
+			Datasource:  datasource,
+			Timestamp:   timestamp,
+			Cids:        cids,
+		}},
+	}
+}
@@ -0,0 +1,53 @@
+package bft
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/logger"
+	"github.com/openmesh-network/core/updater"
+)
+
+// UpgradePlan returns the upgrade the update keys scheduled on chain, nil if there is none, along with the last
+// committed height. It returns nil if the state can't be read, the updater asks again later.
+func (i *Instance) UpgradePlan() (*updater.Plan, int64) {
+	plan, height, err := i.App.UpgradePlan()
+	if err != nil {
+		logger.Errorf("Failed to read the upgrade plan: %s", err.Error())
+		return nil, 0
+	}
+	if plan == nil {
+		return nil, height
+	}
+	return toUpdaterPlan(plan), height
+}
+
+// toUpdaterPlan returns the plan as the updater takes it, nil if its CID is invalid
+func toUpdaterPlan(plan *types.UpgradePlan) *updater.Plan {
+	// The chain only accepts canonical CIDs
+	c, err := cid.Decode(plan.BinaryCid)
+	if err != nil {
+		logger.Errorf("Invalid CID in the upgrade plan: %s", err.Error())
+		return nil
+	}
+	return &updater.Plan{BinaryCid: c, ActivationHeight: plan.ActivationHeight}
+}
+
+// SetVersion sets the CID of the binary this node runs, empty if no upgrade installed it. Once the block before the
+// activation height of an upgrade to another binary is committed, the plan is sent to UpgradeHalt: the node must
+// stop there, it refuses to execute the next block. It must be called before the node starts.
+func (i *Instance) SetVersion(binaryCid string) error {
+	return i.App.SetVersion(binaryCid, func(plan *types.UpgradePlan) {
+		if plan := toUpdaterPlan(plan); plan != nil {
+			select {
+			case i.halt <- plan:
+			default:
+				// Already told
+			}
+		}
+	})
+}
+
+// UpgradeHalt receives the upgrade the node must stop for, see SetVersion
+func (i *Instance) UpgradeHalt() <-chan *updater.Plan {
+	return i.halt
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/openmesh-network/core/internal/bft/types"
+)
+
+// Key types of the keyring, the signature schemes transactions support
+const (
+	keyTypeEd25519   = "ed25519"
+	keyTypeSecp256k1 = "secp256k1"
+)
+
+// keyFileSuffix is the extension of the files keys are stored in
+const keyFileSuffix = ".key"
+
+// keyFile is the content of a key file of the keyring
+type keyFile struct {
+	Type string `json:"type"`
+	Key  string `json:"key"` // Hex-encoded ed25519 seed or secp256k1 private key
+}
+
+// Key is a named signing key of the keyring
+type Key struct {
+	Name      string
+	ed25519   ed25519.PrivateKey
+	secp256k1 *ecdsa.PrivateKey
+}
+
+// Owner returns the owner of the transactions the key signs
+func (k *Key) Owner() string {
+	if k.secp256k1 != nil {
+		return types.Secp256k1Owner(&k.secp256k1.PublicKey)
+	}
+	return types.Ed25519Owner(k.ed25519.Public().(ed25519.PublicKey))
+}
+
+// Sign sets the owner and signature of the transaction
+func (k *Key) Sign(tx *types.Transaction) error {
+	if k.secp256k1 != nil {
+		return types.SignSecp256k1(tx, k.secp256k1)
+	}
+	return types.SignEd25519(tx, k.ed25519)
+}
+
+// Keyring is a directory of key files, readable by their owner only
+type Keyring struct {
+	dir string
+}
+
+// NewKeyring returns the keyring kept in the directory
+func NewKeyring(dir string) *Keyring {
+	return &Keyring{dir: dir}
+}
+
+func (kr *Keyring) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid key name %q", name)
+	}
+	return filepath.Join(kr.dir, name+keyFileSuffix), nil
+}
+
+// Add generates a new key, it never replaces an existing one
+func (kr *Keyring) Add(name, keyType string) (*Key, error) {
+	path, err := kr.path(name)
+	if err != nil {
+		return nil, err
+	}
+	file := keyFile{Type: keyType}
+	switch keyType {
+	case keyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		file.Key = hex.EncodeToString(key.Seed())
+	case keyTypeSecp256k1:
+		key, err := ethcrypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		file.Key = hex.EncodeToString(ethcrypto.FromECDSA(key))
+	default:
+		return nil, fmt.Errorf("unknown key type %q, expected %s or %s", keyType, keyTypeEd25519, keyTypeSecp256k1)
+	}
+
+	content, err := json.Marshal(&file)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(kr.dir, 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("key %s already exists", name)
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return kr.Get(name)
+}
+
+// Get loads a key by its name
+func (kr *Keyring) Get(name string) (*Key, error) {
+	path, err := kr.path(name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("key %s not found in %s", name, kr.dir)
+	} else if err != nil {
+		return nil, err
+	}
+	file := keyFile{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	raw, err := hex.DecodeString(file.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+
+	key := &Key{Name: name}
+	switch file.Type {
+	case keyTypeEd25519:
+		if len(raw) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid key file %s: ed25519 seed must be %d bytes", path, ed25519.SeedSize)
+		}
+		key.ed25519 = ed25519.NewKeyFromSeed(raw)
+	case keyTypeSecp256k1:
+		key.secp256k1, err = ethcrypto.ToECDSA(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid key file %s: unknown key type %q", path, file.Type)
+	}
+	return key, nil
+}
+
+// List returns the names of the keys in the keyring, sorted
+func (kr *Keyring) List() ([]string, error) {
+	entries, err := os.ReadDir(kr.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), keyFileSuffix); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/openmesh-network/core/internal/api"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/config"
+)
+
+const txUsage = `Usage: openmesh-core [-config file] tx [-api addr] [-keyring dir] <command>
+
+Commands:
+  keys add [-type ed25519|secp256k1] <name>   Generate a key
+  keys list                                   List the keys and their owners
+  send <flags> <recipient> <amount>           Transfer tokens, e.g. 1.5
+  verify <flags> <datasource> <timestamp> <cid>...
+                                              Submit the CIDs collected for an interval
+  resource <flags> -epoch <n> -total <units> -rewarded <units> [-bandwidth <units>] [-storage <units>] [-uptime <units>]
+                                              Report the resources contributed during an epoch
+  stake <flags> join|increase|unbond|unjail [amount]
+                                              Bond, unbond or unjail a validator stake
+  gov <flags> propose <name>=<value>...       Propose new values of governed parameters, e.g. collectorSlots=4
+  gov <flags> vote <proposal> yes|no          Vote on a proposal as a validator
+  upgrade <flags> <cid> <height>              Approve the binary with the CID to run from the height, as an update key
+
+Flags of transactions:
+  -from <key>    Key to sign with
+  -nonce <n>     Nonce to use, the next one of the account by default
+  -fee <amount>  Fee to pay, e.g. 0.01, the gas of the transaction at minGasPrice by default
+  -wait=false    Return once the mempool accepted the transaction, instead of once it is in a block
+`
+
+// Tx runs the tx command: it builds transactions, signs them with a key of the local keyring and
+// broadcasts them through the API of a node, printing the result.
+func Tx(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("tx", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { fmt.Fprint(out, txUsage) }
+	apiAddr := flags.String("api", config.Config.API.ListenAddr, "Address of the transaction API of the node")
+	keyringDir := flags.String("keyring", config.Config.API.KeyringDir, "Directory of the keyring")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing command")
+	}
+
+	keyring := NewKeyring(*keyringDir)
+	command, args := flags.Arg(0), flags.Args()[1:]
+	if command == "keys" {
+		return keys(keyring, args, out)
+	}
+
+	tx := &txCommand{keyring: keyring, client: api.NewClient(*apiAddr), out: out}
+	switch command {
+	case "send":
+		return tx.run(command, args, 2, func(args []string) (*types.Transaction, error) {
+			amount, err := types.ParseAmount(args[1])
+			if err != nil {
+				return nil, err
+			}
+			return &types.Transaction{
+				Type:    types.TransactionType_NormalTransaction,
+				Version: types.SchemaVersion,
+				Data:    &types.Transaction_NormalData{NormalData: &types.NormalTransactionData{SentTo: args[0], Amount: amount}},
+			}, nil
+		})
+	case "verify":
+		return tx.run(command, args, 3, func(args []string) (*types.Transaction, error) {
+			timestamp, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp: %w", err)
+			}
+			return types.NewVerificationTransaction(0, args[0], timestamp, args[2:]), nil
+		})
+	case "resource":
+		return tx.runResource(args)
+	case "stake":
+		return tx.run(command, args, 1, func(args []string) (*types.Transaction, error) {
+			action, ok := stakingActions[args[0]]
+			if !ok {
+				return nil, fmt.Errorf("unknown staking action %q", args[0])
+			}
+			var amount uint64
+			if len(args) > 1 {
+				var err error
+				if amount, err = types.ParseAmount(args[1]); err != nil {
+					return nil, err
+				}
+			}
+			return types.NewStakingTransaction(0, action, amount), nil
+		})
+	case "gov":
+		return tx.run(command, args, 2, func(args []string) (*types.Transaction, error) {
+			switch args[0] {
+			case "propose":
+				changes := make([]*types.ParamChange, 0, len(args)-1)
+				for _, arg := range args[1:] {
+					name, value, ok := strings.Cut(arg, "=")
+					if !ok {
+						return nil, fmt.Errorf("invalid change %q, expected <name>=<value>", arg)
+					}
+					changes = append(changes, &types.ParamChange{Name: name, Value: value})
+				}
+				return types.NewProposalTransaction(0, changes), nil
+			case "vote":
+				if len(args) != 3 || (args[2] != "yes" && args[2] != "no") {
+					return nil, errors.New("vote takes a proposal and yes or no")
+				}
+				id, err := strconv.ParseUint(args[1], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid proposal: %w", err)
+				}
+				return types.NewVoteTransaction(0, id, args[2] == "yes"), nil
+			default:
+				return nil, fmt.Errorf("unknown gov command %q, expected propose or vote", args[0])
+			}
+		})
+	case "upgrade":
+		return tx.run(command, args, 2, func(args []string) (*types.Transaction, error) {
+			height, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid activation height: %w", err)
+			}
+			return types.NewUpgradeTransaction(0, args[0], height), nil
+		})
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+var stakingActions = map[string]types.StakingAction{
+	"join":     types.StakingAction_Join,
+	"increase": types.StakingAction_IncreaseStake,
+	"unbond":   types.StakingAction_Unbond,
+	"unjail":   types.StakingAction_Unjail,
+}
+
+// keys runs the keys subcommands
+func keys(keyring *Keyring, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing keys command, expected add or list")
+	}
+	switch args[0] {
+	case "add":
+		flags := flag.NewFlagSet("keys add", flag.ContinueOnError)
+		flags.SetOutput(out)
+		keyType := flags.String("type", keyTypeEd25519, "Key type: ed25519 or secp256k1")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New("keys add takes the name of the key")
+		}
+		key, err := keyring.Add(flags.Arg(0), *keyType)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\t%s\n", key.Name, key.Owner())
+		return nil
+	case "list":
+		names, err := keyring.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			key, err := keyring.Get(name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s\t%s\n", key.Name, key.Owner())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown keys command %q, expected add or list", args[0])
+	}
+}
+
+// txCommand builds, signs and broadcasts a transaction
+type txCommand struct {
+	keyring *Keyring
+	client  *api.Client
+	out     io.Writer
+
+	flags *flag.FlagSet
+	from  *string
+	nonce *int64
+	fee   *string
+	wait  *bool
+}
+
+// parse parses the flags every transaction takes, the command may add its own first
+func (c *txCommand) parse(command string, args []string, minArgs int) ([]string, error) {
+	if c.flags == nil {
+		c.flags = flag.NewFlagSet(command, flag.ContinueOnError)
+	}
+	c.flags.SetOutput(c.out)
+	c.from = c.flags.String("from", "", "Key to sign with")
+	c.nonce = c.flags.Int64("nonce", -1, "Nonce to use, the next one of the account by default")
+	c.fee = c.flags.String("fee", "", "Fee to pay, the gas of the transaction at minGasPrice by default")
+	c.wait = c.flags.Bool("wait", true, "Wait for the block including the transaction")
+	if err := c.flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if *c.from == "" {
+		return nil, fmt.Errorf("%s needs the key to sign with, set -from", command)
+	}
+	if c.flags.NArg() < minArgs {
+		return nil, fmt.Errorf("%s takes at least %d arguments, see tx -help", command, minArgs)
+	}
+	return c.flags.Args(), nil
+}
+
+// run builds the transaction from the arguments left after the flags, then signs and broadcasts it
+func (c *txCommand) run(command string, args []string, minArgs int, build func(args []string) (*types.Transaction, error)) error {
+	args, err := c.parse(command, args, minArgs)
+	if err != nil {
+		return err
+	}
+	tx, err := build(args)
+	if err != nil {
+		return err
+	}
+	return c.broadcast(tx)
+}
+
+func (c *txCommand) runResource(args []string) error {
+	c.flags = flag.NewFlagSet("resource", flag.ContinueOnError)
+	epoch := c.flags.Int64("epoch", -1, "Epoch the resources were contributed during")
+	total := c.flags.Uint64("total", 0, "Resource units contributed")
+	rewarded := c.flags.Uint64("rewarded", 0, "Resource units to be rewarded for")
+	bandwidth := c.flags.Uint64("bandwidth", 0, "Bandwidth units of the total")
+	storage := c.flags.Uint64("storage", 0, "Storage units of the total")
+	uptime := c.flags.Uint64("uptime", 0, "Uptime units of the total")
+	if _, err := c.parse("resource", args, 0); err != nil {
+		return err
+	}
+	if *epoch < 0 {
+		return errors.New("resource needs the epoch, set -epoch")
+	}
+
+	tx := types.NewResourceTransaction(0, *epoch, *total, *rewarded)
+	data := tx.GetResourceData()
+	data.BandwidthUnits, data.StorageUnits, data.UptimeUnits = *bandwidth, *storage, *uptime
+	return c.broadcast(tx)
+}
+
+// broadcast signs the transaction with the next nonce of the account and the minimum fee unless they were set,
+// and prints the result
+func (c *txCommand) broadcast(tx *types.Transaction) error {
+	key, err := c.keyring.Get(*c.from)
+	if err != nil {
+		return err
+	}
+	if *c.fee != "" {
+		if tx.Fee, err = types.ParseAmount(*c.fee); err != nil {
+			return fmt.Errorf("invalid fee: %w", err)
+		}
+	} else {
+		tx.Fee = types.MinFee(tx, config.Config.BFT.MinGasPrice)
+	}
+	if *c.nonce >= 0 {
+		tx.Nonce = uint64(*c.nonce)
+	} else {
+		account, err := c.client.Account(key.Owner())
+		if err != nil {
+			return fmt.Errorf("failed to read the nonce of %s: %w", key.Owner(), err)
+		}
+		tx.Nonce = account.Nonce
+	}
+	if err := key.Sign(tx); err != nil {
+		return err
+	}
+
+	result, err := c.client.Broadcast(tx, *c.wait)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	if result.CheckTx.Code != 0 {
+		return fmt.Errorf("transaction rejected by the mempool with code %d", result.CheckTx.Code)
+	}
+	if result.TxResult != nil && result.TxResult.Code != 0 {
+		return fmt.Errorf("transaction failed with code %d", result.TxResult.Code)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openmesh-network/core/internal/api"
+	"github.com/openmesh-network/core/internal/bft/types"
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring := NewKeyring(t.TempDir())
+	ed, err := keyring.Add("validator", keyTypeEd25519)
+	assert.NoError(t, err)
+	eth, err := keyring.Add("wallet", keyTypeSecp256k1)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(eth.Owner(), "0x"))
+
+	_, err = keyring.Add("validator", keyTypeEd25519)
+	assert.ErrorContains(t, err, "already exists")
+	_, err = keyring.Add("../escape", keyTypeEd25519)
+	assert.ErrorContains(t, err, "invalid key name")
+	_, err = keyring.Add("other", "rsa")
+	assert.ErrorContains(t, err, "unknown key type")
+
+	names, err := keyring.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"validator", "wallet"}, names)
+	loaded, err := keyring.Get("validator")
+	assert.NoError(t, err)
+	assert.Equal(t, ed.Owner(), loaded.Owner())
+
+	for _, key := range []*Key{ed, eth} {
+		tx := types.NewStakingTransaction(0, types.StakingAction_Join, 1)
+		assert.NoError(t, key.Sign(tx))
+		assert.NoError(t, types.VerifySignature(tx))
+	}
+}
+
+func TestTxCommands(t *testing.T) {
+	received := make([]*types.Transaction, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"nonce": "4"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		tx := &types.Transaction{}
+		assert.NoError(t, protojson.Unmarshal(body, tx))
+		assert.NoError(t, types.VerifySignature(tx))
+		received = append(received, tx)
+		json.NewEncoder(w).Encode(&api.TxResult{Hash: "AB", Height: 9})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	run := func(args ...string) (string, error) {
+		out := &bytes.Buffer{}
+		err := Tx(append([]string{"-api", server.URL, "-keyring", dir}, args...), out)
+		return out.String(), err
+	}
+
+	out, err := run("keys", "add", "alice")
+	assert.NoError(t, err)
+	owner := strings.Fields(out)[1]
+	out, err = run("keys", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice\t"+owner+"\n", out)
+
+	out, err = run("send", "-from", "alice", "-fee", "0.02", owner, "1.5")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"height": 9`)
+	_, err = run("verify", "-from", "alice", "-nonce", "8", "ethereum/blocks", "100", "bafy1", "bafy2")
+	assert.NoError(t, err)
+	_, err = run("resource", "-from", "alice", "-epoch", "3", "-total", "10", "-rewarded", "7", "-storage", "10")
+	assert.NoError(t, err)
+	_, err = run("stake", "-from", "alice", "-wait=false", "unjail")
+	assert.NoError(t, err)
+	_, err = run("gov", "-from", "alice", "propose", "collectorSlots=4", "updateThreshold=3/4")
+	assert.NoError(t, err)
+	_, err = run("gov", "-from", "alice", "vote", "7", "yes")
+	assert.NoError(t, err)
+	_, err = run("upgrade", "-from", "alice", "bafy3", "1000")
+	assert.NoError(t, err)
+
+	assert.Len(t, received, 7)
+	assert.Equal(t, uint64(4), received[0].Nonce)
+	assert.Equal(t, uint64(1_500_000), received[0].GetNormalData().Amount)
+	assert.Equal(t, uint64(20_000), received[0].Fee)
+	assert.Equal(t, uint64(8), received[1].Nonce)
+	assert.Equal(t, []string{"bafy1", "bafy2"}, received[1].GetVerificationData().Cids)
+	assert.Equal(t, types.MinFee(received[1], config.Config.BFT.MinGasPrice), received[1].Fee)
+	assert.Equal(t, uint64(10), received[2].GetResourceData().StorageUnits)
+	assert.Equal(t, types.StakingAction_Unjail, received[3].GetStakingData().Action)
+	assert.Equal(t, []string{"collectorSlots", "updateThreshold"}, []string{
+		received[4].GetGovernanceData().Changes[0].Name, received[4].GetGovernanceData().Changes[1].Name,
+	})
+	assert.Equal(t, "3/4", received[4].GetGovernanceData().Changes[1].Value)
+	assert.Equal(t, uint64(7), received[5].GetGovernanceData().ProposalId)
+	assert.True(t, received[5].GetGovernanceData().Approve)
+	assert.Equal(t, "bafy3", received[6].GetUpgradeData().BinaryCid)
+	assert.Equal(t, int64(1000), received[6].GetUpgradeData().ActivationHeight)
+
+	_, err = run("send", "-from", "alice", "-fee", "-1", owner, "1")
+	assert.ErrorContains(t, err, "invalid fee")
+	_, err = run("send", owner, "1")
+	assert.ErrorContains(t, err, "-from")
+	_, err = run("stake", "-from", "alice", "promote")
+	assert.ErrorContains(t, err, "unknown staking action")
+	_, err = run("resource", "-from", "alice")
+	assert.ErrorContains(t, err, "-epoch")
+	_, err = run("gov", "-from", "alice", "propose", "collectorSlots")
+	assert.ErrorContains(t, err, "<name>=<value>")
+	_, err = run("gov", "-from", "alice", "vote", "7", "maybe")
+	assert.ErrorContains(t, err, "yes or no")
+	_, err = run("upgrade", "-from", "alice", "bafy3", "soon")
+	assert.ErrorContains(t, err, "invalid activation height")
+}
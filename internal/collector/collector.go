@@ -1,7 +1,13 @@
 package collector
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ipfs/go-cid"
 	"github.com/multiformats/go-multicodec"
@@ -9,11 +15,36 @@ import (
 	"github.com/sourcegraph/conc"
 )
 
+// Request is a topic of a source to collect. Its subscription connects the source, every request needs a source of its own.
 type Request struct {
 	Source Source
 	Topic  int
 }
 
+// Datasource names the source and topic of a request, e.g. "binance/btc.eth".
+// It is what verification transactions refer to.
+func (req Request) Datasource() string {
+	return fmt.Sprintf("%s/%s", req.Source.Name(), req.Source.Topics()[req.Topic])
+}
+
+// RequestFor returns the request of a datasource, the inverse of Request.Datasource.
+func RequestFor(datasource string) (Request, bool) {
+	name, topic, found := strings.Cut(datasource, "/")
+	if !found {
+		return Request{}, false
+	}
+	source, found := Lookup(name)
+	if !found {
+		return Request{}, false
+	}
+	for i, t := range source.Topics() {
+		if t == topic {
+			return Request{Source: source, Topic: i}, true
+		}
+	}
+	return Request{}, false
+}
+
 type Summary struct {
 	Request Request
 	// XXX: This might not be efficient, array of pointers means many cache misses.
@@ -21,22 +52,55 @@ type Summary struct {
 	DataHashes []cid.Cid
 }
 
+// collection holds the hashes of the messages of a request that are not cut into a summary yet.
+type collection struct {
+	request  Request
+	messages []collected
+	// Messages from before the end of the last cut are dropped, the summary they belong to was handed out already.
+	cut time.Time
+}
+
+// collected is a message with the time the source gave it and its hashes.
+type collected struct {
+	timestamp time.Time
+	hashes    []cid.Cid
+}
+
 type CollectorInstance struct {
 	// Lower in the queue is higher priority
 	requestsByPriorityCurrent []Request
 	requestsByPriorityNew     []Request
-	summariesLatest           []Summary
+	collections               []collection
+	summariesLock             sync.Mutex
 	requestNotifyChannel      chan struct{}
 	stop                      chan struct{}
+
+	// Guarded by summariesLock, see SetLimits
+	connectionsMax int
+	bufferSize     int
 }
 
+// Limits a collector starts with, the chain governs them afterwards, see SetLimits.
 const CONNECTIONS_MAX = 1
+const BUFFER_SIZE = 1024 * 4
+
+func New() *CollectorInstance {
+	return &CollectorInstance{
+		collections:          make([]collection, CONNECTIONS_MAX),
+		requestNotifyChannel: make(chan struct{}, 1),
+		connectionsMax:       CONNECTIONS_MAX,
+		bufferSize:           BUFFER_SIZE,
+	}
+}
 
-// const BUFFER_SIZE_MAX = 1024
-// const BUFFER_MAX = 1024
+// SetLimits sets how many subscriptions run at once and the most bytes of a message hashed into one CID.
+// They apply from the next submitted requests.
+func (collectorInstance *CollectorInstance) SetLimits(connectionsMax int, bufferSize int) {
+	collectorInstance.summariesLock.Lock()
+	defer collectorInstance.summariesLock.Unlock()
 
-func New() CollectorInstance {
-	return CollectorInstance{}
+	collectorInstance.connectionsMax = connectionsMax
+	collectorInstance.bufferSize = bufferSize
 }
 
 func (collectorInstance *CollectorInstance) SubmitRequests(requestsSortedByPriority []Request) {
@@ -46,26 +110,82 @@ func (collectorInstance *CollectorInstance) SubmitRequests(requestsSortedByPrior
 	collectorInstance.requestNotifyChannel <- struct{}{}
 }
 
-// Return latest summaries, wait this is a race condition :facepalm:.
+// Return a copy of the summaries of everything collected and not cut yet.
 func (collectorInstance *CollectorInstance) FetchSummaries() []Summary {
-	// We could tell the collector to run the .
-	// TODO: Add a mutex here.
+	collectorInstance.summariesLock.Lock()
+	defer collectorInstance.summariesLock.Unlock()
+
+	summaries := make([]Summary, 0, len(collectorInstance.collections))
+	for i := range collectorInstance.collections {
+		collection := &collectorInstance.collections[i]
+		collection.sort()
+		summary := Summary{Request: collection.request}
+		for _, message := range collection.messages {
+			summary.DataHashes = append(summary.DataHashes, message.hashes...)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
 
-	return collectorInstance.summariesLatest
+// CutSummaries returns the summaries of the messages whose source timestamps are in [start, end) and drops
+// everything from before end. Messages are ordered by their timestamps, not by when they arrived, so every
+// collector of a datasource cuts the same summary for the same window whenever it is called.
+func (collectorInstance *CollectorInstance) CutSummaries(start, end time.Time) []Summary {
+	collectorInstance.summariesLock.Lock()
+	defer collectorInstance.summariesLock.Unlock()
+
+	summaries := make([]Summary, 0, len(collectorInstance.collections))
+	for i := range collectorInstance.collections {
+		collection := &collectorInstance.collections[i]
+		collection.sort()
+
+		summary := Summary{Request: collection.request}
+		kept := 0
+		for _, message := range collection.messages {
+			if !message.timestamp.Before(end) {
+				collection.messages[kept] = message
+				kept++
+			} else if !message.timestamp.Before(start) {
+				summary.DataHashes = append(summary.DataHashes, message.hashes...)
+			}
+		}
+		collection.messages = collection.messages[:kept]
+		if end.After(collection.cut) {
+			collection.cut = end
+		}
+
+		if len(summary.DataHashes) > 0 {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
 }
 
-func runSubscription(req Request, buffer []byte, summary *Summary, stopChannel chan struct{}) {
+// sort orders the messages by timestamp, then by their first hash for messages of the same time.
+func (c *collection) sort() {
+	sort.SliceStable(c.messages, func(i, j int) bool {
+		a, b := c.messages[i], c.messages[j]
+		if !a.timestamp.Equal(b.timestamp) {
+			return a.timestamp.Before(b.timestamp)
+		}
+		return bytes.Compare(a.hashes[0].Bytes(), b.hashes[0].Bytes()) < 0
+	})
+}
+
+// runSubscription hashes every normalized message of a request into the collection, a message longer than chunkSize
+// into one CID per chunkSize bytes from its start. CIDs only depend on the messages, so collectors of the same
+// datasource agree on the messages they both saw whenever each of them connected or cut.
+func runSubscription(req Request, chunkSize int, collection *collection, collectionLock *sync.Mutex, stopChannel chan struct{}) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if buffer == nil {
-		panic("Buffer is nil dummy.")
-	}
-	if len(buffer) < 100 {
-		panic("Buffer is too small, is this an error?")
+	if chunkSize < 100 {
+		panic("Chunk size is too small, is this an error?")
 	}
 
-	messageChannel, err := Subscribe(ctx, req.Source, req.Source.Topics[req.Topic])
+	topic := req.Source.Topics()[req.Topic]
+	messageChannel, err := Subscribe(ctx, req.Source, topic)
 
 	// TODO: If there's an error connecting to a source tell caller to avoid wasting collector slot on empty data.
 	if err != nil {
@@ -73,65 +193,71 @@ func runSubscription(req Request, buffer []byte, summary *Summary, stopChannel c
 		return
 	}
 
-	bufferOffset := 0
-	summary.Request = req
+	collectionLock.Lock()
+	collection.request = req
+	collection.messages = nil
+	collectionLock.Unlock()
 
+	cidBuilder := cid.V1Builder{
+		Codec:    uint64(multicodec.DagPb),
+		MhType:   uint64(multicodec.Sha2_256),
+		MhLength: -1,
+	}
 	// XXX: Maybe implement this function in RP?
-	bufferSummaryAppend := func(summary *Summary, buffer []byte, length int) {
-		// TODO: Consider adding:
-		//	- Timestamp.
-		//	- Fragmentation flag (Whether there is a half message or not).
-		//	- Message count.
-
-		cidBuilder := cid.V1Builder{
-			Codec:    uint64(multicodec.DagPb),
-			MhType:   uint64(multicodec.Sha2_256),
-			MhLength: -1,
+	collect := func(message []byte) {
+		timestamp, err := req.Source.Timestamp(message)
+		if err != nil {
+			// Without a timestamp there is no summary every collector would put the message in.
+			log.Debugf("Dropping a message of %s without a timestamp: %s", req.Datasource(), err.Error())
+			return
 		}
 
-		c, err := cidBuilder.Sum(buffer[0:length])
-		if err != nil {
-			// If this fails to parse a buffer the input is invalid.
-			panic(err)
+		// Messages are never joined: where a run of them would be cut depends on when the collector started.
+		var hashes []cid.Cid
+		for {
+			chunk := message
+			if len(chunk) > chunkSize {
+				chunk = chunk[:chunkSize]
+			}
+			c, err := cidBuilder.Sum(chunk)
+			if err != nil {
+				// If this fails to parse a buffer the input is invalid.
+				panic(err)
+			}
+			hashes = append(hashes, c)
+			message = message[len(chunk):]
+			if len(message) == 0 {
+				break
+			}
 		}
 
-		summary.DataHashes = append(summary.DataHashes, c)
+		collectionLock.Lock()
+		defer collectionLock.Unlock()
+		if timestamp.Before(collection.cut) {
+			log.Debugf("Dropping a message of %s from %v, its summary was cut already", req.Datasource(), timestamp)
+			return
+		}
+		collection.messages = append(collection.messages, collected{timestamp: timestamp, hashes: hashes})
 	}
 
 	for {
 		select {
 		case <-stopChannel:
-			// TODO: Flush buffer?
-
+			if err := req.Source.Unsubscribe(ctx, topic); err != nil {
+				log.Errorf("Failed to unsubscribe from %s: %s", req.Datasource(), err.Error())
+			}
 			cancel()
 			return
-		case message := <-messageChannel:
-			// Got a message, add it to buffer.
-
-			// We do a for loop here because the message itself might be bigger than the total size of the buffer.
-			if bufferOffset+len(message) > len(buffer) {
-				// Buffer would be filled past total size, convert to cid and push to summary pointer.
-				// This might introduce a problem. Lets say we store 1kb buffer:
-				//	If a source puts out less than 1kb per blocktime, then it won't get registered at all!
-				//	Might have to flush the buffer on stopChannel event, to preserve that data.
-
-				// TODO: Add to Resource Pool at this stage?
-				bufferSummaryAppend(summary, buffer, bufferOffset)
-				bufferOffset = 0
-			}
-
-			// If the message still doesn't fit, divide it into chunks and add it until it fits.
-			for len(message) > len(buffer) {
-				// XXX: Should the cids we post be capped at the length of the buffer?
-				// Or can they be any size? For now I assume they are capped at the size of the buffer.
-				bufferSummaryAppend(summary, message, len(buffer))
-				message = message[len(buffer):]
+		case message, ok := <-messageChannel:
+			if !ok {
+				// TODO: Tell caller the source is down, so it can collect another one.
+				log.Errorf("Subscription to %s ended: %v", req.Datasource(), req.Source.Health())
+				messageChannel = nil
+				continue
 			}
 
-			// Add message to buffer.
-			copy(buffer[bufferOffset:], message)
-
-			bufferOffset += len(message)
+			// TODO: Add to Resource Pool at this stage?
+			collect(message)
 		}
 	}
 }
@@ -142,41 +268,53 @@ func (collectorInstance *CollectorInstance) Start() {
 	go func() {
 		// XXX: Might have to remake the waitgroup on every iteration.
 		var wg conc.WaitGroup
-		stopChannel := make(chan struct{})
+		var stopChannel chan struct{}
 		for {
 			select {
 			case <-collectorInstance.stop:
+				if stopChannel != nil {
+					close(stopChannel)
+					wg.Wait()
+				}
 				return
 			case <-collectorInstance.requestNotifyChannel:
 				// Wait for a request to be submitted.
 				// Once they are submitted, we act on it by launching a collector.
-				if collectorInstance.requestsByPriorityNew != nil {
+				if stopChannel != nil {
 					// Stop all subscriptions running currently.
 
 					log.Info("Stopping subscriptions...")
-					stopChannel <- struct{}{}
-					// Have to wait here to avoid race condition when touching summariesLatest data.
+					// Closing reaches every subscription, a single send would only stop one of them.
+					close(stopChannel)
+					// Have to wait here to avoid race condition when touching the collections.
 					wg.Wait()
 
 					log.Info("Stopped subscriptions!")
 
-					// Window to fetch the requests.
-					{
-						// Do I send them as transactions?
-						// Do I wait for someone to request them?
-					}
+					// Summaries are handed over by CutSummaries, whatever is not cut
+					// yet is dropped when the subscriptions restart below.
+				}
+
+				// No subscription runs, the collections can be resized to the current limits.
+				collectorInstance.summariesLock.Lock()
+				connectionsMax, bufferSize := collectorInstance.connectionsMax, collectorInstance.bufferSize
+				if len(collectorInstance.collections) != connectionsMax {
+					collectorInstance.collections = make([]collection, connectionsMax)
 				}
+				collectorInstance.summariesLock.Unlock()
 
 				// Go through all the available connections and launch a new subscription goroutine for each of them.
 				log.Info("Adding sources...")
-				for i := 0; i < CONNECTIONS_MAX; i++ {
+				stopChannel = make(chan struct{})
+				for i := 0; i < connectionsMax && i < len(collectorInstance.requestsByPriorityNew); i++ {
 					req := collectorInstance.requestsByPriorityNew[i]
-					buffer := make([]byte, 1024*4)
-					wg.Go(func() { runSubscription(req, buffer, &collectorInstance.summariesLatest[i], stopChannel) })
+					collection := &collectorInstance.collections[i]
+					stop := stopChannel
+					wg.Go(func() { runSubscription(req, bufferSize, collection, &collectorInstance.summariesLock, stop) })
 				}
 
 				// Move new to current.
-				copy(collectorInstance.requestsByPriorityCurrent, collectorInstance.requestsByPriorityNew)
+				collectorInstance.requestsByPriorityCurrent = collectorInstance.requestsByPriorityNew
 				collectorInstance.requestsByPriorityNew = nil
 			}
 		}
@@ -184,5 +322,5 @@ func (collectorInstance *CollectorInstance) Start() {
 }
 
 func (collectorInstance *CollectorInstance) Stop() {
-	collectorInstance.stop <- struct{}{}
+	close(collectorInstance.stop)
 }
@@ -1,10 +1,129 @@
 package collector
 
 import (
+    "bytes"
+    "context"
+    "strings"
     "testing"
+    "time"
+
+    "github.com/ipfs/go-cid"
+    log "github.com/openmesh-network/core/internal/logger"
+    "github.com/stretchr/testify/assert"
 )
 
+// fakeSource passes on the messages sent to it, a message starts with its Unix time in seconds and a space
+type fakeSource struct {
+    messages chan []byte
+}
+
+func (f *fakeSource) Name() string                      { return "fake" }
+func (f *fakeSource) Topics() []string                  { return []string{"trades"} }
+func (f *fakeSource) Connect(ctx context.Context) error { return nil }
+func (f *fakeSource) Subscribe(ctx context.Context, topic string) (<-chan []byte, <-chan error, error) {
+    return f.messages, make(chan error), nil
+}
+func (f *fakeSource) Unsubscribe(ctx context.Context, topic string) error { return nil }
+func (f *fakeSource) Health() error                                       { return nil }
+func (f *fakeSource) Normalize(message []byte) ([]byte, error)            { return message, nil }
+func (f *fakeSource) Timestamp(message []byte) (time.Time, error) {
+    seconds, _, _ := strings.Cut(string(message), " ")
+    return parseTimestamp(seconds)
+}
+
 func TestBasic(t *testing.T) {
     //collector := New()
 
 }
+
+func TestCutSummaries(t *testing.T) {
+    collector := New()
+    hash := func(data string) cid.Cid {
+        c, err := cid.V1Builder{Codec: cid.DagProtobuf, MhType: 0x12}.Sum([]byte(data))
+        assert.NoError(t, err)
+        return c
+    }
+    at := func(seconds int64, data string) collected {
+        return collected{timestamp: time.Unix(seconds, 0), hashes: []cid.Cid{hash(data)}}
+    }
+
+    req, ok := RequestFor("binance/btc.eth")
+    assert.True(t, ok)
+    // Messages arrive out of order, summaries follow their timestamps
+    collector.collections[0] = collection{request: req, messages: []collected{at(12, "c"), at(10, "a"), at(5, "old"), at(11, "b"), at(20, "next")}}
+    assert.Equal(t, "binance/btc.eth", collector.FetchSummaries()[0].Request.Datasource())
+
+    // Every hash is handed out once, what is older than the window is dropped
+    summaries := collector.CutSummaries(time.Unix(10, 0), time.Unix(20, 0))
+    assert.Len(t, summaries, 1)
+    assert.Equal(t, []cid.Cid{hash("a"), hash("b"), hash("c")}, summaries[0].DataHashes)
+    assert.Empty(t, collector.CutSummaries(time.Unix(10, 0), time.Unix(20, 0)))
+    assert.Equal(t, []cid.Cid{hash("next")}, collector.FetchSummaries()[0].DataHashes)
+    assert.Equal(t, time.Unix(20, 0), collector.collections[0].cut)
+}
+
+func TestCollectorsAgreeWhenStartedApart(t *testing.T) {
+    defer func(info func(...interface{})) { log.Info = info }(log.Info)
+    log.Info = t.Log
+    defer func(debugf func(string, ...interface{})) { log.Debugf = debugf }(log.Debugf)
+    log.Debugf = t.Logf
+    messages := [][]byte{
+        []byte("1 small"),
+        append([]byte("2 "), bytes.Repeat([]byte("a"), 150)...),
+        []byte("3 trade 1"),
+        append([]byte("4 "), bytes.Repeat([]byte("b"), 250)...),
+        []byte("5 trade 2"),
+        []byte("6 trade 3"),
+        append([]byte("7 "), bytes.Repeat([]byte("c"), 90)...),
+        []byte("8 "),
+        []byte("9 trade 4"),
+        []byte("no timestamp"),
+    }
+
+    start := func() (*CollectorInstance, *fakeSource) {
+        source := &fakeSource{messages: make(chan []byte)}
+        collector := New()
+        collector.SetLimits(1, 100)
+        collector.Start()
+        collector.SubmitRequests([]Request{{Source: source, Topic: 0}})
+        return collector, source
+    }
+    hashes := func(collector *CollectorInstance, count int) []cid.Cid {
+        var hashes []cid.Cid
+        assert.Eventually(t, func() bool {
+            hashes = collector.FetchSummaries()[0].DataHashes
+            return len(hashes) >= count
+        }, time.Second, time.Millisecond)
+        return hashes
+    }
+
+    // The first collector sees every message, the second one starts in the middle of the stream
+    first, firstSource := start()
+    defer first.Stop()
+    for _, message := range messages[:3] {
+        firstSource.messages <- message
+    }
+    // The first collector cuts a summary in between, the second one never does
+    hashes(first, 4)
+    summaries := first.CutSummaries(time.Unix(0, 0), time.Unix(3, 0))
+    assert.Len(t, summaries, 1)
+    assert.Len(t, summaries[0].DataHashes, 3)
+
+    // The second collector receives the messages in another order
+    second, secondSource := start()
+    defer second.Stop()
+    for i := range messages[3:] {
+        firstSource.messages <- messages[3+i]
+        secondSource.messages <- messages[len(messages)-1-i]
+    }
+
+    // A message is hashed on its own, one CID per 100 bytes of it, a message without a timestamp is dropped
+    hashes(first, 9)
+    hashes(second, 8)
+    firstSummaries := first.CutSummaries(time.Unix(4, 0), time.Unix(10, 0))
+    secondSummaries := second.CutSummaries(time.Unix(4, 0), time.Unix(10, 0))
+    assert.Len(t, firstSummaries, 1)
+    assert.Len(t, firstSummaries[0].DataHashes, 8)
+    assert.Len(t, secondSummaries, 1)
+    assert.Equal(t, firstSummaries[0].DataHashes, secondSummaries[0].DataHashes)
+}
@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// rpcSource polls the latest block of an Ethereum JSON-RPC endpoint, every new block is a message in RLP.
+// It has a single topic, the empty one.
+type rpcSource struct {
+	name string
+	url  string
+
+	lock   sync.Mutex
+	client *ethclient.Client
+	cancel context.CancelFunc // Stops polling
+	err    error              // Why polling stopped
+}
+
+// newRPCSource returns the constructor of an Ethereum JSON-RPC source, for Register
+func newRPCSource(name string, url string) NewSource {
+	return func() Source {
+		return &rpcSource{name: name, url: url}
+	}
+}
+
+func (s *rpcSource) Name() string {
+	return s.name
+}
+
+func (s *rpcSource) Topics() []string {
+	return []string{""}
+}
+
+func (s *rpcSource) Connect(ctx context.Context) error {
+	client, err := ethclient.DialContext(ctx, s.url)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.client, s.err = client, nil
+	s.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		client.Close()
+	}()
+	return nil
+}
+
+func (s *rpcSource) Subscribe(ctx context.Context, topic string) (<-chan []byte, <-chan error, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.client == nil {
+		return nil, nil, errNotConnected
+	}
+	if topic != "" {
+		return nil, nil, fmt.Errorf("%s has no topic %q", s.name, topic)
+	}
+	client := s.client
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	msgChannel := make(chan []byte)
+	errChannel := make(chan error, 1)
+
+	stop := func(err error) {
+		s.lock.Lock()
+		s.err = err
+		s.lock.Unlock()
+		errChannel <- err
+	}
+
+	go func() {
+		defer close(msgChannel)
+		defer close(errChannel)
+		buffer := bytes.NewBuffer(make([]byte, 1024000))
+		headerPrevious := common.Hash{}
+
+		// 1 block per second + request delay is roughly alright since new blocks take ~11 seconds.
+		// Ankr gives us 20 requests per second with their RPC, so we're also not exhausting that.
+		timeTicker := time.NewTicker(time.Second)
+		defer timeTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Quit gracefully, the connection closes with ctx.
+				return
+			case <-timeTicker.C:
+				// XXX: This might add 2 seconds to shutdown. It's unfortunate, but it guarantees error checks below
+				// actually error on the state of the request, not the parent's context.
+				ctxToPreventHanging, cancel := context.WithTimeout(context.Background(), time.Second*2)
+				block, err := client.BlockByNumber(ctxToPreventHanging, nil)
+				cancel()
+				if err != nil {
+					stop(err)
+					return
+				}
+
+				headerProspective := block.Header().Hash()
+				if headerPrevious != headerProspective {
+					buffer.Reset()
+					headerPrevious = headerProspective
+					if err := block.EncodeRLP(buffer); err != nil {
+						stop(err)
+						return
+					}
+					select {
+					case msgChannel <- append([]byte{}, buffer.Bytes()...):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return msgChannel, errChannel, nil
+}
+
+func (s *rpcSource) Unsubscribe(ctx context.Context, topic string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *rpcSource) Health() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client == nil {
+		return errNotConnected
+	}
+	return s.err
+}
+
+func (s *rpcSource) Normalize(message []byte) ([]byte, error) {
+	return message, nil
+}
+
+// Timestamp returns the time of the block
+func (s *rpcSource) Timestamp(message []byte) (time.Time, error) {
+	var block ethtypes.Block
+	if err := rlp.DecodeBytes(message, &block); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(block.Time()), 0), nil
+}
@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	openseaSdk "github.com/721tools/stream-api-go/sdk"
+	log "github.com/openmesh-network/core/internal/logger"
+)
+
+// notifyService is the stream of the OpenSea SDK, whose type is unexported
+type notifyService interface {
+	Subscribe(slug, event string, fn openseaSdk.MsgHandlerFanc) (openseaSdk.UnSubscribeHandlerFanc, error)
+	Start() error
+	Stop() error
+}
+
+// openseaSubscription is where the events of a subscribed topic go until its context is done
+type openseaSubscription struct {
+	ctx      context.Context
+	messages chan []byte
+}
+
+// openseaSource streams the events of every collection through the OpenSea SDK, its topics are event types.
+// The SDK only joins the stream when it starts, so Connect registers every event type and starts it once,
+// subscriptions then pick the events they want.
+// Opensea Request structure: {topic: \ event: \ payload:{} \ ref: }
+type openseaSource struct {
+	newNotifyService func(key string) notifyService
+
+	lock          sync.Mutex
+	ns            notifyService
+	subscriptions map[string]*openseaSubscription // Topic -> subscription
+}
+
+func newOpenseaSource() Source {
+	return &openseaSource{
+		newNotifyService: func(key string) notifyService {
+			return openseaSdk.NewNotifyService(openseaSdk.MAIN_NET, key)
+		},
+		subscriptions: make(map[string]*openseaSubscription),
+	}
+}
+
+func (s *openseaSource) Name() string {
+	return "opensea"
+}
+
+func (s *openseaSource) Topics() []string {
+	return []string{"item_listed", "item_cancelled", "item_sold", "item_transferred", "item_received_offer", "item_received_bid"}
+}
+
+func (s *openseaSource) Connect(ctx context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ns != nil {
+		return nil
+	}
+
+	key, err := apiKey(s.Name(), "OPENSEA_API_KEY")
+	if err != nil {
+		return err
+	}
+	log.Infof("Found OpenSea API Key")
+
+	ns := s.newNotifyService(key)
+	for _, topic := range s.Topics() {
+		if _, err := ns.Subscribe("*", topic, s.dispatch(topic)); err != nil {
+			return err
+		}
+	}
+	s.ns = ns
+
+	// Joins the stream once connected, it retries until it is
+	go ns.Start()
+	go func() {
+		<-ctx.Done()
+		log.Infof("Context is done, stopping the OpenSea stream")
+		ns.Stop()
+	}()
+	return nil
+}
+
+// dispatch returns the handler of the events of a topic, it passes them on to the subscription of the topic if any
+func (s *openseaSource) dispatch(topic string) openseaSdk.MsgHandlerFanc {
+	return func(msg *openseaSdk.Message) error {
+		s.lock.Lock()
+		subscription := s.subscriptions[topic]
+		s.lock.Unlock()
+		if subscription == nil {
+			return nil
+		}
+
+		bmsg, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		select {
+		case subscription.messages <- bmsg:
+		case <-subscription.ctx.Done():
+			return subscription.ctx.Err()
+		}
+		return nil
+	}
+}
+
+func (s *openseaSource) Subscribe(ctx context.Context, topic string) (<-chan []byte, <-chan error, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ns == nil {
+		return nil, nil, errNotConnected
+	}
+
+	subscription := &openseaSubscription{ctx: ctx, messages: make(chan []byte, 1000)}
+	s.subscriptions[topic] = subscription
+
+	// The SDK reconnects on its own, the stream only ends with ctx
+	errChannel := make(chan error)
+	go func() {
+		<-ctx.Done()
+		s.lock.Lock()
+		if s.subscriptions[topic] == subscription {
+			delete(s.subscriptions, topic)
+		}
+		s.lock.Unlock()
+		close(errChannel)
+	}()
+	return subscription.messages, errChannel, nil
+}
+
+func (s *openseaSource) Unsubscribe(ctx context.Context, topic string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.subscriptions, topic)
+	return nil
+}
+
+func (s *openseaSource) Health() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.ns == nil {
+		return errNotConnected
+	}
+	return nil
+}
+
+func (s *openseaSource) Normalize(message []byte) ([]byte, error) {
+	return message, nil
+}
+
+func (s *openseaSource) Timestamp(message []byte) (time.Time, error) {
+	return jsonTimestamp(message, "payload.payload.event_timestamp")
+}
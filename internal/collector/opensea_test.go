@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	openseaSdk "github.com/721tools/stream-api-go/sdk"
+	"github.com/openmesh-network/core/internal/config"
+	log "github.com/openmesh-network/core/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifyService records the handlers of the OpenSea SDK instead of connecting
+type fakeNotifyService struct {
+	lock     sync.Mutex
+	handlers map[string]openseaSdk.MsgHandlerFanc
+	starts   int
+	stopped  chan struct{}
+}
+
+func (f *fakeNotifyService) Subscribe(slug, event string, fn openseaSdk.MsgHandlerFanc) (openseaSdk.UnSubscribeHandlerFanc, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.handlers[event] = fn
+	return func() {}, nil
+}
+
+func (f *fakeNotifyService) Start() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.starts++
+	return nil
+}
+
+func (f *fakeNotifyService) Stop() error {
+	close(f.stopped)
+	return nil
+}
+
+func TestOpenseaSource(t *testing.T) {
+	defer func(infof func(string, ...interface{})) { log.Infof = infof }(log.Infof)
+	log.Infof = t.Logf
+	require.NoError(t, Configure(config.CollectorConfig{ApiKeys: map[string]string{"opensea": "key"}}))
+
+	ns := &fakeNotifyService{handlers: make(map[string]openseaSdk.MsgHandlerFanc), stopped: make(chan struct{})}
+	source := newOpenseaSource().(*openseaSource)
+	source.newNotifyService = func(key string) notifyService {
+		assert.Equal(t, "key", key)
+		return ns
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.ErrorIs(t, source.Health(), errNotConnected)
+	require.NoError(t, source.Connect(ctx))
+	require.NoError(t, source.Connect(ctx))
+	sold, soldErrs, err := source.Subscribe(ctx, "item_sold")
+	require.NoError(t, err)
+	subCtx, unsubscribe := context.WithCancel(ctx)
+	_, listedErrs, err := source.Subscribe(subCtx, "item_listed")
+	require.NoError(t, err)
+
+	// Every event type is registered before the stream starts, and it starts once
+	ns.lock.Lock()
+	assert.Len(t, ns.handlers, len(source.Topics()))
+	ns.lock.Unlock()
+	assert.Eventually(t, func() bool {
+		ns.lock.Lock()
+		defer ns.lock.Unlock()
+		return ns.starts == 1
+	}, time.Second, time.Millisecond)
+
+	// Events go to the subscription of their type only
+	assert.NoError(t, ns.handlers["item_transferred"](&openseaSdk.Message{Event: "item_transferred"}))
+	soldAt := "2023-09-09T09:26:03.123456+00:00"
+	payload := openseaSdk.PayloadJson{Payload: &openseaSdk.ItemSoldRes{EventTimestamp: soldAt}}
+	assert.NoError(t, ns.handlers["item_sold"](&openseaSdk.Message{Event: "item_sold", Payload: payload}))
+	message := <-sold
+	assert.Contains(t, string(message), "item_sold")
+
+	// Summaries are cut on the time of the event
+	timestamp, err := source.Timestamp(message)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 9, 9, 9, 26, 3, 123456000, time.UTC).UnixNano(), timestamp.UnixNano())
+
+	// The error channel of a subscription closes with its context, the stream stops with the one of Connect
+	unsubscribe()
+	_, ok := <-listedErrs
+	assert.False(t, ok)
+	cancel()
+	_, ok = <-soldErrs
+	assert.False(t, ok)
+	<-ns.stopped
+}
@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/openmesh-network/core/internal/config"
+	log "github.com/openmesh-network/core/internal/logger"
+)
+
+// Source is a stream of data the collector subscribes to. A source is added by registering it from its own file,
+// see sources.go, or from the configuration, see Configure.
+type Source interface {
+	// Name and topics make up the datasources verification transactions refer to, e.g. "binance/btc.eth".
+	Name() string
+	Topics() []string
+
+	// Connect opens the connection to the source, it is closed once ctx is done.
+	Connect(ctx context.Context) error
+	// Subscribe returns the messages of the topic, and the error the subscription ended with.
+	// The collector connects every subscription on its own.
+	Subscribe(ctx context.Context, topic string) (<-chan []byte, <-chan error, error)
+	Unsubscribe(ctx context.Context, topic string) error
+	// Health returns why the connection is not usable, nil while it is.
+	Health() error
+	// Normalize turns a message into the bytes that are hashed, every node must hash the same bytes for the same data.
+	Normalize(message []byte) ([]byte, error)
+	// Timestamp returns when the data of a normalized message happened according to the source. Summaries are cut
+	// on it, so every node puts a message in the same summary whenever it received it.
+	Timestamp(message []byte) (time.Time, error)
+}
+
+// NewSource returns a source that is not connected yet
+type NewSource func() Source
+
+var registry = struct {
+	sync.RWMutex
+	names   []string // In registration order
+	sources map[string]NewSource
+	apiKeys map[string]string
+}{sources: make(map[string]NewSource)}
+
+// Register adds a source to the ones the collector supports. It panics if a source of the same name was registered,
+// it is meant to be called from init.
+func Register(newSource NewSource) {
+	if err := register(newSource); err != nil {
+		panic(err)
+	}
+}
+
+func register(newSource NewSource) error {
+	name := newSource().Name()
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, found := registry.sources[name]; found {
+		return fmt.Errorf("source %s is registered twice", name)
+	}
+	registry.names = append(registry.names, name)
+	registry.sources[name] = newSource
+	return nil
+}
+
+// Lookup returns a new source of the given name, not connected yet
+func Lookup(name string) (Source, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	newSource, found := registry.sources[name]
+	if !found {
+		return nil, false
+	}
+	return newSource(), true
+}
+
+// Sources returns the names of the registered sources, in registration order
+func Sources() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]string{}, registry.names...)
+}
+
+// Configure registers the websocket sources of the configuration and sets the API keys of the sources.
+// It is called before the collector starts.
+func Configure(conf config.CollectorConfig) error {
+	registry.Lock()
+	registry.apiKeys = conf.ApiKeys
+	registry.Unlock()
+
+	for _, sourceConf := range conf.Sources {
+		source, err := NewWebsocketSource(sourceConf)
+		if err != nil {
+			return err
+		}
+		if err := register(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apiKey returns the API key of a source from the configuration, or else from the environment (or a .env file)
+func apiKey(source string, envKey string) (string, error) {
+	registry.RLock()
+	key := registry.apiKeys[source]
+	registry.RUnlock()
+	if key != "" {
+		return key, nil
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Debugf("No .env file found: %v", err)
+	}
+	if key := os.Getenv(envKey); key != "" {
+		return key, nil
+	}
+	return "", fmt.Errorf("no API key for %s, set collector.apiKeys.%s or %s", source, source, envKey)
+}
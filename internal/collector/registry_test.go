@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// namedSource is a fakeSource registered under its own name
+type namedSource struct {
+	fakeSource
+	name string
+}
+
+func (s *namedSource) Name() string { return s.name }
+
+func TestRegisterLookup(t *testing.T) {
+	created := 0
+	newSource := func() Source {
+		created++
+		return &namedSource{name: "registry-test"}
+	}
+	Register(newSource)
+	assert.Panics(t, func() { Register(newSource) })
+	assert.Equal(t, "registry-test", Sources()[len(Sources())-1])
+
+	// Every lookup returns a new source
+	first, found := Lookup("registry-test")
+	assert.True(t, found)
+	second, _ := Lookup("registry-test")
+	assert.NotSame(t, first, second)
+	assert.Equal(t, "registry-test", first.Name())
+
+	_, found = Lookup("no-such-source")
+	assert.False(t, found)
+
+	// Sources returns a copy
+	names := Sources()
+	names[0] = "changed"
+	assert.NotEqual(t, "changed", Sources()[0])
+}
+
+func TestConfigureApiKeys(t *testing.T) {
+	t.Setenv("REGISTRY_KEYS_API_KEY", "from-env")
+	assert.NoError(t, Configure(config.CollectorConfig{ApiKeys: map[string]string{"configured": "from-config"}}))
+	t.Cleanup(func() {
+		registry.Lock()
+		registry.apiKeys = nil
+		registry.Unlock()
+	})
+
+	// The configuration comes first, then the environment
+	key, err := apiKey("configured", "REGISTRY_KEYS_API_KEY")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-config", key)
+	key, err = apiKey("registry-keys", "REGISTRY_KEYS_API_KEY")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", key)
+	_, err = apiKey("registry-keys", "REGISTRY_MISSING_API_KEY")
+	assert.Error(t, err)
+
+	// A source of the configuration is registered, an invalid one fails the configuration
+	source := config.SourceConfig{Name: "registry-configured", URL: "wss://example.com", Topics: []string{"a"},
+		Subscribe: "{{topic}}", Timestamp: "ts"}
+	assert.NoError(t, Configure(config.CollectorConfig{Sources: []config.SourceConfig{source}}))
+	configured, found := Lookup("registry-configured")
+	assert.True(t, found)
+	assert.Equal(t, []string{"a"}, configured.Topics())
+	source.Name, source.Topics = "registry-invalid", nil
+	assert.Error(t, Configure(config.CollectorConfig{Sources: []config.SourceConfig{source}}))
+	_, found = Lookup("registry-invalid")
+	assert.False(t, found)
+}
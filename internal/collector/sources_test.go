@@ -2,23 +2,40 @@ package collector
 
 import (
     "context"
+    "fmt"
     "sync"
     "testing"
     "time"
 )
 
+// join connects a new source of the given name and subscribes to the topic
+func join(ctx context.Context, name string, topic string) (<-chan []byte, <-chan error, error) {
+    source, found := Lookup(name)
+    if !found {
+        return nil, nil, fmt.Errorf("no source %s", name)
+    }
+    if err := source.Connect(ctx); err != nil {
+        return nil, nil, err
+    }
+    return source.Subscribe(ctx, topic)
+}
+
 func TestSourcesTableSanity(t *testing.T) {
-    // TODO: Want acceptance tests on the Sources table. ie go through the whole table, verify against rules, and test the source's symbols are up.
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
+    // TODO: Want acceptance tests on the registered sources. ie go through the whole table, verify against rules, and test the source's symbols are up.
     // This doesn't work yet since we're not checking error returns from sources completely.
     // This implements the minimum check to make sure we our API calls are getting responses basically.
     // A better way to implement this would be to make sure we receive a few messages or get some minimum amount of bytes transfered.
     var wg sync.WaitGroup
-    checkTopics := func(source Source) {
-        for i := range source.Topics {
-            t.Log("Checking:", source.Name, source.Topics[i])
+    checkTopics := func(name string) {
+        source, _ := Lookup(name)
+        for _, topic := range source.Topics() {
+            t.Log("Checking:", name, topic)
             ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
             defer cancel()
-            _, errChan, err := source.JoinFunc(ctx, source, source.Topics[i])
+            _, errChan, err := join(ctx, name, topic)
 
             if err != nil {
                 t.Error(err)
@@ -33,22 +50,24 @@ func TestSourcesTableSanity(t *testing.T) {
         wg.Done()
     }
 
-    for _, source := range Sources {
+    for _, name := range Sources() {
         wg.Add(1)
         t.Log("Running some code")
-        go checkTopics(source)
+        go checkTopics(name)
     }
 
     wg.Wait()
 }
 
 func TestBinanceJoin(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
     t.Log("Got here no issue")
-    t.Log(Sources[2].Topics[0])
-    msgChan, errChan, err := defaultJoinCEX(ctx, Sources[2], Sources[2].Topics[0])
+    msgChan, errChan, err := join(ctx, "binance", "btc.eth")
 
     if err != nil {
         t.Error(err)
@@ -71,12 +90,14 @@ func TestBinanceJoin(t *testing.T) {
 }
 
 func TestAnkrJoin(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
     t.Log("Got here no issue")
-    t.Log(Sources[4].Topics[0])
-    msgChan, errChan, err := ankrJoinRPC(ctx, Sources[4], Sources[4].Topics[0])
+    msgChan, errChan, err := join(ctx, "ethereum-ankr-rpc", "")
 
     if err != nil {
         t.Error(err)
@@ -98,12 +119,14 @@ func TestAnkrJoin(t *testing.T) {
 }
 
 func TestByBit(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
     t.Log("Running ByBit collector!!!")
-    t.Log(Sources[3].Topics[0])
-    msgChan, errChan, err := defaultJoinCEX(ctx, Sources[3], Sources[3].Topics[0])
+    msgChan, errChan, err := join(ctx, "bybit", "orderbook.50.BTCUSDT")
 
     if err != nil {
         t.Error(err)
@@ -124,12 +147,14 @@ func TestByBit(t *testing.T) {
 }
 
 func TestOKX(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
     t.Log("Running OKX collector!!!")
-    t.Log(Sources[4].Topics[1])
-    msgChan, errChan, err := Sources[4].JoinFunc(ctx, Sources[4], Sources[4].Topics[1])
+    msgChan, errChan, err := join(ctx, "okx", "sprd-books5")
 
     if err != nil {
         t.Error(err)
@@ -150,17 +175,18 @@ func TestOKX(t *testing.T) {
 }
 
 func TestOpenSea(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     // Note(Tom): Have to disable this test since I don't have Opensea Creds.
 
-    // topic := Sources[3].Topics[0]
+    // topic := "item_listed"
     // t.Logf("Using topic: %s", topic)
-    // sourceUrl := Sources[3].ApiURL
-    // t.Logf("Using source url: %s", sourceUrl)
 
     // ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
     // defer cancel()
 
-    // msgChan, errChan, err := defaultJoinNFTCEX(ctx, Sources[3], topic)
+    // msgChan, errChan, err := join(ctx, "opensea", topic)
     // if err != nil {
     // 	t.Fatalf("Failed to join NFT CEX: %v", err)
     // }
@@ -172,7 +198,7 @@ func TestOpenSea(t *testing.T) {
     // 		t.Logf("Received message: %s", string(msg))
     // 		receivedMessages++
     // 	case err := <-errChan:
-    // 		t.Fatalf("Error received from defaultJoinNFTCEX: %v", err)
+    // 		t.Fatalf("Error received from join: %v", err)
     // 	case <-ctx.Done():
     // 		t.Logf("Context canceled or timed out")
     // 		return
@@ -188,12 +214,14 @@ func TestOpenSea(t *testing.T) {
 }
 
 func TestAnkrPolygonJoin(t *testing.T) {
+    if testing.Short() {
+        t.Skip("connects to the live source")
+    }
     ctx, cancel := context.WithCancel(context.Background())
     defer cancel()
 
     t.Log("Got here no issue")
-    t.Log(Sources[4].Topics[0])
-    msgChan, errChan, err := ankrJoinRPC(ctx, Sources[5], Sources[5].Topics[0])
+    msgChan, errChan, err := join(ctx, "polygon-ankr-rpc", "")
 
     if err != nil {
         t.Error(err)
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonTimestamp returns the timestamp of a JSON message found at the given path, whose parts are object keys or
+// array indexes separated by dots, e.g. "data.0.ts".
+func jsonTimestamp(message []byte, path string) (time.Time, error) {
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return time.Time{}, err
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[part]
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return time.Time{}, fmt.Errorf("no %s in the message", path)
+			}
+			value = v[i]
+		default:
+			return time.Time{}, fmt.Errorf("no %s in the message", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return parseTimestamp(v.String())
+	case string:
+		return parseTimestamp(v)
+	default:
+		return time.Time{}, fmt.Errorf("no %s in the message", path)
+	}
+}
+
+// parseTimestamp reads a timestamp the way sources write them: RFC 3339, or a Unix time whose unit is told apart by
+// its size, seconds if it has a fraction.
+func parseTimestamp(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch {
+		case n < 1e11:
+			return time.Unix(n, 0), nil
+		case n < 1e14:
+			return time.UnixMilli(n), nil
+		case n < 1e17:
+			return time.UnixMicro(n), nil
+		default:
+			return time.Unix(0, n), nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.UnixMicro(int64(f * 1e6)), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONTimestamp(t *testing.T) {
+	for _, c := range []struct {
+		message string
+		path    string
+		want    time.Time
+	}{
+		{`{"T": 1700000000}`, "T", time.Unix(1700000000, 0)},
+		{`{"T": 1700000000123}`, "T", time.UnixMilli(1700000000123)},
+		{`{"ts": "1700000000123456"}`, "ts", time.UnixMicro(1700000000123456)},
+		{`{"ts": 1700000000123456789}`, "ts", time.Unix(0, 1700000000123456789)},
+		{`{"timestamp": "1700000000.5"}`, "timestamp", time.UnixMilli(1700000000500)},
+		{`{"data": [{"ts": "1700000000123"}]}`, "data.0.ts", time.UnixMilli(1700000000123)},
+		{`{"time": "2023-11-14T22:13:20.123456Z"}`, "time", time.UnixMicro(1700000000123456)},
+	} {
+		timestamp, err := jsonTimestamp([]byte(c.message), c.path)
+		require.NoError(t, err, c.message)
+		assert.Equal(t, c.want.UnixNano(), timestamp.UnixNano(), c.message)
+	}
+
+	for _, c := range []struct {
+		message string
+		path    string
+	}{
+		{`{"result": null, "id": 1}`, "T"},
+		{`{"data": []}`, "data.0.ts"},
+		{`{"data": {"ts": true}}`, "data.ts"},
+		{`{"time": "yesterday"}`, "time"},
+		{`not json`, "time"},
+	} {
+		_, err := jsonTimestamp([]byte(c.message), c.path)
+		assert.Error(t, err, c.message)
+	}
+}
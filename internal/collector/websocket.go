@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openmesh-network/core/internal/config"
+	"nhooyr.io/websocket" // Docs are hard to find: https://pkg.go.dev/nhooyr.io/websocket; Or, use gorilla websockets?
+)
+
+// Placeholders of the requests of a websocket source
+const (
+	topicPlaceholder  = "{{topic}}"
+	apiKeyPlaceholder = "{{apiKey}}"
+)
+
+var errNotConnected = errors.New("not connected")
+
+// websocketSource subscribes to a topic by sending a request templated with the topic, most exchanges work this way.
+// Every message of the connection belongs to the subscription.
+type websocketSource struct {
+	conf        config.SourceConfig
+	messageType websocket.MessageType
+
+	lock     sync.Mutex
+	conn     *websocket.Conn
+	apiKey   string
+	messages chan []byte
+	errs     chan error
+	err      error // Why the connection ended
+}
+
+// NewWebsocketSource checks the configuration of a websocket source and returns its constructor, for Register
+func NewWebsocketSource(conf config.SourceConfig) (NewSource, error) {
+	if conf.Name == "" || strings.Contains(conf.Name, "/") {
+		return nil, fmt.Errorf("invalid source name %q", conf.Name)
+	}
+	if u, err := url.Parse(conf.URL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+		return nil, fmt.Errorf("source %s: invalid websocket URL %q", conf.Name, conf.URL)
+	}
+	if len(conf.Topics) == 0 {
+		return nil, fmt.Errorf("source %s has no topics", conf.Name)
+	}
+	if !strings.Contains(conf.Subscribe, topicPlaceholder) {
+		return nil, fmt.Errorf("source %s: the subscribe request has no %s", conf.Name, topicPlaceholder)
+	}
+	if conf.Timestamp == "" {
+		return nil, fmt.Errorf("source %s has no timestamp field", conf.Name)
+	}
+
+	var messageType websocket.MessageType
+	switch conf.MessageType {
+	case "", "binary":
+		messageType = websocket.MessageBinary
+	case "text":
+		// OKX's WebSocket API requires websocket.MessageText (instead of websocket.Binary)
+		messageType = websocket.MessageText
+	default:
+		return nil, fmt.Errorf("source %s: message type must be text or binary, not %q", conf.Name, conf.MessageType)
+	}
+
+	return func() Source {
+		return &websocketSource{conf: conf, messageType: messageType}
+	}, nil
+}
+
+func (s *websocketSource) Name() string {
+	return s.conf.Name
+}
+
+func (s *websocketSource) Topics() []string {
+	return s.conf.Topics
+}
+
+func (s *websocketSource) needsApiKey() bool {
+	return s.conf.AuthHeader != "" || strings.Contains(s.conf.Subscribe, apiKeyPlaceholder) ||
+		strings.Contains(s.conf.Unsubscribe, apiKeyPlaceholder)
+}
+
+func (s *websocketSource) Connect(ctx context.Context) error {
+	options := &websocket.DialOptions{Subprotocols: s.conf.Subprotocols}
+	var key string
+	if s.needsApiKey() {
+		var err error
+		if key, err = apiKey(s.conf.Name, strings.ToUpper(s.conf.Name)+"_API_KEY"); err != nil {
+			return err
+		}
+		if s.conf.AuthHeader != "" {
+			options.HTTPHeader = http.Header{s.conf.AuthHeader: []string{key}}
+		}
+	}
+
+	conn, resp, err := websocket.Dial(ctx, s.conf.URL, options)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("failed to connect to %s: %s: %w", s.conf.Name, resp.Status, err)
+		}
+		return fmt.Errorf("failed to connect to %s: %w", s.conf.Name, err)
+	}
+
+	s.lock.Lock()
+	s.conn, s.apiKey, s.err = conn, key, nil
+	s.messages = make(chan []byte)
+	s.errs = make(chan error, 1)
+	messages, errs := s.messages, s.errs
+	s.lock.Unlock()
+
+	go func() {
+		defer close(messages)
+		defer close(errs)
+		for {
+			_, message, err := conn.Read(ctx)
+			if err != nil {
+				s.lock.Lock()
+				s.err = err
+				s.lock.Unlock()
+				errs <- err
+				return
+			}
+			select {
+			case messages <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		conn.CloseNow()
+	}()
+	return nil
+}
+
+// request fills a request template in
+func (s *websocketSource) request(template string, topic string) []byte {
+	request := strings.ReplaceAll(template, topicPlaceholder, topic)
+	return []byte(strings.ReplaceAll(request, apiKeyPlaceholder, s.apiKey))
+}
+
+func (s *websocketSource) Subscribe(ctx context.Context, topic string) (<-chan []byte, <-chan error, error) {
+	s.lock.Lock()
+	conn, messages, errs := s.conn, s.messages, s.errs
+	s.lock.Unlock()
+	if conn == nil {
+		return nil, nil, errNotConnected
+	}
+
+	if err := conn.Write(ctx, s.messageType, s.request(s.conf.Subscribe, topic)); err != nil {
+		return nil, nil, err
+	}
+	return messages, errs, nil
+}
+
+func (s *websocketSource) Unsubscribe(ctx context.Context, topic string) error {
+	s.lock.Lock()
+	conn := s.conn
+	s.lock.Unlock()
+	if conn == nil {
+		return errNotConnected
+	}
+	if s.conf.Unsubscribe == "" {
+		// Closing the connection ends the subscription
+		return nil
+	}
+	return conn.Write(ctx, s.messageType, s.request(s.conf.Unsubscribe, topic))
+}
+
+func (s *websocketSource) Health() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		return errNotConnected
+	}
+	return s.err
+}
+
+func (s *websocketSource) Normalize(message []byte) ([]byte, error) {
+	return message, nil
+}
+
+// Timestamp reads the field of the configuration, messages without it are not data, e.g. subscription replies
+func (s *websocketSource) Timestamp(message []byte) (time.Time, error) {
+	return jsonTimestamp(message, s.conf.Timestamp)
+}
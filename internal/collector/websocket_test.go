@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+)
+
+func TestConfiguredWebsocketSource(t *testing.T) {
+	requests := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		for {
+			messageType, request, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			assert.Equal(t, websocket.MessageText, messageType)
+			requests <- string(request)
+			if strings.HasPrefix(string(request), "subscribe") {
+				conn.Write(r.Context(), websocket.MessageText, []byte(`{"trade": {"time": 1700000000123}}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	conf := config.SourceConfig{
+		Name:        "test-exchange",
+		URL:         "ws" + strings.TrimPrefix(server.URL, "http"),
+		Topics:      []string{"BTC-USD", "ETH-USD"},
+		Subscribe:   "subscribe {{topic}} {{apiKey}}",
+		Unsubscribe: "unsubscribe {{topic}}",
+		MessageType: "text",
+		AuthHeader:  "X-Api-Key",
+		Timestamp:   "trade.time",
+	}
+	apiKeys := map[string]string{"test-exchange": "secret"}
+	assert.NoError(t, Configure(config.CollectorConfig{ApiKeys: apiKeys, Sources: []config.SourceConfig{conf}}))
+	assert.Contains(t, Sources(), "test-exchange")
+
+	// Names are unique, built-in sources can't be replaced either
+	assert.Error(t, Configure(config.CollectorConfig{ApiKeys: apiKeys, Sources: []config.SourceConfig{conf}}))
+	binance := conf
+	binance.Name = "binance"
+	assert.Error(t, Configure(config.CollectorConfig{ApiKeys: apiKeys, Sources: []config.SourceConfig{binance}}))
+
+	// Invalid configurations
+	for _, invalid := range []func(*config.SourceConfig){
+		func(c *config.SourceConfig) { c.Name = "" },
+		func(c *config.SourceConfig) { c.Name = "a/b" },
+		func(c *config.SourceConfig) { c.URL = "https://example.com" },
+		func(c *config.SourceConfig) { c.Topics = nil },
+		func(c *config.SourceConfig) { c.Subscribe = "subscribe" },
+		func(c *config.SourceConfig) { c.MessageType = "json" },
+		func(c *config.SourceConfig) { c.Timestamp = "" },
+	} {
+		c := conf
+		c.Name = "other-exchange"
+		invalid(&c)
+		_, err := NewWebsocketSource(c)
+		assert.Error(t, err)
+	}
+
+	req, ok := RequestFor("test-exchange/ETH-USD")
+	assert.True(t, ok)
+	assert.Equal(t, "test-exchange/ETH-USD", req.Datasource())
+	_, ok = RequestFor("test-exchange/SOL-USD")
+	assert.False(t, ok)
+	assert.ErrorIs(t, req.Source.Health(), errNotConnected)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	messages, err := Subscribe(ctx, req.Source, "ETH-USD")
+	require.NoError(t, err)
+	assert.Equal(t, "subscribe ETH-USD secret", <-requests)
+	message := <-messages
+	assert.Equal(t, []byte(`{"trade": {"time": 1700000000123}}`), message)
+	assert.NoError(t, req.Source.Health())
+	timestamp, err := req.Source.Timestamp(message)
+	require.NoError(t, err)
+	assert.Equal(t, time.UnixMilli(1700000000123), timestamp)
+
+	assert.NoError(t, req.Source.Unsubscribe(ctx, "ETH-USD"))
+	assert.Equal(t, "unsubscribe ETH-USD", <-requests)
+
+	// Without the API key the source can't connect
+	registry.Lock()
+	registry.apiKeys = nil
+	registry.Unlock()
+	source, _ := Lookup("test-exchange")
+	assert.Error(t, source.Connect(ctx))
+}
+
+func TestRequestTemplates(t *testing.T) {
+	source := &websocketSource{conf: config.SourceConfig{
+		Subscribe:   `{"op": "subscribe", "args": ["{{topic}}"], "key": "{{apiKey}}", "again": "{{topic}}"}`,
+		Unsubscribe: `{"op": "unsubscribe", "args": ["{{topic}}"]}`,
+	}, apiKey: "secret"}
+	assert.Equal(t, `{"op": "subscribe", "args": ["BTC-USD"], "key": "secret", "again": "BTC-USD"}`,
+		string(source.request(source.conf.Subscribe, "BTC-USD")))
+	assert.Equal(t, `{"op": "unsubscribe", "args": ["BTC-USD"]}`, string(source.request(source.conf.Unsubscribe, "BTC-USD")))
+
+	// The built-in sources send JSON naming the topic
+	for _, conf := range websocketSources {
+		source := &websocketSource{conf: conf}
+		for _, topic := range conf.Topics {
+			request := source.request(conf.Subscribe, topic)
+			assert.True(t, json.Valid(request), "%s: %s", conf.Name, request)
+			assert.Contains(t, string(request), topic)
+			assert.NotContains(t, string(request), "{{")
+		}
+	}
+}
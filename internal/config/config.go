@@ -16,6 +16,9 @@ type config struct {
 	BFT BFTConfig `yaml:"bft"`
 	Log LogConfig `yaml:"log"`
 	DB  DBConfig  `yaml:"db"`
+	API APIConfig `yaml:"api"`
+
+	Collector CollectorConfig `yaml:"collector"`
 }
 
 // P2pConfig is the configuration for libp2p-related instances
@@ -33,11 +36,39 @@ type DBConfig struct {
 	Port     int    `yaml:"port"`     // Database connection port
 	DBName   string `yaml:"dbName"`   // Name for the database used
 	URL      string `yaml:"URL"`      // Database connection URL
+
+	InMemory         bool   `yaml:"inMemory"`         // Keep BadgerDB in memory only, all the state is lost on restart
+	DataDir          string `yaml:"dataDir"`          // Directory BadgerDB persists its data to (ignored if inMemory is set)
+	SyncWrites       bool   `yaml:"syncWrites"`       // Sync every write to disk before acknowledging it
+	ValueLogFileSize int64  `yaml:"valueLogFileSize"` // Megabytes, 0 for BadgerDB's default
+	Compression      string `yaml:"compression"`      // Block compression: none, snappy or zstd (default: snappy)
+	EncryptionKey    string `yaml:"encryptionKey"`    // Hex-encoded AES-128/192/256 key, empty to disable encryption at rest
+	HistoryBlocks    uint64 `yaml:"historyBlocks"`    // Past blocks whose state can still be queried, 0 to keep all of them
 }
 
 // BFTConfig is the configuration for using CometBFT
 type BFTConfig struct {
-	HomeDir string `yaml:"homeDir"` // Path to CometBFT config
+	HomeDir       string                 `yaml:"homeDir"`       // CometBFT home directory, initialised on first run if empty
+	CometBFT      map[string]interface{} `yaml:"cometbft"`      // CometBFT settings, named as in its config.toml, override the config.toml of homeDir
+	Genesis       string                 `yaml:"genesis"`       // genesis.json a new home directory starts from (default: the embedded one)
+	ChainID       string                 `yaml:"chainId"`       // Chain ID of the single validator chain created when there is no genesis at all
+	DiscoverPeers bool                   `yaml:"discoverPeers"` // Announce and find CometBFT peers over libp2p
+	MinGasPrice   uint64                 `yaml:"minGasPrice"`   // Fee per gas, in base units, the mempool requires
+
+	PrivValidator           string `yaml:"privValidator"`           // Where the validator key is kept: file (default), remote or sealed
+	PrivValidatorListenAddr string `yaml:"privValidatorListenAddr"` // remote: tcp:// or unix:// address the remote signer connects to
+	PrivValidatorSealingKey string `yaml:"privValidatorSealingKey"` // sealed: hex-encoded AES-128/192/256 key the validator key and its state are encrypted with
+
+	SnapshotInterval   uint64 `yaml:"snapshotInterval"`   // Take a state sync snapshot every this many blocks, 0 to disable
+	SnapshotKeepRecent uint32 `yaml:"snapshotKeepRecent"` // Number of snapshots to retain, 0 to retain all of them
+	SnapshotDir        string `yaml:"snapshotDir"`        // Where snapshots are stored (default: <homeDir>/data/snapshots)
+	SnapshotChunkSize  int    `yaml:"snapshotChunkSize"`  // Megabytes per chunk, 0 for the default of 4
+}
+
+// APIConfig is the configuration for the node-local transaction API and the tx command
+type APIConfig struct {
+	ListenAddr string `yaml:"listenAddr"` // host:port the API listens on, empty to disable it (keep it on localhost)
+	KeyringDir string `yaml:"keyringDir"` // Directory the tx command keeps its signing keys in
 }
 
 // LogConfig is the configuration for zap logger
@@ -66,8 +97,24 @@ type ErrorLogConfig struct {
 	ToFile     bool   `yaml:"toFile"`     // Log to file or not
 }
 
+// CollectorConfig is the configuration for the sources the collector subscribes to
 type CollectorConfig struct {
-	ApiKeys map[string]string `yaml:"apiKeys"` // API keys for each authenticated source
+	ApiKeys map[string]string `yaml:"apiKeys"` // API keys for each authenticated source, by source name
+	Sources []SourceConfig    `yaml:"sources"` // Websocket sources added to the built-in ones
+}
+
+// SourceConfig is a websocket source loaded from the configuration instead of being built in
+type SourceConfig struct {
+	Name        string   `yaml:"name"`        // Source name, the first part of its datasources, e.g. "binance" in "binance/btc.eth"
+	URL         string   `yaml:"url"`         // wss:// URL of the source
+	Topics      []string `yaml:"topics"`      // Topics that can be subscribed to
+	Subscribe   string   `yaml:"subscribe"`   // Subscribe request, {{topic}} is replaced by the topic and {{apiKey}} by the API key
+	Unsubscribe string   `yaml:"unsubscribe"` // Unsubscribe request, same as subscribe, empty if the source has none
+	MessageType string   `yaml:"messageType"` // Type of the requests: text or binary (default: binary)
+	AuthHeader  string   `yaml:"authHeader"`  // HTTP header carrying the API key when connecting, empty to not send it
+	Timestamp   string   `yaml:"timestamp"`   // Path of the timestamp field of the JSON messages, e.g. "data.0.ts"
+
+	Subprotocols []string `yaml:"subprotocols"` // Websocket subprotocols offered when connecting
 }
 
 // ParseConfig parses the yml configuration file and initialise the Config variable
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"github.com/openmesh-network/core/internal/api"
 	"github.com/openmesh-network/core/internal/bft"
+	"github.com/openmesh-network/core/internal/collector"
 	"github.com/openmesh-network/core/internal/database"
 	"github.com/openmesh-network/core/internal/logger"
 	"github.com/openmesh-network/core/networking/p2p"
@@ -9,9 +11,11 @@ import (
 
 // Instance is the top-level instance
 type Instance struct {
-	pi  *p2p.Instance
-	DB  *database.Instance
-	BFT *bft.Instance
+	pi        *p2p.Instance
+	DB        *database.Instance
+	BFT       *bft.Instance
+	Collector *collector.CollectorInstance
+	API       *api.Instance
 }
 
 // NewInstance initialise an empty top-level instance
@@ -34,6 +38,16 @@ func (i *Instance) SetBFTInstance(bft *bft.Instance) *Instance {
 	return i
 }
 
+func (i *Instance) SetAPIInstance(api *api.Instance) *Instance {
+	i.API = api
+	return i
+}
+
+func (i *Instance) SetCollectorInstance(c *collector.CollectorInstance) *Instance {
+	i.Collector = c
+	return i
+}
+
 // Start the top-level instance as well as all the low-level instances
 func (i *Instance) Start() {
 	err := i.pi.Start()
@@ -41,11 +55,27 @@ func (i *Instance) Start() {
 		logger.Fatalf("Failed to start p2p instance: %s", err.Error())
 	}
 
+	i.Collector.Start()
+	// The collector has to be attached before the node starts voting
+	i.BFT.AttachCollector(i.Collector)
+	i.BFT.AttachP2P(i.pi)
 	i.BFT.Start()
+
+	if i.API != nil {
+		if err := i.API.Start(); err != nil {
+			logger.Fatalf("Failed to start transaction API: %s", err.Error())
+		}
+	}
 }
 
 // Stop the top-level instance as well as all the low-level instances
 func (i *Instance) Stop() {
+	if i.API != nil {
+		if err := i.API.Stop(); err != nil {
+			logger.Errorf("Failed to stop transaction API: %s", err.Error())
+		}
+	}
+
 	if err := i.pi.Stop(); err != nil {
 		logger.Errorf("Failed to stop p2p instance: %s", err.Error())
 	}
@@ -53,4 +83,11 @@ func (i *Instance) Stop() {
 	if err := i.BFT.Stop(); err != nil {
 		logger.Errorf("Failed to stop CometBFT instance: %s", err.Error())
 	}
+
+	i.Collector.Stop()
+
+	// Close the database only after CometBFT stopped, it may still be committing a block or writing a snapshot before that
+	if err := i.DB.Close(); err != nil {
+		logger.Errorf("Failed to close database: %s", err.Error())
+	}
 }
@@ -1,8 +1,12 @@
 package database
 
 import (
+	"encoding/hex"
+	"fmt"
+
 	"github.com/dgraph-io/badger/v3"
-	"github.com/openmesh-network/core/internal/logger"
+	"github.com/dgraph-io/badger/v3/options"
+	"github.com/openmesh-network/core/internal/config"
 )
 
 // Instance is the instance that holds the database connection
@@ -10,13 +14,70 @@ type Instance struct {
 	Conn *badger.DB
 }
 
+// NewInstance opens BadgerDB using the configuration in config.Config.DB.
+// The database is opened in managed mode: the application commits every block at a version derived
+// from its height, so the state of earlier blocks can still be queried. Every version newer than the
+// discard timestamp the application moves along with db.historyBlocks is kept, of the older ones only
+// the latest, so compactions bound the history to that many blocks.
 func NewInstance() (*Instance, error) {
-	i := &Instance{}
-	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true))
+	opts, err := badgerOptions(config.Config.DB)
+	if err != nil {
+		return nil, err
+	}
 
-	i.Conn = db
+	db, err := badger.OpenManaged(opts.WithNumVersionsToKeep(1))
 	if err != nil {
-		logger.Fatalf("Opening database: %v", err)
+		return nil, err
+	}
+	return &Instance{Conn: db}, nil
+}
+
+// Close flushes everything pending to disk and closes the database
+func (i *Instance) Close() error {
+	if i.Conn == nil {
+		return nil
+	}
+	return i.Conn.Close()
+}
+
+// badgerOptions converts the database configuration into BadgerDB options
+func badgerOptions(c config.DBConfig) (badger.Options, error) {
+	if c.InMemory {
+		return badger.DefaultOptions("").WithInMemory(true), nil
 	}
-	return i, nil
+	if c.DataDir == "" {
+		return badger.Options{}, fmt.Errorf("db.dataDir must be set unless db.inMemory is enabled")
+	}
+
+	opts := badger.DefaultOptions(c.DataDir).WithSyncWrites(c.SyncWrites)
+	if c.ValueLogFileSize > 0 {
+		opts = opts.WithValueLogFileSize(c.ValueLogFileSize << 20)
+	}
+
+	switch c.Compression {
+	case "", "snappy":
+		opts = opts.WithCompression(options.Snappy)
+	case "zstd":
+		opts = opts.WithCompression(options.ZSTD)
+	case "none":
+		opts = opts.WithCompression(options.None)
+	default:
+		return badger.Options{}, fmt.Errorf(`unknown compression "%s"`, c.Compression)
+	}
+
+	if c.EncryptionKey != "" {
+		key, err := hex.DecodeString(c.EncryptionKey)
+		if err != nil {
+			return badger.Options{}, fmt.Errorf("invalid encryption key: %w", err)
+		}
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return badger.Options{}, fmt.Errorf("encryption key must be 16, 24 or 32 bytes, got %d", len(key))
+		}
+		// BadgerDB refuses to open an encrypted database without an index cache.
+		opts = opts.WithEncryptionKey(key).WithIndexCacheSize(100 << 20)
+	}
+
+	return opts, nil
 }
@@ -1,2 +1,52 @@
 package database
 
+import (
+	"math"
+	"testing"
+
+	"github.com/openmesh-network/core/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentInstance(t *testing.T) {
+	config.Config.DB = config.DBConfig{
+		DataDir:       t.TempDir(),
+		SyncWrites:    true,
+		Compression:   "zstd",
+		EncryptionKey: "000102030405060708090a0b0c0d0e0f",
+	}
+
+	ins, err := NewInstance()
+	assert.NoError(t, err)
+	txn := ins.Conn.NewTransactionAt(math.MaxUint64, true)
+	assert.NoError(t, txn.Set([]byte("key"), []byte("value")))
+	assert.NoError(t, txn.CommitAt(1, nil))
+	assert.NoError(t, ins.Close())
+
+	// Reopening the same directory must give back what was committed before
+	ins, err = NewInstance()
+	assert.NoError(t, err)
+	defer ins.Close()
+	txn = ins.Conn.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	item, err := txn.Get([]byte("key"))
+	assert.NoError(t, err)
+	val, err := item.ValueCopy(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	_, err := badgerOptions(config.DBConfig{})
+	assert.Error(t, err)
+
+	_, err = badgerOptions(config.DBConfig{DataDir: "/tmp", Compression: "lz4"})
+	assert.Error(t, err)
+
+	_, err = badgerOptions(config.DBConfig{DataDir: "/tmp", EncryptionKey: "0011"})
+	assert.Error(t, err)
+
+	opts, err := badgerOptions(config.DBConfig{InMemory: true, DataDir: "/ignored"})
+	assert.NoError(t, err)
+	assert.True(t, opts.InMemory)
+}
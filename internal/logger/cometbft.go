@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+
+	cmtlog "github.com/cometbft/cometbft/libs/log"
+	"go.uber.org/zap"
+)
+
+// cometBFTLogger implements CometBFT's logger on top of zap, so its logs go to the same outputs as ours
+type cometBFTLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+var _ cmtlog.Logger = (*cometBFTLogger)(nil)
+
+// NewCometBFTLogger returns a CometBFT logger writing to the global zap logger, initialise it via InitLogger() first.
+// Which levels get through is set by the log configuration, CometBFT's log_level can only filter out more.
+func NewCometBFTLogger() cmtlog.Logger {
+	return newCometBFTLogger(zap.L())
+}
+
+func newCometBFTLogger(logger *zap.Logger) *cometBFTLogger {
+	return &cometBFTLogger{sugar: logger.Named("cometbft").Sugar()}
+}
+
+func (l *cometBFTLogger) Debug(msg string, keyvals ...interface{}) {
+	l.sugar.Debugw(msg, fields(keyvals)...)
+}
+
+func (l *cometBFTLogger) Info(msg string, keyvals ...interface{}) {
+	l.sugar.Infow(msg, fields(keyvals)...)
+}
+
+func (l *cometBFTLogger) Error(msg string, keyvals ...interface{}) {
+	l.sugar.Errorw(msg, fields(keyvals)...)
+}
+
+// With keeps the fields, such as CometBFT's module, on every log of the returned logger
+func (l *cometBFTLogger) With(keyvals ...interface{}) cmtlog.Logger {
+	return &cometBFTLogger{sugar: l.sugar.With(fields(keyvals)...)}
+}
+
+// fields turns CometBFT's key value pairs into zap's, formatted the way CometBFT's own logger does:
+// bytes as upper case hex, anything else that can print itself as a string.
+func fields(keyvals []interface{}) []interface{} {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "(MISSING)")
+	}
+	result := make([]interface{}, len(keyvals))
+	for i := 0; i < len(keyvals); i += 2 {
+		result[i] = fmt.Sprint(keyvals[i])
+		switch value := keyvals[i+1].(type) {
+		case []byte:
+			result[i+1] = fmt.Sprintf("%X", value)
+		case error:
+			result[i+1] = value.Error()
+		case fmt.Stringer:
+			result[i+1] = value.String()
+		default:
+			result[i+1] = value
+		}
+	}
+	return result
+}
@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/openmesh-network/core/internal/config"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestInitLogger(t *testing.T) {
@@ -18,3 +21,25 @@ func TestInitLogger(t *testing.T) {
 	err := SyncAll()
 	assert.NoError(t, err)
 }
+
+func TestCometBFTLogger(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := newCometBFTLogger(zap.New(core)).With("module", "consensus")
+
+	log.Debug("Not logged below the configured level")
+	log.Info("Committed block", "height", 5, "hash", []byte{0xab, 0xcd}, "err", errors.New("none"))
+	log.Error("Odd number of values", "peer")
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, zap.InfoLevel, entries[0].Level)
+	assert.Equal(t, "cometbft", entries[0].LoggerName)
+	assert.Equal(t, map[string]interface{}{
+		"module": "consensus",
+		"height": int64(5),
+		"hash":   "ABCD",
+		"err":    "none",
+	}, entries[0].ContextMap())
+	assert.Equal(t, zap.ErrorLevel, entries[1].Level)
+	assert.Equal(t, "(MISSING)", entries[1].ContextMap()["peer"])
+}
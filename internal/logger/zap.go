@@ -105,8 +105,16 @@ func InitLogger() {
     zap.ReplaceGlobals(logger)
 
     // Convert it to a sugared logger to use something like Infof() and Info()
-    sugar := logger.Sugar()
+    assign(logger.Sugar())
+}
+
+// init discards logs until InitLogger is called, so packages logging in tests don't need a logger
+func init() {
+    assign(zap.NewNop().Sugar())
+}
 
+// assign sets the global variables to the methods of a sugared logger
+func assign(sugar *zap.SugaredLogger) {
     // Assign global variables
     // DEBUG level (-1)
     Debug = sugar.Debug
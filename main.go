@@ -2,12 +2,19 @@ package main
 
 import (
 	"context"
-	_ "embed"
+	"embed"
+	"flag"
+	"fmt"
+	"io/fs"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/cometbft/cometbft/rpc/client/local"
+	"github.com/openmesh-network/core/internal/api"
 	"github.com/openmesh-network/core/internal/bft"
+	"github.com/openmesh-network/core/internal/cli"
+	"github.com/openmesh-network/core/internal/collector"
 	"github.com/openmesh-network/core/internal/config"
 	"github.com/openmesh-network/core/internal/core"
 	"github.com/openmesh-network/core/internal/database"
@@ -21,17 +28,11 @@ const (
 )
 
 var (
-	// These are the public keys trusted to sign new updates.
-	TrustedKeys = []updater.PublicKey{
-		// XXX: THESE ARE NOT THE FINAL KEYS, CHANGE BEFORE DEPLOYING TO PRODUCTION!!!
-		updater.PublicKeyFromBase64("HJOvRAmk3tYFvs2uFm+06T6kU9MC2oT+8s1Scwqf224"),
-		// updater.PublicKeyFromBase64("jt1/Mb2xWnd7z6pn21iTb9EU4wycdZhT6Zgb3xf+h6k"),
-		// updater.PublicKeyFromBase64("+8rZEcO928jPGlkn0CZKbXxi11twmZbj9KxxBvTa15Q"),
-		// Fake key
-		// updater.PublicKeyFromBase64("JZlpAGC7aYXIupMUQN48daT/tYRulWiOC0sXFNEXFNE"),
-	}
 	//go:embed config.yml
 	configCompileValue string
+	// A genesis.json put in genesis/ before building is used by nodes with a new home directory.
+	//go:embed genesis
+	genesisFiles embed.FS
 )
 
 func main() {
@@ -40,6 +41,15 @@ func main() {
 	}
 	config.ParseConfig(configCompileValue, allowLoadConfigAtRuntime)
 
+	// Submit a transaction to a running node instead of running one
+	if flag.Arg(0) == "tx" {
+		if err := cli.Tx(flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialise logger after parsing configuration
 	logger.InitLogger()
 	defer logger.SyncAll()
@@ -49,41 +59,108 @@ func main() {
 	defer cancel()
 
 	// Initialise p2p instance.
-	p2pInstance, err := p2p.NewInstance(cancelCtx).Build()
+	p2pInstance, err := p2p.NewInstance(cancelCtx, config.Config.P2P).Build()
 	if err != nil {
 		logger.Fatalf("Failed to initialise p2p instance: %s", err.Error())
 	}
 
+	// A binary started for an upgrade checks it is the one handed over before opening any data
+	updaterInstance, err := updater.NewInstance(p2pInstance, []string{config.Config.BFT.HomeDir, config.Config.DB.DataDir})
+	if err != nil {
+		logger.Fatalf("Failed to take over from the previous binary: %s", err.Error())
+	}
+
 	// Initialise BadgerDB connection
 	dbInstance, err := database.NewInstance()
 	if err != nil {
-		logger.Fatalf("Failed to establish PostgreSQL connection: %s", err.Error())
+		logger.Fatalf("Failed to open BadgerDB: %s", err.Error())
 	}
 
 	// Initialise CometBFT instance
-
+	embeddedGenesis, err := fs.Sub(genesisFiles, "genesis")
+	if err != nil {
+		logger.Fatalf("Failed to read embedded genesis: %s", err.Error())
+	}
+	bft.SetEmbeddedGenesis(embeddedGenesis)
 	bftInstance, err := bft.NewInstance(dbInstance.Conn)
 	if err != nil {
 		logger.Fatalf("Failed to initialise CometBFT instance: %s", err.Error())
 	}
 
-	// Run the updater.
-	// TODO: Maybe pass past CID versions to avoid redownloading old updates.
-	updater.NewInstance(TrustedKeys, p2pInstance).Start(cancelCtx)
+	if err := bftInstance.SetVersion(updaterInstance.Version); err != nil {
+		logger.Fatalf("Failed to read the upgrade plan: %s", err.Error())
+	}
+
+	// Run the updater, the update keys of genesis.json schedule upgrades on chain.
+	updaterInstance.Schedule = bftInstance.UpgradePlan
+	updaterInstance.Start(cancelCtx)
+
+	// Add the sources of the configuration to the built-in ones before the chain assigns any
+	if err := collector.Configure(config.Config.Collector); err != nil {
+		logger.Fatalf("Failed to configure the collector sources: %s", err.Error())
+	}
 
 	// Build and start top-level instance.
 	ins := core.NewInstance().
-		SetP2pInstance(p2pInstance, config.P2pConfig).
+		SetP2pInstance(p2pInstance).
 		SetDBInstance(dbInstance).
-		SetBFTInstance(bftInstance)
+		SetBFTInstance(bftInstance).
+		SetCollectorInstance(collector.New())
+	if addr := config.Config.API.ListenAddr; addr != "" {
+		ins.SetAPIInstance(api.NewInstance(local.New(bftInstance.BftNode), addr))
+	}
 	ins.Start()
 	logger.Infof("Openmesh Core started successfully.")
-	defer ins.Stop()
+
+	// A binary started for an upgrade tells the previous one it runs, which then exits
+	go func() {
+		<-bftInstance.Started()
+		if err := updaterInstance.ConfirmHandover(); err != nil {
+			logger.Errorf("Failed to confirm the upgrade handover: %s", err.Error())
+		}
+	}()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
 
 	// Stop here!
-	sig := <-sigChan
-	logger.Infof("Termination signal received: %v", sig)
+	select {
+	case sig := <-sigChan:
+		logger.Infof("Termination signal received: %v", sig)
+		ins.Stop()
+	case plan := <-bftInstance.UpgradeHalt():
+		upgrade(ins, updaterInstance, plan, sigChan)
+	}
+}
+
+// upgrade stops the node, which committed the block before the activation height of the plan, and hands the data
+// directories over to the binary of the plan. If that binary fails to start, this binary starts again in its place
+// and stays stopped at that height.
+func upgrade(ins *core.Instance, updaterInstance *updater.UpdaterInstance, plan *updater.Plan, sigChan <-chan os.Signal) {
+	logger.Infof("Stopping for the upgrade to %s at height %d", plan.BinaryCid, plan.ActivationHeight)
+	installs := updaterInstance.Installs(plan)
+	var err error
+	if installs {
+		// The binary is usually downloaded already, the download needs the p2p instance
+		err = updaterInstance.Fetch(plan)
+	}
+	ins.Stop()
+
+	if !installs {
+		logger.Errorf("The binary of the upgrade to %s failed to start, install it by hand or restart to try again", plan.BinaryCid)
+		sig := <-sigChan
+		logger.Infof("Termination signal received: %v", sig)
+		return
+	}
+	if err == nil {
+		err = updaterInstance.Handover(plan)
+	}
+	if err != nil {
+		logger.Errorf("Upgrade to %s failed, rolling back: %s", plan.BinaryCid, err.Error())
+		if err := updaterInstance.Rollback(plan); err != nil {
+			logger.Fatalf("Failed to roll back: %s", err.Error())
+		}
+		return
+	}
+	logger.Infof("Handed over to the binary of %s", plan.BinaryCid)
 }
@@ -0,0 +1,243 @@
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openmesh-network/core/internal/logger"
+)
+
+// Environment variables the updater starts a binary with
+const (
+	// HandoverEnv is the path of the Handover record of the upgrade that started the binary
+	HandoverEnv = "OPENMESH_UPGRADE_HANDOVER"
+	// FailedEnv is the CID of the upgrade the previous binary rolled back from, it is not tried again
+	FailedEnv = "OPENMESH_UPGRADE_FAILED"
+)
+
+const (
+	// Binaries of upgrades are written to executablePrefix followed by their CID, and started from there
+	executablePrefix = "executable-"
+	// handoverFile is the Handover record of the upgrade being started, installedFile the one of the last
+	// upgrade whose binary confirmed it runs
+	handoverFile  = "upgrade-handover.json"
+	installedFile = "upgrade-installed.json"
+
+	// How long the binary of an upgrade has to confirm it runs before it is stopped and rolled back
+	handoverTimeout = 2 * time.Minute
+	// How often the record of the installed upgrade is checked for the confirmation
+	confirmInterval = 100 * time.Millisecond
+)
+
+// Handover is what a binary stopped for an upgrade hands to the binary of the upgrade
+type Handover struct {
+	BinaryCid string `json:"binaryCid"`
+	// Hex-encoded SHA-256 of the binary, it checks it is the binary that was handed over
+	Sha256           string `json:"sha256"`
+	ActivationHeight int64  `json:"activationHeight"`
+	// Directories the stopped binary closed, the new binary refuses to run on others
+	DataDirs []string `json:"dataDirs"`
+}
+
+// executablePath returns the path of the binary of this process
+var executablePath = os.Executable
+
+func (u *UpdaterInstance) path(name string) string {
+	return filepath.Join(u.Dir, name)
+}
+
+func readHandover(path string) (*Handover, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	handover := &Handover{}
+	if err := json.Unmarshal(data, handover); err != nil {
+		return nil, fmt.Errorf("invalid upgrade record %s: %w", path, err)
+	}
+	return handover, nil
+}
+
+// writeHandover writes a record atomically, a binary polling it never reads half of it
+func writeHandover(path string, handover *Handover) error {
+	data, err := json.Marshal(handover)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func sha256File(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// loadVersion finds out which binary this process runs. A binary started by a handover must be the binary handed
+// over, on the same data directories. Otherwise the binary is the one of the last installed upgrade if its hash
+// matches, or one no upgrade installed, e.g. the binary started by hand again after an upgrade.
+func (u *UpdaterInstance) loadVersion() error {
+	u.failed = os.Getenv(FailedEnv)
+	executable, err := executablePath()
+	if err != nil {
+		return err
+	}
+	sum, err := sha256File(executable)
+	if err != nil {
+		return err
+	}
+
+	if path := os.Getenv(HandoverEnv); path != "" {
+		handover, err := readHandover(path)
+		if err != nil {
+			return err
+		}
+		if handover.Sha256 != sum {
+			return fmt.Errorf("this binary is not the binary of the upgrade to %s", handover.BinaryCid)
+		}
+		if !slices.Equal(handover.DataDirs, u.DataDirs) {
+			return fmt.Errorf("the data directories %v were handed over, not %v", handover.DataDirs, u.DataDirs)
+		}
+		u.Version, u.handover = handover.BinaryCid, handover
+		return nil
+	}
+
+	installed, err := readHandover(u.path(installedFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if installed.Sha256 == sum {
+		u.Version = installed.BinaryCid
+	}
+	return nil
+}
+
+// ConfirmHandover tells the binary that started this process that the node runs, making this binary the installed
+// version. It does nothing if this process was not started by a handover.
+func (u *UpdaterInstance) ConfirmHandover() error {
+	if u.handover == nil {
+		return nil
+	}
+	return writeHandover(u.path(installedFile), u.handover)
+}
+
+// environ returns the environment of this process with the updater's variables replaced by the given ones
+func environ(variables ...string) []string {
+	env := slices.DeleteFunc(os.Environ(), func(v string) bool {
+		return strings.HasPrefix(v, HandoverEnv+"=") || strings.HasPrefix(v, FailedEnv+"=")
+	})
+	return append(env, variables...)
+}
+
+// startProcess starts a binary with the arguments of this process
+func startProcess(executable string, env []string) (*os.Process, error) {
+	attr := &os.ProcAttr{Env: env, Files: []*os.File{os.Stdin, os.Stdout, os.Stderr}}
+	return os.StartProcess(executable, append([]string{executable}, os.Args[1:]...), attr)
+}
+
+// Handover starts the binary of the plan once this process stopped its node and closed the data directories,
+// and waits for the new binary to confirm it runs. If it exits or does not confirm within handoverTimeout, it is
+// stopped and an error is returned: the caller rolls back then.
+func (u *UpdaterInstance) Handover(plan *Plan) error {
+	u.fetchLock.Lock()
+	if !u.fetchedCid.Equals(plan.BinaryCid) {
+		u.fetchLock.Unlock()
+		return fmt.Errorf("the binary of %s was not downloaded", plan.BinaryCid)
+	}
+	binary := u.fetchedBinary
+	u.fetchLock.Unlock()
+
+	sum := sha256.Sum256(binary)
+	handover := &Handover{
+		BinaryCid:        plan.BinaryCid.String(),
+		Sha256:           hex.EncodeToString(sum[:]),
+		ActivationHeight: plan.ActivationHeight,
+		DataDirs:         u.DataDirs,
+	}
+	// A binary of its own for every version, the running one can't be overwritten
+	executable, err := filepath.Abs(u.path(executablePrefix + handover.BinaryCid))
+	if err != nil {
+		return err
+	}
+	// XXX: Think about more sensible permissions maybe. Shouldn't matter if it's running in a container 🤷.
+	if err := os.WriteFile(executable, binary, 0o777); err != nil {
+		return err
+	}
+	path, err := filepath.Abs(u.path(handoverFile))
+	if err != nil {
+		return err
+	}
+	if err := writeHandover(path, handover); err != nil {
+		return err
+	}
+
+	// A record of the same binary is of an earlier handover, only the new binary may confirm this one
+	if installed, err := readHandover(u.path(installedFile)); err == nil && installed.BinaryCid == handover.BinaryCid {
+		if err := os.Remove(u.path(installedFile)); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Handing over to %s", executable)
+	process, err := startProcess(executable, environ(HandoverEnv+"="+path))
+	if err != nil {
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		process.Wait()
+		close(exited)
+	}()
+	confirmed := func() bool {
+		installed, err := readHandover(u.path(installedFile))
+		return err == nil && installed.BinaryCid == handover.BinaryCid && installed.Sha256 == handover.Sha256
+	}
+
+	ticker := time.NewTicker(confirmInterval)
+	defer ticker.Stop()
+	timeout := time.After(handoverTimeout)
+	for {
+		select {
+		case <-ticker.C:
+			if confirmed() {
+				return nil
+			}
+		case <-exited:
+			if confirmed() {
+				return nil
+			}
+			return fmt.Errorf("the binary of %s exited before it confirmed the handover", handover.BinaryCid)
+		case <-timeout:
+			process.Kill()
+			<-exited
+			return fmt.Errorf("the binary of %s did not confirm the handover within %s", handover.BinaryCid, handoverTimeout)
+		}
+	}
+}
+
+// Rollback starts this binary again after the binary of the plan failed to start. It takes the data directories
+// back and stays stopped at the height before the activation height, without trying the plan again.
+func (u *UpdaterInstance) Rollback(plan *Plan) error {
+	executable, err := executablePath()
+	if err != nil {
+		return err
+	}
+	_, err = startProcess(executable, environ(FailedEnv+"="+plan.BinaryCid.String()))
+	return err
+}
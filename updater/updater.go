@@ -3,13 +3,9 @@ package updater
 import (
 	"bytes"
 	"context"
-	"crypto/ed25519"
 	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base64"
-	"encoding/gob"
 	"fmt"
-	"os"
+	"sync"
 	"time"
 
 	"io"
@@ -36,34 +32,36 @@ import (
 	"github.com/ipfs/boxo/files"
 )
 
-type PublicKey [ed25519.PublicKeySize]byte
-type Signature [ed25519.SignatureSize]byte
-
-type UpdateRequest struct {
-	PublicKey PublicKey
-	Signature Signature
-
-	Content UpdateRequestContent
+// Plan is a version of the binary the chain scheduled, see bft.Instance.UpgradePlan
+type Plan struct {
+	BinaryCid        cid.Cid
+	ActivationHeight int64
 }
 
-type UpdateRequestContent struct {
-	Nonce     int64
-	BinaryCid []byte
-}
+// How often the chain is checked for a scheduled upgrade.
+const pollInterval = time.Second
 
 type UpdaterInstance struct {
-	TrustedKeys []PublicKey
-
-	LatestVerifiedRequests []UpdateRequest
-	P2pInstance            *p2p.Instance
-	// CurrentVersion         [32]byte // TODO: this should be passed as an argv to child process
-}
-
-func HashRequestContent(content UpdateRequestContent) []byte {
-	h := sha256.New()
-	e := gob.NewEncoder(h)
-	e.Encode(content)
-	return h.Sum(nil)
+	// Schedule returns the upgrade the chain scheduled, nil if there is none, and the height of the last committed block.
+	// Update keys approve upgrades on chain, so every node reads the same plan.
+	Schedule func() (plan *Plan, height int64)
+
+	P2pInstance *p2p.Instance
+	// Directory the binaries of upgrades and the records of the updater are kept in, the working directory if empty.
+	Dir string
+	// Directories handed over to the binary of an upgrade: the CometBFT home and the application state.
+	DataDirs []string
+	// CID of the binary this process runs, empty if no upgrade installed it. See loadVersion.
+	Version string
+
+	// Handover that started this process, confirmed once the node runs, and the upgrade that failed to start before.
+	handover *Handover
+	failed   string
+
+	// Binary of the scheduled upgrade, downloaded ahead of its activation height.
+	fetchLock     sync.Mutex
+	fetchedCid    cid.Cid
+	fetchedBinary []byte
 }
 
 var numbers = []int{
@@ -79,77 +77,35 @@ func (u *UpdaterInstance) testFunc() {
 	fmt.Println("I love this!")
 }
 
-func PublicKeyFromBase64(base64KeyString string) PublicKey {
-	key_bytes, err := base64.RawStdEncoding.DecodeString(base64KeyString)
-
-	if err != nil {
-		panic(err)
-	}
-
-	publicKey := PublicKey{}
-
-	for i := range key_bytes {
-		publicKey[i] = key_bytes[i]
-	}
-
-	return publicKey
-}
-
-func NewInstance(trustedKeys []PublicKey, p2pInstance *p2p.Instance) *UpdaterInstance {
+// NewInstance returns an updater for the data directories. It fails if this process was started by a handover
+// with another binary or other data directories than the ones handed over, the previous binary rolls back then.
+func NewInstance(p2pInstance *p2p.Instance, dataDirs []string) (*UpdaterInstance, error) {
 	updater := UpdaterInstance{}
-	updater.TrustedKeys = trustedKeys
-	updater.LatestVerifiedRequests = make([]UpdateRequest, len(updater.TrustedKeys))
 	updater.P2pInstance = p2pInstance
+	updater.DataDirs = dataDirs
+	if err := updater.loadVersion(); err != nil {
+		return nil, err
+	}
 
-	return &updater
+	return &updater, nil
 }
 
-func (u *UpdaterInstance) VerifyRequest(req UpdateRequest) bool {
-	// This matches the request's key to the list of public keys.
-	trustedIndex := -1
-	for i, tk := range u.TrustedKeys {
-		if bytes.Equal(tk[:], req.PublicKey[:]) {
-			trustedIndex = i
-			break
-		}
+// nextPlan returns the scheduled upgrade to another binary, nil if there is none or if its binary failed to start
+func (u *UpdaterInstance) nextPlan() *Plan {
+	if u.Schedule == nil {
+		return nil
 	}
-
-	// NOTE: Harry maybe uncomment this for debugging.
-	// fmt.Println("Trusted index: ", trustedIndex)
-
-	if trustedIndex >= 0 {
-		// Verify hash and signature independently.
-		hashBytes := HashRequestContent(req.Content)
-		verified := ed25519.Verify(req.PublicKey[:], hashBytes, req.Signature[:])
-
-		if verified {
-			// NOTE: Harry maybe uncomment this for debugging.
-			// fmt.Println("Trusted index: ", trustedIndex)
-			// fmt.Println("LatestVerifiedRequests: ", u.LatestVerifiedRequests)
-
-			// Verified requests might be empty.
-			if u.LatestVerifiedRequests[trustedIndex].Content.Nonce < req.Content.Nonce {
-				// This means this verified message is newer so it should take the place of the old one in the queue.
-				u.LatestVerifiedRequests[trustedIndex] = req
-				return true
-			} else {
-				// NOT VERIFIED
-				// TODO:Handle case where outdated message is still being received
-				fmt.Println("Outdated message. Nonce local ", u.LatestVerifiedRequests[trustedIndex].Content.Nonce, req.Content.Nonce)
-				return false
-			}
-		} else {
-			// NOT VERIFIED
-			// TODO:Handle case where public key is right but signature is wrong
-			fmt.Println("Public key is right, signature incorrect.")
-			return false
-		}
-	} else {
-		// NOT VERIFIED
-		// TODO: Handle case where someone sent a message with unknown receiver
-		fmt.Println("Message with unknown receiver.")
-		return false
+	plan, _ := u.Schedule()
+	if plan == nil || !u.Installs(plan) {
+		return nil
 	}
+	return plan
+}
+
+// Installs returns whether this process upgrades to the plan once the chain stops for it. It doesn't if it runs the
+// binary of the plan already, or if that binary failed to start before.
+func (u *UpdaterInstance) Installs(plan *Plan) bool {
+	return plan.BinaryCid.String() != u.Version && plan.BinaryCid.String() != u.failed
 }
 
 // TODO: Move this somewhere more appropriate
@@ -172,99 +128,17 @@ func ConnectToMultiaddr(ctx context.Context, h host.Host, targetPeer string) err
 	return nil
 }
 
-func (u *UpdaterInstance) UpdateIfAppropriate(h host.Host) bool {
-	isVerified := false
-	c := cid.Cid{}
-
-	{ // Check if I should update.
-		// RULE: the majority of the trusted keys backup a new version number.
-
-		cidIndices := []int32{}
-		{ // Gives a list of all unique CIDs.
-			for ci, r := range u.LatestVerifiedRequests {
-				// Nonce 0 is not a valid request.
-				if r.Content.Nonce > 0 {
-					// Only proceed if the CID is valid!
-					_, parseError := cid.Parse(r.Content.BinaryCid)
-					if parseError == nil {
-						cidIndex := int32(ci)
-
-						unique := true
-						for _, i := range cidIndices {
-							// Again, nonce 0 is invalid.
-							if u.LatestVerifiedRequests[i].Content.Nonce > 0 {
-								if cidIndex != i && bytes.Equal(r.Content.BinaryCid, u.LatestVerifiedRequests[i].Content.BinaryCid) {
-									unique = false
-								}
-							}
-						}
-
-						if unique {
-							cidIndices = append(cidIndices, cidIndex)
-						}
-					}
-				}
-			}
-		}
-
-		fmt.Println("Cid indices", cidIndices)
-		fmt.Println("Latest verified requests", len(u.LatestVerifiedRequests))
-
-		if len(cidIndices) > 0 {
-			mostPopularCidIndex := cidIndices[0]
-			highestTally := 0
-			{
-				for _, v := range cidIndices {
-					tally := 0
-					for _, r := range u.LatestVerifiedRequests {
-						if bytes.Equal(r.Content.BinaryCid, u.LatestVerifiedRequests[v].Content.BinaryCid) {
-							tally++
-							fmt.Println("This runs twice somehow.")
-						}
-					}
-
-					if tally > highestTally {
-						mostPopularCidIndex = v
-						highestTally = tally
-					}
-				}
-			}
-
-			// XXX: Make 100% sure this logic makes sense:
-			// Want #ValidSignatures >= #TotalKeys * 2/3 to be true for 66% concensus.
-			// So it simplifies to 3 * #ValidSignatures >= 2 * #TotalKeys.
-			over66PercentThreshold := len(u.TrustedKeys)*2 <= highestTally*3
-			if over66PercentThreshold {
-				var err error
-				c, err = cid.Cast(u.LatestVerifiedRequests[mostPopularCidIndex].Content.BinaryCid)
-
-				if err != nil {
-					// We don't trust valid signatures with invalid CIDs.
-					// Though this should never run in practice since we check this earlier.
-					isVerified = false
-					fmt.Println("Invalid CID.")
-					fmt.Println(err)
-				} else {
-					isVerified = true
-				}
-			} else {
-				// No one has a majority.
-				fmt.Println("No CID has a majority of keys.")
-				isVerified = false
-			}
-		} else {
-			fmt.Println("No CIDs.")
-			isVerified = false
-		}
-	}
+// Fetch downloads the binary of the plan, unless it was already. It needs the p2p instance.
+func (u *UpdaterInstance) Fetch(plan *Plan) error {
+	return u.fetch(*u.P2pInstance.Host, plan.BinaryCid)
+}
 
-	if !isVerified {
-		fmt.Println("Not verified didn't reach consensus.")
-		return false
-	}
+func (u *UpdaterInstance) fetch(h host.Host, c cid.Cid) error {
+	u.fetchLock.Lock()
+	defer u.fetchLock.Unlock()
 
-	fmt.Println("Downloading cid...")
-	{ // Download the cid.
+	if !u.fetchedCid.Equals(c) { // Download the cid.
+		fmt.Println("Downloading cid...")
 		// TODO: Maybe take ctx as a parameter.
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -323,25 +197,11 @@ func (u *UpdaterInstance) UpdateIfAppropriate(h host.Host) bool {
 		buf, err := getFile(h, c)
 
 		if err != nil {
-			fmt.Println(err)
-			return false
-		} else {
-			fmt.Println("Everything has passed")
-
-			// XXX: Think about more sensible permissions maybe. Shouldn't matter if it's running in a container 🤷.
-			os.WriteFile("executable-file", buf, 0o777)
-
-			var attr os.ProcAttr
-			_, err := os.StartProcess("executable-file", []string{"executable-file", "wazzup"}, &attr)
-
-			if err != nil {
-				fmt.Println("error launching new process: ", err)
-				return false
-			} else {
-				return true
-			}
+			return err
 		}
+		u.fetchedCid, u.fetchedBinary = c, buf
 	}
+	return nil
 }
 
 // Used for debugging, will panic on err.
@@ -374,52 +234,23 @@ func HostToString(h host.Host) string {
 	return addr.Encapsulate(hostAddr).String()
 }
 
+// Start downloads the binary of the scheduled upgrade as soon as the chain schedules it
 func (updater *UpdaterInstance) Start(ctx context.Context) {
 	fmt.Println("Updater listening on address: ", HostToString(*updater.P2pInstance.Host))
 
-	// TODO: This needs to get the file from an actual IPFS network
-	err := updater.P2pInstance.JoinTopic("openmesh-core-update")
-	if err != nil {
-		// HACK: Should handle this sensibly.
-		panic(err)
-	}
-
-	subscription, err := updater.P2pInstance.Subscribe("openmesh-core-update")
-	if err != nil {
-		// HACK: Should handle this sensibly.
-		panic(err)
-	}
-
 	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
-				break
-			case message := <-subscription:
-				fmt.Println("Got message.")
-
-				if err != nil {
-					fmt.Println(err)
-				}
-				fmt.Println(message)
-
-				var request_buffer bytes.Buffer
-				request_buffer.Write(message.Data)
-				decoder := gob.NewDecoder(&request_buffer)
-
-				var request UpdateRequest
-				decoder.Decode(&request)
-
-				fmt.Println(message.Data)
-				fmt.Println(request)
-
-				// TODO: Handle misbehaving nodes here.
-				if !updater.VerifyRequest(request) {
-					// TODO: I think I can disconnect from this peer here. Maybe assign social credit score or something?
-				}
-				if updater.UpdateIfAppropriate(*updater.P2pInstance.Host) {
-					fmt.Println("Success, spawned child process! Updater is finished.")
-					os.Exit(0)
+				return
+			case <-ticker.C:
+				// The binary is downloaded ahead, the chain stops for the upgrade at its activation height
+				if plan := updater.nextPlan(); plan != nil {
+					if err := updater.Fetch(plan); err != nil {
+						fmt.Println(err)
+					}
 				}
 			}
 		}
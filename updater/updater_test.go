@@ -1,14 +1,11 @@
 package updater
 
 import (
-	"bytes"
 	"context"
-	"crypto"
-	"crypto/ed25519"
-	crand "crypto/rand"
 	"fmt"
-	"log"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -16,15 +13,93 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestHashWorks(t *testing.T) {
-	var content UpdateRequestContent
-	content.BinaryCid = []byte("hello")
-	content.Nonce = 0
+func TestNextPlan(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := cid.Parse("QmTytvFFrGE69EWw7f3bbJhzUb3F7WTsNcPnFRH6xULUJW")
+	assert.NoError(err)
+	var updater UpdaterInstance
+	assert.Nil(updater.nextPlan())
+
+	scheduled := &Plan{BinaryCid: c, ActivationHeight: 20}
+	updater.Schedule = func() (*Plan, int64) { return scheduled, 10 }
+	assert.Equal(scheduled, updater.nextPlan())
+
+	// Nothing to install when running the binary already, or when it failed to start
+	updater.Version = c.String()
+	assert.Nil(updater.nextPlan())
+	updater.Version, updater.failed = "", c.String()
+	assert.Nil(updater.nextPlan())
+	assert.False(updater.Installs(scheduled))
+}
+
+// writeExecutable writes a shell script and returns its path
+func writeExecutable(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "binary")
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o777))
+	return path
+}
 
-	// fmt.Println(HashRequestContent(content))
+func TestHandover(t *testing.T) {
+	assert := assert.New(t)
 
-	content.BinaryCid = []byte("lolol")
-	// fmt.Println(HashRequestContent(content))
+	c, err := cid.Parse("QmTytvFFrGE69EWw7f3bbJhzUb3F7WTsNcPnFRH6xULUJW")
+	assert.NoError(err)
+	other, err := cid.Parse("bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy")
+	assert.NoError(err)
+	plan := &Plan{BinaryCid: c, ActivationHeight: 20}
+	dataDirs := []string{"cometbft", "badger"}
+	updater := &UpdaterInstance{Dir: t.TempDir(), DataDirs: dataDirs}
+	assert.ErrorContains(updater.Handover(plan), "not downloaded")
+
+	// The new binary confirms by recording the handover as installed
+	confirming := "cp \"$" + HandoverEnv + "\" \"$(dirname \"$" + HandoverEnv + "\")/" + installedFile + "\"\n"
+	updater.fetchedCid, updater.fetchedBinary = c, []byte("#!/bin/sh\n"+confirming)
+	assert.NoError(updater.Handover(plan))
+	installed, err := readHandover(filepath.Join(updater.Dir, installedFile))
+	assert.NoError(err)
+	assert.Equal(c.String(), installed.BinaryCid)
+	assert.Equal(dataDirs, installed.DataDirs)
+
+	// A binary that fails to start is rolled back, an earlier record of it does not count as a confirmation
+	assert.NoError(writeHandover(filepath.Join(updater.Dir, installedFile), &Handover{BinaryCid: other.String()}))
+	updater.fetchedCid, updater.fetchedBinary = other, []byte("#!/bin/sh\nexit 1\n")
+	assert.ErrorContains(updater.Handover(&Plan{BinaryCid: other, ActivationHeight: 40}), "exited before")
+	_, err = readHandover(filepath.Join(updater.Dir, installedFile))
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	// The binary handed over runs on the same data directories only
+	handedOver := writeExecutable(t, confirming)
+	executablePath = func() (string, error) { return handedOver, nil }
+	defer func() { executablePath = os.Executable }()
+	sum, err := sha256File(handedOver)
+	assert.NoError(err)
+	record := filepath.Join(updater.Dir, handoverFile)
+	assert.NoError(writeHandover(record, &Handover{BinaryCid: c.String(), Sha256: sum, DataDirs: dataDirs}))
+	t.Setenv(HandoverEnv, record)
+
+	started, err := NewInstance(nil, []string{"cometbft", "elsewhere"})
+	assert.ErrorContains(err, "data directories")
+	assert.Nil(started)
+	started, err = NewInstance(nil, dataDirs)
+	assert.NoError(err)
+	assert.Equal(c.String(), started.Version)
+	started.Dir = updater.Dir
+	assert.NoError(started.ConfirmHandover())
+
+	// Another binary is refused
+	executablePath = func() (string, error) { return writeExecutable(t, "exit 0\n"), nil }
+	_, err = NewInstance(nil, dataDirs)
+	assert.ErrorContains(err, "not the binary")
+
+	// Started by hand, the installed binary knows its version, other binaries don't
+	t.Setenv(HandoverEnv, "")
+	started = &UpdaterInstance{Dir: updater.Dir}
+	assert.NoError(started.loadVersion())
+	assert.Empty(started.Version)
+	executablePath = func() (string, error) { return handedOver, nil }
+	assert.NoError(started.loadVersion())
+	assert.Equal(c.String(), started.Version)
 }
 
 func TestEndToEnd(t *testing.T) {
@@ -64,79 +139,16 @@ func TestEndToEnd(t *testing.T) {
 		}
 	}()
 
-	// I use uint8 and not boolean to avoid writing "true" and "false" a billion times later on.
-	// keyIsTrusted are the stores whether the key is trusted or not, and keysIsMakingARequest stores whether the key will make a request.
-	runTestWithKeys := func(keyIsTrusted []uint8, keyIsMakingARequest []uint8) bool {
-		assert.True(len(keyIsTrusted) == len(keyIsMakingARequest), "Wrong argument for test.")
-
-		trustedKeyCount := 0
-		for i := range keyIsTrusted {
-			if keyIsTrusted[i] == 1 {
-				trustedKeyCount++
-			}
-		}
-
-		var updater UpdaterInstance
-		updater.TrustedKeys = make([]PublicKey, 0, len(keyIsTrusted))
-		updater.LatestVerifiedRequests = make([]UpdateRequest, trustedKeyCount)
-
-		publicKeys := make([]ed25519.PublicKey, len(keyIsTrusted))
-		privateKeys := make([]ed25519.PrivateKey, len(keyIsTrusted))
-
-		for i := range publicKeys {
-			var err error
-			publicKeys[i], privateKeys[i], err = ed25519.GenerateKey(crand.Reader)
-
-			if keyIsTrusted[i] == 1 {
-				updater.TrustedKeys = append(updater.TrustedKeys, PublicKey(publicKeys[i]))
-			}
-
-			if err != nil {
-				log.Panicln(err)
-			}
-
-			// This is the CID of the test-script.sh file.
-			c, err := cid.Parse("QmTytvFFrGE69EWw7f3bbJhzUb3F7WTsNcPnFRH6xULUJW")
-			if err != nil {
-				t.Error(err)
-				t.FailNow()
-			}
-
-			var req UpdateRequest
-
-			{ // Build a request.
-
-				req.PublicKey = PublicKey(publicKeys[i])
-				req.Content.BinaryCid = c.Bytes()
-				req.Content.Nonce = 1
-
-				hash := HashRequestContent(req.Content)
-
-				signature, err := privateKeys[i].Sign(crand.Reader, hash, crypto.Hash(0))
-
-				if err != nil {
-					t.Error(err)
-					t.FailNow()
-				}
-
-				for i := range signature {
-					req.Signature[i] = signature[i]
-				}
-
-				assert.True(bytes.Equal(signature, req.Signature[:]))
-			}
+	// This is the CID of the test-script.sh file.
+	c, err := cid.Parse("QmTytvFFrGE69EWw7f3bbJhzUb3F7WTsNcPnFRH6xULUJW")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
 
-			// Send the request and see what happens!
-			if keyIsMakingARequest[i] == 1 {
-				result := updater.VerifyRequest(req)
-
-				if result {
-					assert.True(keyIsTrusted[i] == 1, "Request verified with untrusted key!")
-				} else {
-					assert.True(keyIsTrusted[i] != 1, "Request unverified with trusted key!")
-				}
-			}
-		}
+	// runTestWithPlan downloads the binary of the plan and hands over to it.
+	runTestWithPlan := func(plan *Plan) error {
+		updater := UpdaterInstance{Dir: t.TempDir()}
 
 		// TODO: Fix this test!
 		// "/ip4/10.0.17.23/tcp/4001/p2p/12D3KooWPnX64ZDrYZof4cyJhAA9NK2Yxygs5C1uCS2zg5x1PbHL"
@@ -154,50 +166,12 @@ func TestEndToEnd(t *testing.T) {
 			panic(err)
 		}
 
-		return updater.UpdateIfAppropriate(host)
+		if err := updater.fetch(host, plan.BinaryCid); err != nil {
+			return err
+		}
+		return updater.Handover(plan)
 	}
 
-	assert.True(runTestWithKeys([]uint8{1}, []uint8{1}))
-	assert.True(runTestWithKeys([]uint8{1, 1}, []uint8{1, 1}))
-
-	assert.True(runTestWithKeys([]uint8{1, 1, 0}, []uint8{1, 1, 0}))
-	assert.True(runTestWithKeys([]uint8{0, 1, 1}, []uint8{0, 1, 1}))
-	assert.True(runTestWithKeys([]uint8{1, 0, 1}, []uint8{1, 0, 1}))
-
-	// These seem like false positives, but the logic is sound. If there's one trusted key and one signature then it's correct
-	assert.True(runTestWithKeys([]uint8{1, 0, 0}, []uint8{1, 1, 1}))
-	assert.True(runTestWithKeys([]uint8{0, 1, 0}, []uint8{1, 1, 1}))
-	assert.True(runTestWithKeys([]uint8{0, 1, 0}, []uint8{1, 1, 1}))
-
-	assert.False(runTestWithKeys([]uint8{1, 1}, []uint8{1, 0}))
-	assert.False(runTestWithKeys([]uint8{0, 0}, []uint8{1, 0}))
-	assert.False(runTestWithKeys([]uint8{0, 0}, []uint8{1, 1}))
-	assert.False(runTestWithKeys([]uint8{0, 0}, []uint8{0, 0}))
-
-	assert.False(runTestWithKeys([]uint8{1, 1, 0}, []uint8{0, 1, 0})) // 1 / 2
-	assert.False(runTestWithKeys([]uint8{0, 1, 1}, []uint8{0, 1, 0})) // 1 / 2
-	assert.False(runTestWithKeys([]uint8{1, 0, 1}, []uint8{1, 0, 0})) // 1 / 2
-
-	assert.True(runTestWithKeys(
-		[]uint8{1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		[]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
-
-	assert.False(runTestWithKeys(
-		[]uint8{1, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		[]uint8{0, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
-	assert.False(runTestWithKeys(
-		[]uint8{0, 1, 0, 0, 0, 0, 0, 0, 0, 0},
-		[]uint8{1, 0, 1, 1, 1, 1, 1, 1, 1, 1}))
-	assert.False(runTestWithKeys(
-		[]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		[]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1, 0}))
-	assert.False(runTestWithKeys(
-		[]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		[]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1, 0}))
-	assert.False(runTestWithKeys(
-		[]uint8{0, 0, 0, 1, 0, 0, 0, 0, 0, 1},
-		[]uint8{1, 1, 1, 1, 1, 1, 1, 1, 1, 0}))
-	assert.False(runTestWithKeys(
-		[]uint8{1, 0, 0, 1, 0, 0, 0, 0, 0, 1},
-		[]uint8{0, 0, 1, 1, 1, 1, 1, 1, 1, 0}))
+	// The test script doesn't confirm the handover, so it is rolled back
+	assert.ErrorContains(runTestWithPlan(&Plan{BinaryCid: c, ActivationHeight: 20}), "exited before")
 }